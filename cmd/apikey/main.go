@@ -0,0 +1,147 @@
+// Command apikey manages the API keys clients use to call the REST API.
+//
+//	apikey create -name "warehouse scanner" -role operator
+//	apikey list
+//	apikey role -id 3 -role admin
+//	apikey revoke -id 3
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+	"walmart-inventory-manager/internal/config"
+	"walmart-inventory-manager/internal/db"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/repositories/apikey"
+	authService "walmart-inventory-manager/internal/service/auth"
+)
+
+const usage = `usage: apikey <command> [flags]
+
+commands:
+  create -name NAME [-role ROLE]   create a key and print it once
+  list                             list keys
+  role -id ID -role ROLE           change the role of a key
+  revoke -id ID                    revoke a key
+
+roles: viewer (read), operator (read and write), admin (everything)
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// Settings come from the config file, the environment and .env; the
+	// command's own flags follow the subcommand.
+	cfg, err := config.Load(nil)
+	if err != nil {
+		log.Fatalf("Error loading configuration:\n%v", err)
+	}
+
+	conn, err := db.ConnectDB(cfg)
+	if err != nil {
+		log.Fatalf("Error connecting to the database: %v", err)
+	}
+	defer conn.Close()
+
+	sv := authService.NewAuthDefault(apikey.NewAPIKeyRepository(conn), entities.JWTPolicy{},
+		logging.New(logging.Config{Level: cfg.LogLevel, Format: "text"}, os.Stderr))
+
+	switch os.Args[1] {
+	case "create":
+		err = create(sv, os.Args[2:])
+	case "list":
+		err = list(sv)
+	case "role":
+		err = setRole(sv, os.Args[2:])
+	case "revoke":
+		err = revoke(sv, os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func create(sv authService.AuthService, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ExitOnError)
+	name := fs.String("name", "", "who or what the key is for")
+	role := fs.String("role", string(entities.RoleViewer), "viewer, operator or admin")
+	fs.Parse(args)
+
+	key, plain, err := sv.CreateKey(*name, entities.Role(*role))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s API key %d (%s)\n", key.Role, key.ID, key.Name)
+	fmt.Printf("\n  %s\n\n", plain)
+	fmt.Println("Store it now; it cannot be shown again.")
+	return nil
+}
+
+func list(sv authService.AuthService) error {
+	keys, err := sv.Keys()
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tROLE\tPREFIX\tCREATED\tLAST USED\tSTATUS")
+	for _, key := range keys {
+		status := "active"
+		if !key.Active() {
+			status = "revoked " + key.RevokedAt.Format(time.DateTime)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s…\t%s\t%s\t%s\n", key.ID, key.Name, key.Role, key.Prefix, key.CreatedAt.Format(time.DateTime), formatTime(key.LastUsedAt), status)
+	}
+	return tw.Flush()
+}
+
+func setRole(sv authService.AuthService, args []string) error {
+	fs := flag.NewFlagSet("role", flag.ExitOnError)
+	id := fs.Int64("id", 0, "ID of the key to change")
+	role := fs.String("role", "", "viewer, operator or admin")
+	fs.Parse(args)
+
+	key, err := sv.SetKeyRole(*id, entities.Role(*role))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("API key %d (%s) is now %s\n", key.ID, key.Name, key.Role)
+	return nil
+}
+
+func revoke(sv authService.AuthService, args []string) error {
+	fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+	id := fs.Int64("id", 0, "ID of the key to revoke")
+	fs.Parse(args)
+
+	key, err := sv.RevokeKey(*id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked API key %d (%s)\n", key.ID, key.Name)
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format(time.DateTime)
+}
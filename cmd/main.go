@@ -1,20 +1,36 @@
 package main
 
 import (
-	"fmt"
+	"errors"
+	"flag"
 	"log"
+	"log/slog"
+	"os"
 	"walmart-inventory-manager/internal/application"
-
-	"github.com/joho/godotenv"
+	"walmart-inventory-manager/internal/config"
 )
 
 func main() {
-	err := godotenv.Load("C:/Users/User/Desktop/walmart-inventory-manager/.env")
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("Error loading .env file: %v", err)
+		log.Fatalf("Error loading configuration:\n%v", err)
+	}
+
+	if cfg.PrintConfig {
+		if err := cfg.Dump(os.Stdout); err != nil {
+			log.Fatalf("Error printing configuration: %v", err)
+		}
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
 	}
 
-	a, err := application.NewApplication()
+	a, err := application.NewApplication(cfg)
 	if err != nil {
 		log.Fatalf("Error initializing application: %v", err)
 	}
@@ -31,6 +47,6 @@ func main() {
 
 	err = a.TearDown()
 	if err != nil {
-		fmt.Printf("Error tearing down application: %v\n", err)
+		slog.Error("Error tearing down application", slog.String("error", err.Error()))
 	}
 }
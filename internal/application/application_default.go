@@ -1,12 +1,18 @@
 package application
 
 import (
-	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
+	"time"
+	"walmart-inventory-manager/internal/config"
+	"walmart-inventory-manager/internal/entities"
 	"walmart-inventory-manager/internal/infrastructure/dependencies"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/metrics"
+	"walmart-inventory-manager/internal/middleware"
 	"walmart-inventory-manager/internal/walmart"
 	"walmart-inventory-manager/platform/web"
+	webMiddleware "walmart-inventory-manager/platform/web/middleware"
 )
 
 type applicationDefault struct {
@@ -15,8 +21,8 @@ type applicationDefault struct {
 	deps *dependencies.HandlerContainer
 }
 
-func NewApplication() (Application, error) {
-	deps := dependencies.Start()
+func NewApplication(cfg *config.Config) (Application, error) {
+	deps := dependencies.Start(cfg)
 
 	return &applicationDefault{
 		r:    web.NewRouter(),
@@ -25,33 +31,241 @@ func NewApplication() (Application, error) {
 }
 
 func (a *applicationDefault) Run() (err error) {
-	fmt.Println("Server running on http://localhost:8081")
-	return http.ListenAndServe(":8081", a.r)
+	cfg := a.deps.Config
+	server := &http.Server{
+		Addr:         cfg.ServerAddress,
+		Handler:      a.r,
+		ReadTimeout:  time.Duration(cfg.ServerReadTimeoutSeconds) * time.Second,
+		WriteTimeout: time.Duration(cfg.ServerWriteTimeoutSeconds) * time.Second,
+		IdleTimeout:  time.Duration(cfg.ServerIdleTimeoutSeconds) * time.Second,
+	}
+
+	a.deps.Logger.Info("Server running", slog.String("addr", cfg.ServerAddress))
+	return server.ListenAndServe()
 }
 
 func (a *applicationDefault) SetUp() (err error) {
-	serverAddress := os.Getenv("SERVER_ADDRESS")
-	if serverAddress == "" {
-		serverAddress = "localhost:8081"
-	}
-
 	a.setUpRoutes()
-	walmart.StartCronJob(a.deps.WalmartClient, a.deps.InventoryRepository)
-	walmart.OrdersCronjob(a.deps.WalmartClient, a.deps.InventoryRepository)
+	walmart.StartCronJob(a.deps.WalmartClient, a.deps.InventoryRepository, a.deps.StockRepository, a.deps.ListingRepository, a.deps.DelistPolicy, a.deps.CatalogSyncSchedule, a.deps.Logger, a.deps.Health, a.reconcile, a.evaluateAlerts)
+	walmart.OrdersCronjob(a.deps.WalmartClient, a.deps.SalesRepository, a.deps.OrdersSyncSchedule, a.deps.Logger, a.deps.Health, a.refreshForecasts)
+	walmart.ImageBackfillJob(a.deps.Images, a.deps.ImageBackfillPolicy.Interval, a.deps.Logger, a.deps.Health)
 
 	return nil
 }
 
+// reconcile runs the stock reconciliation once a catalog sync has finished.
+func (a *applicationDefault) reconcile(runID string) {
+	logger := a.deps.Logger.With(slog.String(logging.KeyJob, "reconciliation"), slog.String(logging.KeyRunID, runID))
+
+	run, err := a.deps.Reconciliation.Reconcile(runID)
+	if err != nil {
+		logger.Error("Stock reconciliation failed", logging.Err(err))
+		return
+	}
+	logger.Info("Stock reconciliation finished",
+		slog.Int("discrepancies", run.DiscrepancyCount),
+		slog.Int("skus", run.TotalSKUs),
+	)
+}
+
+// refreshForecasts makes the forecasts pick up the sales an orders sync stored.
+func (a *applicationDefault) refreshForecasts(runID string) {
+	a.deps.Forecasts.Refresh()
+}
+
+// evaluateAlerts checks the alert rules once a catalog sync has finished.
+func (a *applicationDefault) evaluateAlerts(runID string) {
+	logger := a.deps.Logger.With(slog.String(logging.KeyJob, "alert_evaluation"), slog.String(logging.KeyRunID, runID))
+
+	result, err := a.deps.Alerts.Evaluate(runID)
+	if err != nil {
+		logger.Error("Alert evaluation failed", logging.Err(err))
+		return
+	}
+	logger.Info("Alert evaluation finished",
+		slog.Int("raised", result.Raised),
+		slog.Int("notified", result.Notified),
+		slog.Int("resolved", result.Resolved),
+	)
+}
+
 func (a *applicationDefault) TearDown() (err error) {
 	return nil
 }
 
+// httpMiddleware is the configured middleware that runs before routing. The
+// order matters: the request ID comes first so everything after can log it,
+// and recovery runs inside the access log and metrics so panics are counted
+// as 500s.
+func (a *applicationDefault) httpMiddleware() []func(http.Handler) http.Handler {
+	cfg := a.deps.Config
+
+	md := []func(http.Handler) http.Handler{
+		webMiddleware.RequestID(webMiddleware.RequestIDConfig{
+			Header:        cfg.RequestIDHeader,
+			TrustIncoming: cfg.RequestIDTrustIncoming,
+		}),
+	}
+	if cfg.AccessLogEnabled {
+		md = append(md, webMiddleware.AccessLog(webMiddleware.AccessLogConfig{
+			Logger:    a.deps.Logger,
+			SkipPaths: []string{"/healthz", "/readyz"},
+		}))
+	}
+	if cfg.MetricsEnabled {
+		md = append(md, webMiddleware.Metrics(webMiddleware.MetricsConfig{Observe: metrics.ObserveHTTPRequest}))
+	}
+	md = append(md,
+		webMiddleware.Recover(),
+		webMiddleware.CORS(webMiddleware.CORSConfig{
+			AllowedOrigins:   cfg.CORSAllowedOrigins,
+			AllowedMethods:   cfg.CORSAllowedMethods,
+			AllowedHeaders:   cfg.CORSAllowedHeaders,
+			ExposedHeaders:   []string{cfg.RequestIDHeader, "Retry-After"},
+			AllowCredentials: cfg.CORSAllowCredentials,
+			MaxAge:           time.Duration(cfg.CORSMaxAgeSeconds) * time.Second,
+		}),
+		webMiddleware.RateLimit(webMiddleware.RateLimitConfig{
+			RequestsPerSecond: cfg.RateLimitPerSecond,
+			Burst:             cfg.RateLimitBurst,
+			KeyFunc: func(r *http.Request) string {
+				return webMiddleware.ClientIP(r, cfg.RateLimitTrustProxy)
+			},
+		}),
+		webMiddleware.BodyLimit(webMiddleware.BodyLimitConfig{
+			MaxBytes:          int64(cfg.BodyMaxBytes),
+			MaxMultipartBytes: int64(cfg.BodyMaxMultipartBytes),
+		}),
+	)
+	if cfg.GzipEnabled {
+		md = append(md, webMiddleware.Gzip(webMiddleware.GzipConfig{Level: cfg.GzipLevel}))
+	}
+
+	return md
+}
+
 func (a *applicationDefault) setUpRoutes() {
+	// Routes copy the middleware registered before them, so all of it has to
+	// be in place before any route is added.
+	a.r.UseHTTP(a.httpMiddleware()...)
+	a.r.Use(middleware.Authenticate(a.deps.Auth), middleware.Authorize(a.deps.Auth))
+
+	a.r.Handle("GET", "/healthz", entities.PermissionPublic, a.deps.HealthHandler.Live)
+	a.r.Handle("GET", "/readyz", entities.PermissionPublic, a.deps.HealthHandler.Ready)
+	a.r.Handle("GET", "/status", entities.PermissionRead, a.deps.HealthHandler.Status)
+
+	if a.deps.Config.MetricsEnabled {
+		a.r.Handle("GET", "/metrics", entities.PermissionRead, web.WrapHandler(metrics.Handler()))
+	}
+
 	a.r.Route("/api/v1/inventory", func(rg *web.RouterGroup) {
-		rg.Handle("GET", "", a.deps.InventoryHandler.FindAll)
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.InventoryHandler.FindAll)
+		rg.Handle("GET", "/gtin/{gtin}", entities.PermissionRead, a.deps.InventoryHandler.FindByGTIN)
+		rg.Handle("GET", "/upc/{upc}", entities.PermissionRead, a.deps.InventoryHandler.FindByUPC)
+		rg.Handle("GET", "/wpid/{wpid}", entities.PermissionRead, a.deps.InventoryHandler.FindByWPID)
+		rg.Handle("GET", "/{sku}", entities.PermissionRead, a.deps.InventoryHandler.FindBySKU)
+
+		rg.Handle("POST", "/warehouse-stock/adjustments", entities.PermissionWrite, a.deps.StockHandler.BulkAdjust)
+		rg.Handle("PUT", "/{sku}/warehouse-stock", entities.PermissionWrite, a.deps.StockHandler.SetWarehouseStock)
+		rg.Handle("POST", "/{sku}/warehouse-stock/adjustments", entities.PermissionWrite, a.deps.StockHandler.AdjustWarehouseStock)
+
+		rg.Handle("POST", "/stock/movements", entities.PermissionWrite, a.deps.StockHandler.RecordMovements)
+		rg.Handle("GET", "/{sku}/stock", entities.PermissionRead, a.deps.StockHandler.BalanceAt)
+		rg.Handle("GET", "/{sku}/stock/movements", entities.PermissionRead, a.deps.StockHandler.Movements)
+		rg.Handle("GET", "/{sku}/stock/ledger-check", entities.PermissionRead, a.deps.StockHandler.CheckLedger)
+		rg.Handle("GET", "/{sku}/locations", entities.PermissionRead, a.deps.LocationHandler.StockBySKU)
+		rg.Handle("GET", "/{sku}/forecast", entities.PermissionRead, a.deps.ForecastHandler.FindBySKU)
+		rg.Handle("PUT", "/{sku}/supply", entities.PermissionWrite, a.deps.SupplierHandler.AssignProduct)
+		rg.Handle("GET", "/{sku}/inbound", entities.PermissionRead, a.deps.PurchaseOrderHandler.InboundBySKU)
+		rg.Handle("PUT", "/{sku}/fee-profile", entities.PermissionWrite, a.deps.MarginHandler.SetFeeProfile)
+		rg.Handle("GET", "/{sku}/listing-status/history", entities.PermissionRead, a.deps.ListingHandler.History)
+		rg.Handle("GET", "/{sku}/images", entities.PermissionRead, a.deps.ImageHandler.List)
+		rg.Handle("POST", "/{sku}/images", entities.PermissionWrite, a.deps.ImageHandler.Add)
+		rg.Handle("GET", "/{sku}/images/{id}", entities.PermissionRead, a.deps.ImageHandler.Serve)
+		rg.Handle("GET", "/{sku}/images/{id}/thumbnail", entities.PermissionRead, a.deps.ImageHandler.Thumbnail)
+		rg.Handle("PUT", "/{sku}/images/{id}/primary", entities.PermissionWrite, a.deps.ImageHandler.SetPrimary)
+		rg.Handle("DELETE", "/{sku}/images/{id}", entities.PermissionWrite, a.deps.ImageHandler.Delete)
+	})
+
+	a.r.Route("/api/v1/image-reviews", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.ImageHandler.Reviews)
+		rg.Handle("POST", "/{id}/approve", entities.PermissionWrite, a.deps.ImageHandler.ApproveReview)
+		rg.Handle("POST", "/{id}/reject", entities.PermissionWrite, a.deps.ImageHandler.RejectReview)
+	})
+
+	a.r.Route("/api/v1/image-backfill", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.ImageHandler.BackfillProgress)
+		rg.Handle("GET", "/items", entities.PermissionRead, a.deps.ImageHandler.Backfills)
+		rg.Handle("POST", "/run", entities.PermissionWrite, a.deps.ImageHandler.RunBackfill)
+		rg.Handle("POST", "/requeue", entities.PermissionWrite, a.deps.ImageHandler.Requeue)
+	})
+
+	a.r.Route("/api/v1/forecasts", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.ForecastHandler.FindAll)
+	})
+
+	a.r.Route("/api/v1/locations", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.LocationHandler.FindAll)
+		rg.Handle("POST", "", entities.PermissionWrite, a.deps.LocationHandler.Create)
+		rg.Handle("GET", "/{code}", entities.PermissionRead, a.deps.LocationHandler.FindByCode)
+		rg.Handle("PUT", "/{code}", entities.PermissionWrite, a.deps.LocationHandler.Update)
+		rg.Handle("GET", "/{code}/stock", entities.PermissionRead, a.deps.LocationHandler.Stock)
+	})
+
+	a.r.Route("/api/v1/listing-statuses", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.ListingHandler.Statuses)
+		rg.Handle("GET", "/rules", entities.PermissionRead, a.deps.ListingHandler.Rules)
+		rg.Handle("PUT", "/rules", entities.PermissionAdmin, a.deps.ListingHandler.ReplaceRules)
+	})
+
+	a.r.Route("/api/v1/suppliers", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.SupplierHandler.FindAll)
+		rg.Handle("POST", "", entities.PermissionWrite, a.deps.SupplierHandler.Create)
+		rg.Handle("GET", "/{id}", entities.PermissionRead, a.deps.SupplierHandler.FindByID)
+		rg.Handle("PUT", "/{id}", entities.PermissionWrite, a.deps.SupplierHandler.Update)
+	})
+
+	a.r.Route("/api/v1/replenishment", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "/suggestions", entities.PermissionRead, a.deps.ReplenishmentHandler.Suggestions)
+		rg.Handle("GET", "/suppliers/{id}/draft-po", entities.PermissionRead, a.deps.ReplenishmentHandler.DraftPurchaseOrder)
+		rg.Handle("POST", "/suppliers/{id}/purchase-orders", entities.PermissionWrite, a.deps.PurchaseOrderHandler.CreateFromDraft)
+	})
+
+	a.r.Route("/api/v1/purchase-orders", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.PurchaseOrderHandler.FindAll)
+		rg.Handle("POST", "", entities.PermissionWrite, a.deps.PurchaseOrderHandler.Create)
+		rg.Handle("GET", "/inbound", entities.PermissionRead, a.deps.PurchaseOrderHandler.Inbound)
+		rg.Handle("GET", "/{id}", entities.PermissionRead, a.deps.PurchaseOrderHandler.FindByID)
+		rg.Handle("POST", "/{id}/receipts", entities.PermissionWrite, a.deps.PurchaseOrderHandler.Receive)
+		rg.Handle("POST", "/{id}/close", entities.PermissionWrite, a.deps.PurchaseOrderHandler.Close)
+	})
+
+	a.r.Route("/api/v1/reports", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "/reconciliation", entities.PermissionRead, a.deps.ReportHandler.Reconciliation)
+		rg.Handle("POST", "/reconciliation", entities.PermissionWrite, a.deps.ReportHandler.RunReconciliation)
+		rg.Handle("GET", "/margins", entities.PermissionRead, a.deps.MarginHandler.Report)
+	})
+
+	a.r.Route("/api/v1/fees", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.MarginHandler.FeeSchedule)
+		rg.Handle("PUT", "/referral", entities.PermissionAdmin, a.deps.MarginHandler.ReplaceReferralFees)
+		rg.Handle("PUT", "/fulfillment", entities.PermissionAdmin, a.deps.MarginHandler.ReplaceFulfillmentFees)
+	})
+
+	a.r.Route("/api/v1/alerts", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionRead, a.deps.AlertHandler.FindAll)
+		rg.Handle("POST", "/evaluate", entities.PermissionWrite, a.deps.AlertHandler.Evaluate)
+		rg.Handle("GET", "/rules", entities.PermissionRead, a.deps.AlertHandler.Rules)
+		rg.Handle("POST", "/rules", entities.PermissionAdmin, a.deps.AlertHandler.CreateRule)
+		rg.Handle("DELETE", "/rules/{id}", entities.PermissionAdmin, a.deps.AlertHandler.DeleteRule)
+		rg.Handle("GET", "/channels", entities.PermissionRead, a.deps.AlertHandler.Channels)
+		rg.Handle("POST", "/channels/{name}/test", entities.PermissionWrite, a.deps.AlertHandler.TestChannel)
 	})
 
-	a.r.Route("/api/v1/token", func(rg *web.RouterGroup) {
-		rg.Handle("GET", "", a.deps.WalmartHandler.GetToken)
+	a.r.Route("/api/v1/api-keys", func(rg *web.RouterGroup) {
+		rg.Handle("GET", "", entities.PermissionAdmin, a.deps.APIKeyHandler.FindAll)
+		rg.Handle("POST", "", entities.PermissionAdmin, a.deps.APIKeyHandler.Create)
+		rg.Handle("PUT", "/{id}/role", entities.PermissionAdmin, a.deps.APIKeyHandler.SetRole)
+		rg.Handle("DELETE", "/{id}", entities.PermissionAdmin, a.deps.APIKeyHandler.Revoke)
 	})
 }
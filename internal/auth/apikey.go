@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// KeyPrefix starts every API key, so keys are recognizable in configs and
+// secret scanners and cannot be mistaken for a JWT.
+const KeyPrefix = "wim_"
+
+// displayPrefixLength is how much of a key is stored in clear to identify it.
+const displayPrefixLength = len(KeyPrefix) + 8
+
+// GenerateAPIKey returns a new random key and the prefix stored to identify it.
+func GenerateAPIKey() (key, prefix string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	key = KeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:displayPrefixLength], nil
+}
+
+// IsAPIKey reports whether the credential looks like one of our keys.
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, KeyPrefix)
+}
+
+// HashAPIKey returns the hex SHA-256 of a key. Keys carry 256 random bits, so
+// a fast hash is enough; unlike passwords there is nothing to brute force.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller.
+func WithPrincipal(ctx context.Context, principal *entities.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the caller stored by the auth middleware, or nil.
+func PrincipalFrom(ctx context.Context) *entities.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*entities.Principal)
+	return principal
+}
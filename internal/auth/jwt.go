@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+var (
+	ErrMalformedToken = errors.New("malformed token")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrExpiredToken   = errors.New("token is expired")
+)
+
+// Claims are the registered JWT claims this API understands, plus the
+// caller's role.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Role      string   `json:"role"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt int64    `json:"exp"`
+	NotBefore int64    `json:"nbf"`
+	IssuedAt  int64    `json:"iat"`
+}
+
+// audience accepts both forms the JWT spec allows: a string or a list.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (a audience) contains(value string) bool {
+	for _, v := range a {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+}
+
+// ValidateJWT checks an HS256 token against the policy and returns its claims.
+// Only HS256 is accepted, whatever the header says, so a token cannot pick a
+// weaker algorithm or "none". Tokens must expire and name a subject.
+func ValidateJWT(token string, policy entities.JWTPolicy, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var head header
+	if err := decodeSegment(parts[0], &head); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if head.Algorithm != "HS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, head.Algorithm)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if !hmac.Equal(signature, sign(parts[0]+"."+parts[1], policy.Secret)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if claims.ExpiresAt == 0 {
+		return nil, fmt.Errorf("%w: missing exp claim", ErrMalformedToken)
+	}
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(policy.Leeway)) {
+		return nil, ErrExpiredToken
+	}
+	if claims.NotBefore != 0 && now.Add(policy.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, fmt.Errorf("%w: token is not valid yet", ErrInvalidToken)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub claim", ErrMalformedToken)
+	}
+	if policy.Issuer != "" && claims.Issuer != policy.Issuer {
+		return nil, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, claims.Issuer)
+	}
+	if policy.Audience != "" && !claims.Audience.contains(policy.Audience) {
+		return nil, fmt.Errorf("%w: token is not meant for %q", ErrInvalidToken, policy.Audience)
+	}
+
+	return &claims, nil
+}
+
+func sign(data string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
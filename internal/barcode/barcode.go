@@ -0,0 +1,139 @@
+package barcode
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrEmpty      = errors.New("barcode is empty")
+	ErrNotNumeric = errors.New("barcode must contain only digits")
+	ErrLength     = errors.New("barcode must have between 8 and 14 digits")
+	ErrCheckDigit = errors.New("barcode check digit is wrong")
+)
+
+// Format is the shortest standard form a GTIN can be written in.
+type Format string
+
+const (
+	FormatEAN8   Format = "EAN-8"
+	FormatUPCA   Format = "UPC-A"
+	FormatEAN13  Format = "EAN-13"
+	FormatGTIN14 Format = "GTIN-14"
+)
+
+// GTIN is a validated barcode number held in its canonical 14-digit form.
+// UPC-A, EAN-13 and EAN-8 numbers are the same GTIN padded with leading zeros.
+type GTIN string
+
+// Parse validates a UPC, EAN or GTIN as typed by a user or returned by an API.
+// Spaces and hyphens are ignored, and numbers that lost leading zeros along
+// the way are accepted as long as the check digit holds.
+func Parse(s string) (GTIN, error) {
+	digits := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+
+	if digits == "" {
+		return "", ErrEmpty
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("%w: %q", ErrNotNumeric, s)
+		}
+	}
+	if len(digits) < 8 || len(digits) > 14 {
+		return "", fmt.Errorf("%w: %q has %d", ErrLength, s, len(digits))
+	}
+
+	want := CheckDigit(digits[:len(digits)-1])
+	if got := int(digits[len(digits)-1] - '0'); got != want {
+		return "", fmt.Errorf("%w: %q should end in %d", ErrCheckDigit, s, want)
+	}
+
+	return GTIN(strings.Repeat("0", 14-len(digits)) + digits), nil
+}
+
+// Valid reports whether s parses as a GTIN.
+func Valid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// CheckDigit computes the GS1 check digit for the digits that precede it.
+func CheckDigit(payload string) int {
+	sum := 0
+	for i := 0; i < len(payload); i++ {
+		digit := int(payload[len(payload)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10 - sum%10) % 10
+}
+
+func (g GTIN) String() string {
+	return string(g)
+}
+
+// GTIN14 returns the canonical 14-digit form.
+func (g GTIN) GTIN14() string {
+	return string(g)
+}
+
+// EAN13 returns the 13-digit form when the GTIN has no packaging indicator.
+func (g GTIN) EAN13() (string, bool) {
+	if len(g) != 14 || g[0] != '0' {
+		return "", false
+	}
+	return string(g[1:]), true
+}
+
+// UPCA returns the 12-digit UPC-A form when the GTIN is a US/Canada number.
+func (g GTIN) UPCA() (string, bool) {
+	if len(g) != 14 || g[:2] != "00" {
+		return "", false
+	}
+	return string(g[2:]), true
+}
+
+func (g GTIN) Format() Format {
+	switch {
+	case strings.HasPrefix(string(g), "000000"):
+		return FormatEAN8
+	case strings.HasPrefix(string(g), "00"):
+		return FormatUPCA
+	case strings.HasPrefix(string(g), "0"):
+		return FormatEAN13
+	default:
+		return FormatGTIN14
+	}
+}
+
+// NormalizeGTIN returns the canonical GTIN-14 of s, or s trimmed when it is not
+// a valid barcode so that unexpected values are kept as they came.
+func NormalizeGTIN(s string) string {
+	g, err := Parse(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	return g.GTIN14()
+}
+
+// NormalizeUPC returns the UPC-A form of s. Valid numbers with no UPC-A form
+// are stored as GTIN-14, and invalid ones are kept trimmed.
+func NormalizeUPC(s string) string {
+	g, err := Parse(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	if upc, ok := g.UPCA(); ok {
+		return upc
+	}
+	return g.GTIN14()
+}
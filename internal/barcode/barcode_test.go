@@ -0,0 +1,109 @@
+package barcode
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    int
+	}{
+		{"03600029145", 2},
+		{"400638133393", 1},
+		{"9638507", 4},
+		{"1001234567890", 2},
+		{"00000000000", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := CheckDigit(tt.payload); got != tt.want {
+			t.Errorf("CheckDigit(%q) = %d, want %d", tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    GTIN
+		wantErr error
+	}{
+		{"UPC-A", "036000291452", "00036000291452", nil},
+		{"EAN-13", "4006381333931", "04006381333931", nil},
+		{"EAN-8", "96385074", "00000096385074", nil},
+		{"GTIN-14", "10012345678902", "10012345678902", nil},
+		{"UPC-A that lost its leading zero", "36000291452", "00036000291452", nil},
+		{"spaces and hyphens", " 0 36000-29145 2 ", "00036000291452", nil},
+		{"empty", "", "", ErrEmpty},
+		{"blank", "   ", "", ErrEmpty},
+		{"letters", "03600029145A", "", ErrNotNumeric},
+		{"too short", "1234567", "", ErrLength},
+		{"too long", "123456789012345", "", ErrLength},
+		{"wrong check digit", "036000291453", "", ErrCheckDigit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGTINForms(t *testing.T) {
+	tests := []struct {
+		gtin   GTIN
+		format Format
+		upca   string
+		ean13  string
+	}{
+		{"00036000291452", FormatUPCA, "036000291452", "0036000291452"},
+		{"04006381333931", FormatEAN13, "", "4006381333931"},
+		{"00000096385074", FormatEAN8, "000096385074", "0000096385074"},
+		{"10012345678902", FormatGTIN14, "", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.gtin.Format(); got != tt.format {
+			t.Errorf("%s.Format() = %s, want %s", tt.gtin, got, tt.format)
+		}
+		if got, _ := tt.gtin.UPCA(); got != tt.upca {
+			t.Errorf("%s.UPCA() = %q, want %q", tt.gtin, got, tt.upca)
+		}
+		if got, _ := tt.gtin.EAN13(); got != tt.ean13 {
+			t.Errorf("%s.EAN13() = %q, want %q", tt.gtin, got, tt.ean13)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		input string
+		gtin  string
+		upc   string
+	}{
+		{"036000291452", "00036000291452", "036000291452"},
+		{"36000291452", "00036000291452", "036000291452"},
+		{"4006381333931", "04006381333931", "04006381333931"},
+		{" 036000291453 ", "036000291453", "036000291453"},
+		{"not-a-code", "not-a-code", "not-a-code"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeGTIN(tt.input); got != tt.gtin {
+			t.Errorf("NormalizeGTIN(%q) = %q, want %q", tt.input, got, tt.gtin)
+		}
+		if got := NormalizeUPC(tt.input); got != tt.upc {
+			t.Errorf("NormalizeUPC(%q) = %q, want %q", tt.input, got, tt.upc)
+		}
+	}
+}
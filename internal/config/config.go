@@ -1,23 +1,349 @@
+// Package config loads the service configuration. Every setting is named
+// like its environment variable and resolved from, in increasing precedence:
+// its default, the config file (YAML or TOML, given by -config or
+// CONFIG_FILE), the environment (including an optional .env file) and the
+// command line, where DB_MAX_OPEN_CONNS is -db-max-open-conns.
 package config
 
-import "os"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"walmart-inventory-manager/internal/entities"
+
+	"github.com/joho/godotenv"
+)
 
 type Config struct {
-	ServerAdress string
-	DBHost       string
-	DBPort       string
-	DBUser       string
-	DBPassword   string
-	DBName       string
+	// PrintConfig is set by -print-config: dump the configuration and exit.
+	PrintConfig bool
+
+	ServerAddress             string
+	ServerReadTimeoutSeconds  int
+	ServerWriteTimeoutSeconds int
+	ServerIdleTimeoutSeconds  int
+
+	DBHost                   string
+	DBPort                   string
+	DBUser                   string
+	DBPassword               string
+	DBName                   string
+	DBTLS                    string
+	DBMaxOpenConns           int
+	DBMaxIdleConns           int
+	DBConnMaxLifetimeMinutes int
+	DBConnMaxIdleTimeMinutes int
+
+	WalmartPartnerID    string
+	WalmartClientID     string
+	WalmartClientSecret string
+
+	SchedulerTimezone string
+	CatalogSyncTime   string
+	OrdersSyncTime    string
+
+	ReconciliationToleranceUnits   int
+	ReconciliationTolerancePercent float64
+
+	ForecastSmoothingAlpha float64
+
+	ReorderSafetyStockDays int
+	ReorderCoverageDays    int
+
+	DelistGraceRuns         int
+	DelistMaxMissingPercent float64
+
+	ImageCacheDir      string
+	ImageThumbnailSize int
+	ImageMaxBytes      int
+	ImageMaxPixels     int
+	ImageRefreshDays   int
+
+	ImageMatchThreshold  float64
+	ImageReviewThreshold float64
+
+	ImageBackfillIntervalMinutes  int
+	ImageBackfillBatchSize        int
+	ImageBackfillRetryBaseMinutes int
+	ImageBackfillRetryMaxHours    int
+	ImageBackfillMaxAttempts      int
+
+	LogLevel  string
+	LogFormat string
+
+	ReadyMaxSyncAgeHours    int
+	ReadyCheckTimeoutMillis int
+
+	RequestIDHeader        string
+	RequestIDTrustIncoming bool
+	AccessLogEnabled       bool
+	MetricsEnabled         bool
+
+	CORSAllowedOrigins   []string
+	CORSAllowedMethods   []string
+	CORSAllowedHeaders   []string
+	CORSAllowCredentials bool
+	CORSMaxAgeSeconds    int
+
+	GzipEnabled bool
+	GzipLevel   int
+
+	BodyMaxBytes          int
+	BodyMaxMultipartBytes int
+
+	RateLimitPerSecond  float64
+	RateLimitBurst      int
+	RateLimitTrustProxy bool
+
+	AuthJWTSecret        string
+	AuthJWTIssuer        string
+	AuthJWTAudience      string
+	AuthJWTLeewaySeconds int
+
+	AlertWebhookURL    string
+	AlertWebhookSecret string
+	SMTPHost           string
+	SMTPPort           string
+	SMTPUsername       string
+	SMTPPassword       string
+	AlertEmailFrom     string
+	AlertEmailTo       []string
+
+	settings []Setting
 }
 
-func NewConfig() *Config {
+// build declares every setting with its default. It runs once to register
+// the flags and once to read the values.
+func build(l *loader) *Config {
 	return &Config{
-		ServerAdress: os.Getenv("SERVER_ADDRESS"),
-		DBHost: os.Getenv("DB_HOST"),
-		DBPort: os.Getenv("DB_PORT"),
-		DBUser: os.Getenv("DB_USER"),
-		DBPassword: os.Getenv("DB_PASSWORD"),
-		DBName: os.Getenv("DB_NAME"),
+		ServerAddress:             l.String("SERVER_ADDRESS", ":8081"),
+		ServerReadTimeoutSeconds:  l.Int("SERVER_READ_TIMEOUT_SECONDS", 15),
+		ServerWriteTimeoutSeconds: l.Int("SERVER_WRITE_TIMEOUT_SECONDS", 60),
+		ServerIdleTimeoutSeconds:  l.Int("SERVER_IDLE_TIMEOUT_SECONDS", 120),
+
+		DBHost:                   l.String("DB_HOST", ""),
+		DBPort:                   l.String("DB_PORT", "3306"),
+		DBUser:                   l.String("DB_USER", ""),
+		DBPassword:               l.Secret("DB_PASSWORD"),
+		DBName:                   l.String("DB_NAME", ""),
+		DBTLS:                    l.String("DB_TLS", "true"),
+		DBMaxOpenConns:           l.Int("DB_MAX_OPEN_CONNS", 25),
+		DBMaxIdleConns:           l.Int("DB_MAX_IDLE_CONNS", 10),
+		DBConnMaxLifetimeMinutes: l.Int("DB_CONN_MAX_LIFETIME_MINUTES", 30),
+		DBConnMaxIdleTimeMinutes: l.Int("DB_CONN_MAX_IDLE_TIME_MINUTES", 5),
+
+		WalmartPartnerID:    l.String("WM_PARTNER_ID", ""),
+		WalmartClientID:     l.String("WALMART_CLIENT_ID", ""),
+		WalmartClientSecret: l.Secret("WALMART_CLIENT_SECRET"),
+
+		SchedulerTimezone: l.String("SCHEDULER_TIMEZONE", "America/Argentina/Buenos_Aires"),
+		CatalogSyncTime:   l.String("CATALOG_SYNC_TIME", "15:25"),
+		OrdersSyncTime:    l.String("ORDERS_SYNC_TIME", "23:40"),
+
+		ReconciliationToleranceUnits:   l.Int("RECONCILIATION_TOLERANCE_UNITS", 0),
+		ReconciliationTolerancePercent: l.Float("RECONCILIATION_TOLERANCE_PERCENT", 0),
+
+		ForecastSmoothingAlpha: l.Float("FORECAST_SMOOTHING_ALPHA", 0.3),
+
+		ReorderSafetyStockDays: l.Int("REORDER_SAFETY_STOCK_DAYS", 14),
+		ReorderCoverageDays:    l.Int("REORDER_COVERAGE_DAYS", 30),
+
+		DelistGraceRuns:         l.Int("DELIST_GRACE_RUNS", 3),
+		DelistMaxMissingPercent: l.Float("DELIST_MAX_MISSING_PERCENT", 10),
+
+		ImageCacheDir:      l.String("IMAGE_CACHE_DIR", "data/images"),
+		ImageThumbnailSize: l.Int("IMAGE_THUMBNAIL_SIZE", 200),
+		ImageMaxBytes:      l.Int("IMAGE_MAX_BYTES", 10<<20),
+		ImageMaxPixels:     l.Int("IMAGE_MAX_PIXELS", 40_000_000),
+		ImageRefreshDays:   l.Int("IMAGE_REFRESH_DAYS", 30),
+
+		ImageMatchThreshold:  l.Float("IMAGE_MATCH_THRESHOLD", 0.75),
+		ImageReviewThreshold: l.Float("IMAGE_REVIEW_THRESHOLD", 0.4),
+
+		ImageBackfillIntervalMinutes:  l.Int("IMAGE_BACKFILL_INTERVAL_MINUTES", 15),
+		ImageBackfillBatchSize:        l.Int("IMAGE_BACKFILL_BATCH_SIZE", 25),
+		ImageBackfillRetryBaseMinutes: l.Int("IMAGE_BACKFILL_RETRY_BASE_MINUTES", 60),
+		ImageBackfillRetryMaxHours:    l.Int("IMAGE_BACKFILL_RETRY_MAX_HOURS", 168),
+		ImageBackfillMaxAttempts:      l.Int("IMAGE_BACKFILL_MAX_ATTEMPTS", 8),
+
+		LogLevel:  l.String("LOG_LEVEL", "info"),
+		LogFormat: l.String("LOG_FORMAT", "json"),
+
+		ReadyMaxSyncAgeHours:    l.Int("READY_MAX_SYNC_AGE_HOURS", 26),
+		ReadyCheckTimeoutMillis: l.Int("READY_CHECK_TIMEOUT_MILLIS", 2000),
+
+		RequestIDHeader:        l.String("REQUEST_ID_HEADER", "X-Request-ID"),
+		RequestIDTrustIncoming: l.Bool("REQUEST_ID_TRUST_INCOMING", true),
+		AccessLogEnabled:       l.Bool("ACCESS_LOG_ENABLED", true),
+		MetricsEnabled:         l.Bool("METRICS_ENABLED", true),
+
+		CORSAllowedOrigins:   l.List("CORS_ALLOWED_ORIGINS"),
+		CORSAllowedMethods:   l.List("CORS_ALLOWED_METHODS", "GET", "POST", "PUT", "DELETE"),
+		CORSAllowedHeaders:   l.List("CORS_ALLOWED_HEADERS", "Authorization", "Content-Type", "X-API-Key", "X-Request-ID"),
+		CORSAllowCredentials: l.Bool("CORS_ALLOW_CREDENTIALS", false),
+		CORSMaxAgeSeconds:    l.Int("CORS_MAX_AGE_SECONDS", 600),
+
+		GzipEnabled: l.Bool("GZIP_ENABLED", true),
+		GzipLevel:   l.Int("GZIP_LEVEL", 0),
+
+		// The multipart limit leaves room for an image of IMAGE_MAX_BYTES plus
+		// the form framing.
+		BodyMaxBytes:          l.Int("BODY_MAX_BYTES", 1<<20),
+		BodyMaxMultipartBytes: l.Int("BODY_MAX_MULTIPART_BYTES", 11<<20),
+
+		RateLimitPerSecond:  l.Float("RATE_LIMIT_PER_SECOND", 10),
+		RateLimitBurst:      l.Int("RATE_LIMIT_BURST", 20),
+		RateLimitTrustProxy: l.Bool("RATE_LIMIT_TRUST_PROXY", false),
+
+		AuthJWTSecret:        l.Secret("AUTH_JWT_SECRET"),
+		AuthJWTIssuer:        l.String("AUTH_JWT_ISSUER", ""),
+		AuthJWTAudience:      l.String("AUTH_JWT_AUDIENCE", ""),
+		AuthJWTLeewaySeconds: l.Int("AUTH_JWT_LEEWAY_SECONDS", 30),
+
+		AlertWebhookURL:    l.Secret("ALERT_WEBHOOK_URL"),
+		AlertWebhookSecret: l.Secret("ALERT_WEBHOOK_SECRET"),
+		SMTPHost:           l.String("SMTP_HOST", ""),
+		SMTPPort:           l.String("SMTP_PORT", "25"),
+		SMTPUsername:       l.String("SMTP_USERNAME", ""),
+		SMTPPassword:       l.Secret("SMTP_PASSWORD"),
+		AlertEmailFrom:     l.String("ALERT_EMAIL_FROM", "inventory-alerts@localhost"),
+		AlertEmailTo:       l.List("ALERT_EMAIL_TO"),
+	}
+}
+
+// Load resolves the configuration from the command-line arguments, which are
+// the service's own flags and one flag per setting. Malformed values are
+// reported here; whether the configuration is usable is up to Validate.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("walmart-inventory-manager", flag.ContinueOnError)
+	configFile := fs.String("config", "", "YAML or TOML config file; defaults to $CONFIG_FILE")
+	envFile := fs.String("env-file", ".env", "file of environment variables, loaded when present; the environment wins")
+	printConfig := fs.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+
+	flags := make(map[string]*flagValue)
+	build(&loader{register: fs, flags: flags})
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if err := godotenv.Load(*envFile); err != nil && (isSet(fs, "env-file") || !errors.Is(err, os.ErrNotExist)) {
+		return nil, fmt.Errorf("loading %s: %w", *envFile, err)
+	}
+
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	var file map[string]string
+	if path != "" {
+		var err error
+		if file, err = readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	l := newLoader(flags, file)
+	cfg := build(l)
+	l.unknownFileKeys()
+
+	cfg.PrintConfig = *printConfig
+	cfg.settings = l.settings
+
+	return cfg, errors.Join(l.errs...)
+}
+
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// Validate reports every setting the service cannot start with.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	required := func(key, value string) {
+		check(strings.TrimSpace(value) != "", "%s is required", key)
+	}
+
+	required("SERVER_ADDRESS", c.ServerAddress)
+	check(c.ServerReadTimeoutSeconds >= 0 && c.ServerWriteTimeoutSeconds >= 0 && c.ServerIdleTimeoutSeconds >= 0,
+		"SERVER_*_TIMEOUT_SECONDS must not be negative")
+
+	required("DB_HOST", c.DBHost)
+	required("DB_USER", c.DBUser)
+	required("DB_NAME", c.DBName)
+	port, err := strconv.Atoi(c.DBPort)
+	check(err == nil && port > 0 && port < 65536, "DB_PORT must be a port number, got %q", c.DBPort)
+	check(c.DBMaxOpenConns >= 0, "DB_MAX_OPEN_CONNS must not be negative")
+	check(c.DBMaxIdleConns >= 0, "DB_MAX_IDLE_CONNS must not be negative")
+	check(c.DBMaxOpenConns == 0 || c.DBMaxIdleConns <= c.DBMaxOpenConns, "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS")
+
+	required("WM_PARTNER_ID", c.WalmartPartnerID)
+	required("WALMART_CLIENT_ID", c.WalmartClientID)
+	required("WALMART_CLIENT_SECRET", c.WalmartClientSecret)
+
+	_, err = entities.ParseDailySchedule(c.CatalogSyncTime, c.SchedulerTimezone)
+	check(err == nil, "CATALOG_SYNC_TIME or SCHEDULER_TIMEZONE: %v", err)
+	_, err = entities.ParseDailySchedule(c.OrdersSyncTime, c.SchedulerTimezone)
+	check(err == nil, "ORDERS_SYNC_TIME or SCHEDULER_TIMEZONE: %v", err)
+	check(c.ImageMaxPixels > 0, "IMAGE_MAX_PIXELS must be positive")
+	check(c.ImageBackfillIntervalMinutes >= 0, "IMAGE_BACKFILL_INTERVAL_MINUTES must not be negative")
+	check(c.ImageBackfillBatchSize > 0, "IMAGE_BACKFILL_BATCH_SIZE must be positive")
+
+	check(c.ForecastSmoothingAlpha > 0 && c.ForecastSmoothingAlpha <= 1, "FORECAST_SMOOTHING_ALPHA must be in (0, 1]")
+	check(c.ImageReviewThreshold >= 0 && c.ImageReviewThreshold <= c.ImageMatchThreshold && c.ImageMatchThreshold <= 1,
+		"IMAGE_REVIEW_THRESHOLD and IMAGE_MATCH_THRESHOLD must satisfy 0 <= review <= match <= 1")
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.LogLevel)) == nil, "LOG_LEVEL must be debug, info, warn or error, got %q", c.LogLevel)
+	check(c.LogFormat == "json" || c.LogFormat == "text", "LOG_FORMAT must be json or text, got %q", c.LogFormat)
+
+	check(c.GzipLevel >= -2 && c.GzipLevel <= 9, "GZIP_LEVEL must be between -2 and 9")
+	check(c.BodyMaxBytes > 0 && c.BodyMaxMultipartBytes > 0, "BODY_MAX_BYTES and BODY_MAX_MULTIPART_BYTES must be positive")
+	check(c.RateLimitPerSecond >= 0, "RATE_LIMIT_PER_SECOND must not be negative")
+
+	check(c.SMTPHost == "" || len(c.AlertEmailTo) > 0, "ALERT_EMAIL_TO is required when SMTP_HOST is set")
+
+	return errors.Join(errs...)
+}
+
+// Settings returns the effective value and source of every setting, in the
+// order they are declared, with secrets redacted.
+func (c *Config) Settings() []Setting {
+	settings := make([]Setting, len(c.settings))
+	for i, s := range c.settings {
+		if s.Secret && s.Value != "" {
+			s.Value = redacted
+		}
+		settings[i] = s
+	}
+	return settings
+}
+
+// Dump writes the effective configuration as a table with secrets redacted.
+func (c *Config) Dump(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE")
+	for _, s := range c.Settings() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
 	}
+	return tw.Flush()
 }
@@ -0,0 +1,248 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Where a setting came from, from lowest to highest precedence.
+const (
+	sourceDefault = "default"
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceFlag    = "flag"
+)
+
+const redacted = "[REDACTED]"
+
+// Setting is the effective value of one setting, named like its environment
+// variable.
+type Setting struct {
+	Key    string
+	Value  string
+	Source string
+	Secret bool
+}
+
+// loader resolves every setting through the layers. Settings are declared by
+// the calls build makes, so the same code registers the flags, reads the
+// values and records them for the dump.
+type loader struct {
+	// register is set during the first pass, which only declares a flag for
+	// every setting.
+	register *flag.FlagSet
+	flags    map[string]*flagValue
+
+	file     map[string]string
+	used     map[string]bool
+	settings []Setting
+	errs     []error
+}
+
+func newLoader(flags map[string]*flagValue, file map[string]string) *loader {
+	return &loader{
+		flags: flags,
+		file:  file,
+		used:  make(map[string]bool),
+	}
+}
+
+func (l *loader) value(key, fallback string, isBool, secret bool) (string, string) {
+	if l.register != nil {
+		value := &flagValue{isBool: isBool}
+		l.flags[key] = value
+		l.register.Var(value, flagName(key), "overrides $"+key)
+		return fallback, sourceDefault
+	}
+
+	value, source := fallback, sourceDefault
+	if v, ok := l.file[key]; ok {
+		value, source = v, sourceFile
+		l.used[key] = true
+	}
+	// An empty variable counts as unset, as it always has.
+	if v := os.Getenv(key); v != "" {
+		value, source = v, sourceEnv
+	}
+	if f, ok := l.flags[key]; ok && f.set {
+		value, source = f.value, sourceFlag
+	}
+
+	l.settings = append(l.settings, Setting{Key: key, Value: value, Source: source, Secret: secret})
+	return value, source
+}
+
+func (l *loader) invalid(key, value, source, want string) {
+	l.errs = append(l.errs, fmt.Errorf("%s: %q from %s is not %s", key, value, source, want))
+}
+
+func (l *loader) String(key, fallback string) string {
+	value, _ := l.value(key, fallback, false, false)
+	return value
+}
+
+// Secret is a string setting that is redacted from the dump.
+func (l *loader) Secret(key string) string {
+	value, _ := l.value(key, "", false, true)
+	return value
+}
+
+func (l *loader) Int(key string, fallback int) int {
+	value, source := l.value(key, strconv.Itoa(fallback), false, false)
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		l.invalid(key, value, source, "a whole number")
+		return fallback
+	}
+	return n
+}
+
+func (l *loader) Float(key string, fallback float64) float64 {
+	value, source := l.value(key, strconv.FormatFloat(fallback, 'g', -1, 64), false, false)
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		l.invalid(key, value, source, "a number")
+		return fallback
+	}
+	return f
+}
+
+func (l *loader) Bool(key string, fallback bool) bool {
+	value, source := l.value(key, strconv.FormatBool(fallback), true, false)
+	b, err := strconv.ParseBool(strings.TrimSpace(value))
+	if err != nil {
+		l.invalid(key, value, source, "true or false")
+		return fallback
+	}
+	return b
+}
+
+// List is a comma-separated setting. An empty list falls back to the default.
+func (l *loader) List(key string, fallback ...string) []string {
+	value, _ := l.value(key, strings.Join(fallback, ","), false, false)
+
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
+// unknownFileKeys reports settings in the config file that nothing reads,
+// which are almost always typos.
+func (l *loader) unknownFileKeys() {
+	var unknown []string
+	for key := range l.file {
+		if !l.used[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		l.errs = append(l.errs, fmt.Errorf("%s: unknown setting in the config file", key))
+	}
+}
+
+// flagValue holds a setting given on the command line. Settings are
+// registered as strings so a flag is only applied when it was actually set.
+type flagValue struct {
+	value  string
+	set    bool
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(value string) error {
+	f.value, f.set = value, true
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// flagName turns DB_MAX_OPEN_CONNS into db-max-open-conns.
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// readFile reads a YAML or TOML config file, chosen by extension, into
+// settings keyed like the environment. Nested tables are joined with
+// underscores, so db: {host: x} sets DB_HOST, and lists are joined with
+// commas.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	settings := make(map[string]string)
+	if err := flatten("", raw, settings); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+func flatten(prefix string, raw map[string]interface{}, settings map[string]string) error {
+	for name, value := range raw {
+		key := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(key, v, settings); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				switch item.(type) {
+				case map[string]interface{}, []interface{}:
+					return errors.New(key + ": lists may only hold plain values")
+				}
+				items = append(items, fmt.Sprint(item))
+			}
+			settings[key] = strings.Join(items, ",")
+		case nil:
+			settings[key] = ""
+		default:
+			settings[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
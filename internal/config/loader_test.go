@@ -0,0 +1,358 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// isolate blanks every setting in the environment, which counts as unset, and
+// points -env-file at an empty file so the developer's .env is not read.
+func isolate(t *testing.T) []string {
+	t.Helper()
+
+	flags := make(map[string]*flagValue)
+	build(&loader{register: flag.NewFlagSet("keys", flag.ContinueOnError), flags: flags})
+	for key := range flags {
+		t.Setenv(key, "")
+	}
+	t.Setenv("CONFIG_FILE", "")
+
+	return []string{"-env-file", writeFile(t, "empty.env", "")}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+	return path
+}
+
+func settingsByKey(cfg *Config) map[string]Setting {
+	settings := make(map[string]Setting)
+	for _, s := range cfg.Settings() {
+		settings[s.Key] = s
+	}
+	return settings
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	args := isolate(t)
+	path := writeFile(t, "config.yaml", `
+server_address: ":9000"
+db:
+  host: file-host
+  port: 3307
+  name: file-db
+  max_open_conns: 40
+cors:
+  allowed_origins: [https://a.example, https://b.example]
+`)
+	t.Setenv("DB_PORT", "3308")
+	t.Setenv("DB_NAME", "env-db")
+	t.Setenv("DB_MAX_OPEN_CONNS", "") // empty counts as unset: the file wins
+	t.Setenv("LOG_LEVEL", "debug")
+
+	cfg, err := Load(append(args, "-config", path, "-db-name", "flag-db", "-gzip-enabled=false"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		key, value, source string
+	}{
+		{"SERVER_ADDRESS", ":9000", sourceFile},
+		{"DB_HOST", "file-host", sourceFile},
+		{"DB_PORT", "3308", sourceEnv},
+		{"DB_NAME", "flag-db", sourceFlag},
+		{"DB_MAX_OPEN_CONNS", "40", sourceFile},
+		{"DB_MAX_IDLE_CONNS", "10", sourceDefault},
+		{"LOG_LEVEL", "debug", sourceEnv},
+		{"GZIP_ENABLED", "false", sourceFlag},
+		{"CORS_ALLOWED_ORIGINS", "https://a.example,https://b.example", sourceFile},
+		{"CORS_ALLOWED_METHODS", "GET,POST,PUT,DELETE", sourceDefault},
+	}
+	settings := settingsByKey(cfg)
+	for _, tt := range tests {
+		s, ok := settings[tt.key]
+		if !ok {
+			t.Errorf("%s: no setting", tt.key)
+			continue
+		}
+		if s.Value != tt.value || s.Source != tt.source {
+			t.Errorf("%s = %q from %s, want %q from %s", tt.key, s.Value, s.Source, tt.value, tt.source)
+		}
+	}
+
+	if cfg.DBMaxOpenConns != 40 || cfg.DBName != "flag-db" || cfg.GzipEnabled {
+		t.Errorf("config = DBMaxOpenConns %d, DBName %q, GzipEnabled %v", cfg.DBMaxOpenConns, cfg.DBName, cfg.GzipEnabled)
+	}
+	if want := []string{"https://a.example", "https://b.example"}; !reflect.DeepEqual(cfg.CORSAllowedOrigins, want) {
+		t.Errorf("CORSAllowedOrigins = %q, want %q", cfg.CORSAllowedOrigins, want)
+	}
+}
+
+func TestLoaderReadsAParsedFlagSet(t *testing.T) {
+	isolate(t)
+	t.Setenv("DB_HOST", "env-host")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := make(map[string]*flagValue)
+	build(&loader{register: fs, flags: flags})
+	if err := fs.Parse([]string{"-db-host", "flag-host", "-metrics-enabled"}); err != nil {
+		t.Fatalf("parsing flags: %v", err)
+	}
+
+	l := newLoader(flags, map[string]string{"DB_HOST": "file-host", "DB_USER": "file-user"})
+	cfg := build(l)
+	l.unknownFileKeys()
+	if len(l.errs) > 0 {
+		t.Fatalf("errors: %v", l.errs)
+	}
+
+	if cfg.DBHost != "flag-host" || cfg.DBUser != "file-user" || !cfg.MetricsEnabled {
+		t.Errorf("config = DBHost %q, DBUser %q, MetricsEnabled %v", cfg.DBHost, cfg.DBUser, cfg.MetricsEnabled)
+	}
+	// A flag that was not given does not override the lower layers.
+	if f := flags["DB_USER"]; f.set {
+		t.Errorf("DB_USER flag is set without being given")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	want := map[string]string{
+		"SERVER_ADDRESS":            ":9000",
+		"DB_HOST":                   "db.internal",
+		"DB_MAX_OPEN_CONNS":         "40",
+		"IMAGE_BACKFILL_BATCH_SIZE": "10",
+		"ALERT_EMAIL_TO":            "a@example.com,b@example.com",
+		"SMTP_HOST":                 "",
+	}
+
+	tests := []struct {
+		name, content string
+	}{
+		{"config.yaml", `
+server-address: ":9000"
+db:
+  host: db.internal
+  max_open_conns: 40
+image:
+  backfill.batch-size: 10
+alert_email_to: [a@example.com, b@example.com]
+smtp_host:
+`},
+		{"config.toml", `
+server-address = ":9000"
+alert_email_to = ["a@example.com", "b@example.com"]
+smtp_host = ""
+
+[db]
+host = "db.internal"
+max_open_conns = 40
+
+[image.backfill]
+batch-size = 10
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readFile(writeFile(t, tt.name, tt.content))
+			if err != nil {
+				t.Fatalf("readFile: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("readFile = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"config.json", `{}`, `unsupported format ".json"`},
+		{"config.yaml", "db: [", "parsing config file"},
+		{"config.yaml", "cors:\n  allowed_origins:\n    - host: a\n", "CORS_ALLOWED_ORIGINS: lists may only hold plain values"},
+	}
+
+	for _, tt := range tests {
+		_, err := readFile(writeFile(t, tt.name, tt.content))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("readFile(%s %q) error = %v, want it to mention %q", tt.name, tt.content, err, tt.want)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		args    []string
+		wantErr []string
+	}{
+		{
+			name:    "unknown keys in the file",
+			file:    "db:\n  hots: x\nsevrer_address: y\n",
+			wantErr: []string{"DB_HOTS: unknown setting", "SEVRER_ADDRESS: unknown setting"},
+		},
+		{
+			name:    "malformed values name their source",
+			file:    "db:\n  max_open_conns: many\n",
+			env:     map[string]string{"GZIP_LEVEL": "high"},
+			args:    []string{"-metrics-enabled=maybe"},
+			wantErr: []string{`DB_MAX_OPEN_CONNS: "many" from file`, `GZIP_LEVEL: "high" from env`, `METRICS_ENABLED: "maybe" from flag`},
+		},
+		{
+			name:    "stray arguments",
+			args:    []string{"serve"},
+			wantErr: []string{"unexpected arguments: serve"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := isolate(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			if tt.file != "" {
+				args = append(args, "-config", writeFile(t, "config.yaml", tt.file))
+			}
+
+			_, err := Load(append(args, tt.args...))
+			if err == nil {
+				t.Fatalf("Load succeeded, want errors %q", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load error %q does not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	args := isolate(t)
+	for key, value := range map[string]string{
+		"DB_HOST":               "localhost",
+		"DB_USER":               "inventory",
+		"DB_NAME":               "inventory",
+		"WM_PARTNER_ID":         "partner",
+		"WALMART_CLIENT_ID":     "client",
+		"WALMART_CLIENT_SECRET": "secret",
+	} {
+		t.Setenv(key, value)
+	}
+
+	cfg, err := Load(args)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate of the defaults: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Config)
+		want   string
+	}{
+		{"missing host", func(c *Config) { c.DBHost = " " }, "DB_HOST is required"},
+		{"bad port", func(c *Config) { c.DBPort = "70000" }, `DB_PORT must be a port number, got "70000"`},
+		{"idle above open", func(c *Config) { c.DBMaxIdleConns = 30 }, "DB_MAX_IDLE_CONNS must not exceed DB_MAX_OPEN_CONNS"},
+		{"bad sync time", func(c *Config) { c.CatalogSyncTime = "25:00" }, "CATALOG_SYNC_TIME or SCHEDULER_TIMEZONE"},
+		{"bad timezone", func(c *Config) { c.SchedulerTimezone = "Mars/Olympus" }, "ORDERS_SYNC_TIME or SCHEDULER_TIMEZONE"},
+		{"alpha out of range", func(c *Config) { c.ForecastSmoothingAlpha = 0 }, "FORECAST_SMOOTHING_ALPHA"},
+		{"review above match", func(c *Config) { c.ImageReviewThreshold = 0.9 }, "IMAGE_REVIEW_THRESHOLD and IMAGE_MATCH_THRESHOLD"},
+		{"bad log level", func(c *Config) { c.LogLevel = "loud" }, `LOG_LEVEL must be debug, info, warn or error, got "loud"`},
+		{"smtp without recipients", func(c *Config) { c.SMTPHost = "smtp.example.com" }, "ALERT_EMAIL_TO is required when SMTP_HOST is set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := *cfg
+			tt.change(&changed)
+
+			err := changed.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDumpRedactsSecrets(t *testing.T) {
+	args := isolate(t)
+	t.Setenv("WALMART_CLIENT_SECRET", "env-client-secret")
+	t.Setenv("DB_USER", "inventory")
+	path := writeFile(t, "config.toml", "db_password = \"file-db-password\"\n")
+
+	cfg, err := Load(append(args, "-config", path, "-auth-jwt-secret", "flag-jwt-secret"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	settings := settingsByKey(cfg)
+	for key, source := range map[string]string{
+		"WALMART_CLIENT_SECRET": sourceEnv,
+		"DB_PASSWORD":           sourceFile,
+		"AUTH_JWT_SECRET":       sourceFlag,
+	} {
+		if s := settings[key]; s.Value != redacted || s.Source != source || !s.Secret {
+			t.Errorf("%s = %+v, want %s from %s", key, s, redacted, source)
+		}
+	}
+	// An unset secret shows as empty, so it is clear that it is missing.
+	if s := settings["SMTP_PASSWORD"]; s.Value != "" || s.Source != sourceDefault {
+		t.Errorf("SMTP_PASSWORD = %+v, want an empty default", s)
+	}
+	if s := settings["DB_USER"]; s.Value != "inventory" || s.Secret {
+		t.Errorf("DB_USER = %+v, want inventory in the clear", s)
+	}
+	// The config keeps the real values.
+	if cfg.DBPassword != "file-db-password" || cfg.AuthJWTSecret != "flag-jwt-secret" {
+		t.Errorf("config lost the secret values")
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Dump(&out); err != nil {
+		t.Fatalf("Dump: %v", err)
+	}
+	dump := out.String()
+	for _, secret := range []string{"env-client-secret", "file-db-password", "flag-jwt-secret"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("Dump prints the secret %q", secret)
+		}
+	}
+	if !strings.HasPrefix(dump, "SETTING") {
+		t.Errorf("Dump does not start with the header:\n%s", dump)
+	}
+	rows := make(map[string][]string)
+	for _, line := range strings.Split(strings.TrimSpace(dump), "\n") {
+		fields := strings.Fields(line)
+		rows[fields[0]] = fields[1:]
+	}
+	for key, want := range map[string][]string{
+		"WALMART_CLIENT_SECRET": {redacted, sourceEnv},
+		"DB_PASSWORD":           {redacted, sourceFile},
+		"AUTH_JWT_SECRET":       {redacted, sourceFlag},
+		"SMTP_PASSWORD":         {sourceDefault},
+		"DB_USER":               {"inventory", sourceEnv},
+	} {
+		if !reflect.DeepEqual(rows[key], want) {
+			t.Errorf("Dump row %s = %q, want %q", key, rows[key], want)
+		}
+	}
+}
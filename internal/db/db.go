@@ -3,23 +3,28 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"time"
 	"walmart-inventory-manager/internal/config"
 
 	_ "github.com/go-sql-driver/mysql"
 )
 
 func ConnectDB(cfg *config.Config) (*sql.DB, error) {
-	connStr := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&tls=true", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
+	connStr := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&tls=%s", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName, cfg.DBTLS)
 	db, err := sql.Open("mysql", connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
 
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetimeMinutes) * time.Minute)
+	db.SetConnMaxIdleTime(time.Duration(cfg.DBConnMaxIdleTimeMinutes) * time.Minute)
+
 	err = db.Ping()
 	if err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	fmt.Println("Database connected")
 	return db, nil
 }
@@ -0,0 +1,8 @@
+ALTER TABLE wmt_product_details
+	ADD COLUMN availability VARCHAR(32) NULL AFTER price,
+	ADD COLUMN published_status VARCHAR(32) NULL AFTER availability,
+	ADD COLUMN lifecycle_status VARCHAR(32) NULL AFTER published_status;
+
+CREATE INDEX idx_wmt_product_details_gtin ON wmt_product_details (gtin);
+CREATE INDEX idx_wmt_product_details_wpid ON wmt_product_details (wpid);
+CREATE INDEX idx_products_upc ON products (upc);
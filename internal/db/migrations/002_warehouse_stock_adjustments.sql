@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS warehouse_stock_adjustments (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	product_id INT NOT NULL,
+	seller_sku VARCHAR(64) NOT NULL,
+	reason VARCHAR(32) NOT NULL,
+	delta INT NOT NULL,
+	previous_qty INT NOT NULL,
+	new_qty INT NOT NULL,
+	note VARCHAR(255) NULL,
+	createdAt DATETIME NOT NULL,
+	INDEX idx_warehouse_stock_adjustments_sku (seller_sku, createdAt),
+	CONSTRAINT fk_warehouse_stock_adjustments_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
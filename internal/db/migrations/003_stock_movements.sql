@@ -0,0 +1,50 @@
+-- Append-only ledger of every quantity change. products.warehouse_stock and
+-- wmt_product_details.available_to_sell_qty are kept as cached balances only.
+CREATE TABLE IF NOT EXISTS stock_movements (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	product_id INT NOT NULL,
+	seller_sku VARCHAR(64) NOT NULL,
+	stock_type VARCHAR(16) NOT NULL,
+	source VARCHAR(32) NOT NULL,
+	reason VARCHAR(32) NULL,
+	quantity INT NOT NULL,
+	balance_after INT NOT NULL,
+	reference_id VARCHAR(64) NULL,
+	note VARCHAR(255) NULL,
+	createdAt DATETIME(3) NOT NULL,
+	INDEX idx_stock_movements_product (product_id, stock_type, id),
+	INDEX idx_stock_movements_sku (seller_sku, createdAt),
+	UNIQUE KEY uq_stock_movements_reference (product_id, stock_type, source, reference_id),
+	CONSTRAINT fk_stock_movements_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+
+-- Opening balances so that SUM(quantity) matches the current cached columns.
+-- A product with adjustment history opens just before its first adjustment,
+-- and is inserted ahead of that history, so balances rebuilt for earlier
+-- times and the latest balance_after are both right.
+INSERT INTO stock_movements (product_id, seller_sku, stock_type, source, quantity, balance_after, createdAt)
+SELECT p.id, p.seller_sku, 'warehouse', 'opening_balance',
+	COALESCE(p.warehouse_stock, 0) - COALESCE(h.total, 0),
+	COALESCE(p.warehouse_stock, 0) - COALESCE(h.total, 0),
+	COALESCE(h.first_at - INTERVAL 1000 MICROSECOND, NOW(3))
+FROM products p
+LEFT JOIN (
+	SELECT product_id, SUM(delta) AS total, MIN(createdAt) AS first_at
+	FROM warehouse_stock_adjustments
+	GROUP BY product_id
+) h ON h.product_id = p.id
+WHERE COALESCE(p.warehouse_stock, 0) <> COALESCE(h.total, 0);
+
+-- Keep the history recorded by the old adjustments table.
+INSERT INTO stock_movements (product_id, seller_sku, stock_type, source, reason, quantity, balance_after, note, createdAt)
+SELECT product_id, seller_sku, 'warehouse', 'manual', reason, delta, new_qty, note, createdAt
+FROM warehouse_stock_adjustments
+ORDER BY id;
+
+INSERT INTO stock_movements (product_id, seller_sku, stock_type, source, quantity, balance_after, createdAt)
+SELECT p.id, p.seller_sku, 'walmart', 'opening_balance', d.available_to_sell_qty, d.available_to_sell_qty, NOW(3)
+FROM products p
+INNER JOIN wmt_product_details d ON p.id = d.product_id
+WHERE d.available_to_sell_qty <> 0;
+
+DROP TABLE warehouse_stock_adjustments;
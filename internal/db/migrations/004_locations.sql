@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS locations (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	code VARCHAR(64) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	type VARCHAR(32) NOT NULL,
+	address VARCHAR(255) NULL,
+	is_default TINYINT(1) NOT NULL DEFAULT 0,
+	active TINYINT(1) NOT NULL DEFAULT 1,
+	createdAt DATETIME NOT NULL,
+	updatedAt DATETIME NOT NULL,
+	UNIQUE KEY uq_locations_code (code)
+);
+
+-- Existing warehouse stock lives in the main building; the quantity Walmart
+-- reported so far came from WFS fulfillment centers.
+INSERT INTO locations (code, name, type, is_default, active, createdAt, updatedAt)
+VALUES
+	('MAIN', 'Main warehouse', 'warehouse', 1, 1, NOW(), NOW()),
+	('WFSFulfilled', 'Walmart WFSFulfilled', 'walmart_ship_node', 0, 1, NOW(), NOW());
+
+ALTER TABLE stock_movements ADD COLUMN location_id INT NULL AFTER stock_type;
+
+UPDATE stock_movements m
+INNER JOIN locations l ON l.code = IF(m.stock_type = 'warehouse', 'MAIN', 'WFSFulfilled')
+SET m.location_id = l.id;
+
+ALTER TABLE stock_movements
+	MODIFY location_id INT NOT NULL,
+	DROP INDEX uq_stock_movements_reference,
+	ADD UNIQUE KEY uq_stock_movements_reference (product_id, stock_type, location_id, source, reference_id),
+	ADD INDEX idx_stock_movements_location (product_id, location_id, id),
+	ADD CONSTRAINT fk_stock_movements_location FOREIGN KEY (location_id) REFERENCES locations (id);
+
+-- Cached per-location balances; the ledger remains the source of truth.
+CREATE TABLE IF NOT EXISTS location_stock (
+	product_id INT NOT NULL,
+	location_id INT NOT NULL,
+	quantity INT NOT NULL,
+	updatedAt DATETIME NOT NULL,
+	PRIMARY KEY (product_id, location_id),
+	INDEX idx_location_stock_location (location_id),
+	CONSTRAINT fk_location_stock_product FOREIGN KEY (product_id) REFERENCES products (id),
+	CONSTRAINT fk_location_stock_location FOREIGN KEY (location_id) REFERENCES locations (id)
+);
+
+INSERT INTO location_stock (product_id, location_id, quantity, updatedAt)
+SELECT product_id, location_id, SUM(quantity), NOW()
+FROM stock_movements
+GROUP BY product_id, location_id;
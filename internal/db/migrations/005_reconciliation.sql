@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	sync_run_id VARCHAR(64) NULL,
+	tolerance_units INT NOT NULL,
+	tolerance_percent DECIMAL(6, 2) NOT NULL,
+	total_skus INT NOT NULL,
+	discrepancy_count INT NOT NULL,
+	createdAt DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	run_id BIGINT NOT NULL,
+	product_id INT NOT NULL,
+	seller_sku VARCHAR(64) NOT NULL,
+	product_name VARCHAR(255) NULL,
+	warehouse_stock INT NOT NULL,
+	walmart_available INT NOT NULL,
+	difference INT NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	INDEX idx_reconciliation_discrepancies_run (run_id),
+	CONSTRAINT fk_reconciliation_discrepancies_run FOREIGN KEY (run_id) REFERENCES reconciliation_runs (id) ON DELETE CASCADE
+);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	seller_sku VARCHAR(64) NULL,
+	type VARCHAR(32) NOT NULL,
+	stock_type VARCHAR(16) NOT NULL DEFAULT 'walmart',
+	threshold INT NOT NULL DEFAULT 0,
+	days_of_cover DECIMAL(8, 2) NOT NULL DEFAULT 0,
+	channels VARCHAR(255) NULL,
+	active TINYINT(1) NOT NULL DEFAULT 1,
+	createdAt DATETIME NOT NULL,
+	updatedAt DATETIME NOT NULL,
+	INDEX idx_alert_rules_sku (seller_sku)
+);
+
+-- A rule/SKU pair has at most one open alert; it is resolved once the condition clears.
+CREATE TABLE IF NOT EXISTS alerts (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	rule_id BIGINT NOT NULL,
+	product_id INT NOT NULL,
+	seller_sku VARCHAR(64) NOT NULL,
+	type VARCHAR(32) NOT NULL,
+	message VARCHAR(512) NOT NULL,
+	quantity INT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	createdAt DATETIME NOT NULL,
+	notifiedAt DATETIME NULL,
+	resolvedAt DATETIME NULL,
+	INDEX idx_alerts_open (status, rule_id, product_id),
+	CONSTRAINT fk_alerts_rule FOREIGN KEY (rule_id) REFERENCES alert_rules (id) ON DELETE CASCADE
+);
+
+INSERT INTO alert_rules (seller_sku, type, stock_type, threshold, active, createdAt, updatedAt)
+VALUES (NULL, 'status_change', 'walmart', 0, 1, NOW(), NOW());
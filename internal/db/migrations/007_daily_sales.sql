@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS daily_sales (
+	seller_sku VARCHAR(64) NOT NULL,
+	sale_date DATE NOT NULL,
+	units INT NOT NULL,
+	orders INT NOT NULL,
+	updatedAt DATETIME NOT NULL,
+	PRIMARY KEY (seller_sku, sale_date),
+	INDEX idx_daily_sales_date (sale_date)
+);
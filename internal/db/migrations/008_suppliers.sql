@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS suppliers (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	email VARCHAR(255) NULL,
+	phone VARCHAR(64) NULL,
+	lead_time_days INT NOT NULL DEFAULT 7,
+	min_order_qty INT NOT NULL DEFAULT 1,
+	active TINYINT(1) NOT NULL DEFAULT 1,
+	createdAt DATETIME NOT NULL,
+	updatedAt DATETIME NOT NULL
+);
+
+-- Per-SKU overrides of the supplier defaults.
+ALTER TABLE products
+	ADD COLUMN lead_time_days INT NULL AFTER product_cost,
+	ADD COLUMN min_order_qty INT NULL AFTER lead_time_days,
+	ADD INDEX idx_products_supplier (supplier_id);
@@ -0,0 +1,53 @@
+CREATE TABLE IF NOT EXISTS purchase_orders (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	po_number VARCHAR(32) NULL,
+	supplier_id INT NOT NULL,
+	status VARCHAR(32) NOT NULL,
+	expected_date DATE NULL,
+	note VARCHAR(255) NULL,
+	createdAt DATETIME NOT NULL,
+	updatedAt DATETIME NOT NULL,
+	closedAt DATETIME NULL,
+	UNIQUE KEY uq_purchase_orders_number (po_number),
+	INDEX idx_purchase_orders_status (status),
+	INDEX idx_purchase_orders_supplier (supplier_id),
+	CONSTRAINT fk_purchase_orders_supplier FOREIGN KEY (supplier_id) REFERENCES suppliers (id)
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	purchase_order_id INT NOT NULL,
+	product_id INT NOT NULL,
+	seller_sku VARCHAR(255) NOT NULL,
+	quantity_ordered INT NOT NULL,
+	quantity_received INT NOT NULL DEFAULT 0,
+	unit_cost DECIMAL(10, 2) NOT NULL DEFAULT 0,
+	expected_date DATE NULL,
+	UNIQUE KEY uq_purchase_order_lines_product (purchase_order_id, product_id),
+	INDEX idx_purchase_order_lines_product (product_id),
+	CONSTRAINT fk_purchase_order_lines_order FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders (id),
+	CONSTRAINT fk_purchase_order_lines_product FOREIGN KEY (product_id) REFERENCES products (id)
+);
+
+-- One row per delivery. The ledger entries it produces use the receipt line ID
+-- as their reference, so a receipt cannot be booked twice.
+CREATE TABLE IF NOT EXISTS purchase_order_receipts (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	purchase_order_id INT NOT NULL,
+	location_id INT NOT NULL,
+	note VARCHAR(255) NULL,
+	receivedAt DATETIME NOT NULL,
+	INDEX idx_purchase_order_receipts_order (purchase_order_id),
+	CONSTRAINT fk_purchase_order_receipts_order FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders (id),
+	CONSTRAINT fk_purchase_order_receipts_location FOREIGN KEY (location_id) REFERENCES locations (id)
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_receipt_lines (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	receipt_id INT NOT NULL,
+	purchase_order_line_id INT NOT NULL,
+	quantity INT NOT NULL,
+	INDEX idx_purchase_order_receipt_lines_receipt (receipt_id),
+	CONSTRAINT fk_purchase_order_receipt_lines_receipt FOREIGN KEY (receipt_id) REFERENCES purchase_order_receipts (id),
+	CONSTRAINT fk_purchase_order_receipt_lines_line FOREIGN KEY (purchase_order_line_id) REFERENCES purchase_order_lines (id)
+);
@@ -0,0 +1,26 @@
+ALTER TABLE daily_sales
+	ADD COLUMN wfs_units INT NOT NULL DEFAULT 0 AFTER units,
+	ADD COLUMN revenue DECIMAL(12, 2) NOT NULL DEFAULT 0 AFTER orders;
+
+-- Stored days have no revenue yet. Emptying the table makes the next orders
+-- sync reload the full history.
+DELETE FROM daily_sales;
+
+ALTER TABLE products
+	ADD COLUMN category VARCHAR(128) NULL AFTER product_name,
+	ADD COLUMN size_tier VARCHAR(32) NULL AFTER category;
+
+-- An empty category or size tier is the fallback used when no specific row matches.
+CREATE TABLE IF NOT EXISTS referral_fees (
+	category VARCHAR(128) NOT NULL PRIMARY KEY,
+	percent DECIMAL(5, 2) NOT NULL,
+	minimum_fee DECIMAL(10, 2) NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS fulfillment_fees (
+	size_tier VARCHAR(32) NOT NULL PRIMARY KEY,
+	fee_per_unit DECIMAL(10, 2) NOT NULL
+);
+
+INSERT INTO referral_fees (category, percent, minimum_fee) VALUES ('', 15.00, 0);
+INSERT INTO fulfillment_fees (size_tier, fee_per_unit) VALUES ('', 3.45);
@@ -0,0 +1,37 @@
+-- Rules mapping Walmart lifecycle/published/availability values to
+-- products.listing_status_id (1 active, 2 out_of_stock, 3 inactive, 4 pending,
+-- 5 delisted, 0 unknown). NULL fields match any value.
+CREATE TABLE IF NOT EXISTS listing_status_rules (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	position INT NOT NULL,
+	lifecycle_status VARCHAR(32) NULL,
+	published_status VARCHAR(32) NULL,
+	availability VARCHAR(32) NULL,
+	listing_status_id INT NOT NULL
+);
+
+INSERT INTO listing_status_rules (position, lifecycle_status, published_status, availability, listing_status_id)
+VALUES
+	(10, 'ACTIVE', 'PUBLISHED', 'In_stock', 1),
+	(20, 'ACTIVE', 'PUBLISHED', 'Out_of_stock', 2),
+	(30, 'ARCHIVED', NULL, NULL, 3),
+	(40, 'RETIRED', NULL, NULL, 3),
+	(50, 'ACTIVE', 'UNPUBLISHED', NULL, 3),
+	(60, 'ACTIVE', 'SYSTEM_PROBLEM', NULL, 3),
+	(70, 'ACTIVE', 'IN_PROGRESS', NULL, 4),
+	(80, 'ACTIVE', 'READY_TO_PUBLISH', NULL, 4),
+	(90, 'ACTIVE', 'STAGE', NULL, 4);
+
+CREATE TABLE IF NOT EXISTS listing_status_history (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	product_id INT NOT NULL,
+	seller_sku VARCHAR(255) NOT NULL,
+	from_status_id INT NOT NULL,
+	to_status_id INT NOT NULL,
+	reason VARCHAR(255) NOT NULL,
+	sync_run_id VARCHAR(36) NULL,
+	createdAt DATETIME NOT NULL,
+	INDEX idx_listing_status_history_product (product_id, createdAt)
+);
+
+UPDATE products SET listing_status_id = 0 WHERE listing_status_id IS NULL;
@@ -0,0 +1,5 @@
+-- Consecutive catalog syncs a product has been missing from, reset whenever
+-- Walmart reports it again.
+ALTER TABLE products
+	ADD COLUMN missing_sync_count INT NOT NULL DEFAULT 0 AFTER listing_status_id,
+	ADD COLUMN last_seen_at DATETIME NULL AFTER missing_sync_count;
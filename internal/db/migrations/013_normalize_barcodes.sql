@@ -0,0 +1,41 @@
+-- Bring stored barcodes to the forms the sync now writes: GTIN-14 in
+-- wmt_product_details.gtin and 12-digit UPC-A in products.upc. Like the sync,
+-- only codes whose GS1 check digit holds are rewritten; invalid codes and
+-- values that are not 8 to 14 digits are left as they are. Leading zeros do
+-- not change the check, so the digits are weighted 3, 1, 3, ... from the left
+-- of the 14-digit form, and the weighted sum of a valid code ends in 0.
+UPDATE wmt_product_details
+SET gtin = LPAD(gtin, 14, '0')
+WHERE gtin REGEXP '^[0-9]{8,13}$' AND (
+	3 * SUBSTRING(LPAD(gtin, 14, '0'), 1, 1) + SUBSTRING(LPAD(gtin, 14, '0'), 2, 1) +
+	3 * SUBSTRING(LPAD(gtin, 14, '0'), 3, 1) + SUBSTRING(LPAD(gtin, 14, '0'), 4, 1) +
+	3 * SUBSTRING(LPAD(gtin, 14, '0'), 5, 1) + SUBSTRING(LPAD(gtin, 14, '0'), 6, 1) +
+	3 * SUBSTRING(LPAD(gtin, 14, '0'), 7, 1) + SUBSTRING(LPAD(gtin, 14, '0'), 8, 1) +
+	3 * SUBSTRING(LPAD(gtin, 14, '0'), 9, 1) + SUBSTRING(LPAD(gtin, 14, '0'), 10, 1) +
+	3 * SUBSTRING(LPAD(gtin, 14, '0'), 11, 1) + SUBSTRING(LPAD(gtin, 14, '0'), 12, 1) +
+	3 * SUBSTRING(LPAD(gtin, 14, '0'), 13, 1) + SUBSTRING(LPAD(gtin, 14, '0'), 14, 1)
+) % 10 = 0;
+
+UPDATE products
+SET upc = LPAD(upc, 12, '0')
+WHERE upc REGEXP '^[0-9]{8,11}$' AND (
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 1, 1) + SUBSTRING(LPAD(upc, 14, '0'), 2, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 3, 1) + SUBSTRING(LPAD(upc, 14, '0'), 4, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 5, 1) + SUBSTRING(LPAD(upc, 14, '0'), 6, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 7, 1) + SUBSTRING(LPAD(upc, 14, '0'), 8, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 9, 1) + SUBSTRING(LPAD(upc, 14, '0'), 10, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 11, 1) + SUBSTRING(LPAD(upc, 14, '0'), 12, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 13, 1) + SUBSTRING(LPAD(upc, 14, '0'), 14, 1)
+) % 10 = 0;
+
+UPDATE products
+SET upc = RIGHT(upc, 12)
+WHERE upc REGEXP '^0{1,2}[0-9]{12}$' AND CHAR_LENGTH(upc) > 12 AND (
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 1, 1) + SUBSTRING(LPAD(upc, 14, '0'), 2, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 3, 1) + SUBSTRING(LPAD(upc, 14, '0'), 4, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 5, 1) + SUBSTRING(LPAD(upc, 14, '0'), 6, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 7, 1) + SUBSTRING(LPAD(upc, 14, '0'), 8, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 9, 1) + SUBSTRING(LPAD(upc, 14, '0'), 10, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 11, 1) + SUBSTRING(LPAD(upc, 14, '0'), 12, 1) +
+	3 * SUBSTRING(LPAD(upc, 14, '0'), 13, 1) + SUBSTRING(LPAD(upc, 14, '0'), 14, 1)
+) % 10 = 0;
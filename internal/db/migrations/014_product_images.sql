@@ -0,0 +1,22 @@
+-- Every image of a product, cached on local disk with a thumbnail. Walmart
+-- images keep the URL they were downloaded from; uploads have none.
+-- products.product_image mirrors the primary image's URL.
+CREATE TABLE IF NOT EXISTS product_images (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	product_id INT NOT NULL,
+	source VARCHAR(16) NOT NULL,
+	source_url VARCHAR(1024) NULL,
+	position INT NOT NULL DEFAULT 0,
+	is_primary TINYINT(1) NOT NULL DEFAULT 0,
+	file_name VARCHAR(255) NOT NULL,
+	thumbnail_name VARCHAR(255) NOT NULL,
+	content_type VARCHAR(64) NOT NULL,
+	width INT NOT NULL,
+	height INT NOT NULL,
+	size_bytes INT NOT NULL,
+	checksum CHAR(64) NOT NULL,
+	createdAt DATETIME NOT NULL,
+	updatedAt DATETIME NOT NULL,
+	UNIQUE KEY uq_product_images_checksum (product_id, checksum),
+	INDEX idx_product_images_product (product_id, position)
+);
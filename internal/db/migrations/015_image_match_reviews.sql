@@ -0,0 +1,17 @@
+-- Catalog items whose title only loosely matches the product. Their images are
+-- downloaded once a person approves the match; a rejected item is never
+-- proposed again for the same product.
+CREATE TABLE IF NOT EXISTS image_match_reviews (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	product_id INT NOT NULL,
+	item_id VARCHAR(64) NULL,
+	candidate_title VARCHAR(512) NOT NULL,
+	method VARCHAR(16) NOT NULL,
+	score DECIMAL(5, 4) NOT NULL,
+	image_urls TEXT NOT NULL,
+	status VARCHAR(16) NOT NULL,
+	createdAt DATETIME NOT NULL,
+	reviewedAt DATETIME NULL,
+	INDEX idx_image_match_reviews_status (status),
+	INDEX idx_image_match_reviews_product (product_id)
+);
@@ -0,0 +1,15 @@
+-- Products waiting for their images to be looked up on Walmart, worked off by
+-- the image backfill job instead of the catalog sync. Failed lookups are
+-- retried with a growing delay until the attempts run out.
+CREATE TABLE IF NOT EXISTS image_backfill_queue (
+	product_id INT PRIMARY KEY,
+	status VARCHAR(16) NOT NULL,
+	attempts INT NOT NULL DEFAULT 0,
+	next_attempt_at DATETIME NOT NULL,
+	last_attempt_at DATETIME NULL,
+	last_outcome VARCHAR(16) NULL,
+	last_error VARCHAR(512) NULL,
+	createdAt DATETIME NOT NULL,
+	updatedAt DATETIME NOT NULL,
+	INDEX idx_image_backfill_queue_due (status, next_attempt_at)
+);
@@ -0,0 +1,12 @@
+-- API keys for clients of the REST API. Only the SHA-256 of each key is kept;
+-- the key itself is shown once, when it is created.
+CREATE TABLE IF NOT EXISTS api_keys (
+	id INT AUTO_INCREMENT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	prefix VARCHAR(16) NOT NULL,
+	key_hash CHAR(64) NOT NULL,
+	createdAt DATETIME NOT NULL,
+	last_used_at DATETIME NULL,
+	revoked_at DATETIME NULL,
+	UNIQUE KEY uq_api_keys_hash (key_hash)
+);
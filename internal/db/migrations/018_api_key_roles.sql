@@ -0,0 +1,6 @@
+-- Roles limit what each API key may do. Keys created before roles existed
+-- could call everything, so they become admins; new keys default to viewer.
+ALTER TABLE api_keys
+	ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'viewer' AFTER prefix;
+
+UPDATE api_keys SET role = 'admin';
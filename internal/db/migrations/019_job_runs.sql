@@ -0,0 +1,12 @@
+-- Every finished run of a background job, so the status endpoint and the
+-- readiness probe know how the jobs went even after a restart.
+CREATE TABLE IF NOT EXISTS job_runs (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	job VARCHAR(64) NOT NULL,
+	run_id VARCHAR(64) NOT NULL,
+	result VARCHAR(16) NOT NULL,
+	error TEXT NULL,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME NOT NULL,
+	INDEX idx_job_runs_job_finished (job, finished_at)
+);
@@ -0,0 +1,85 @@
+package entities
+
+import "time"
+
+type AlertRuleType string
+
+const (
+	// AlertRuleThreshold fires when the quantity drops to or below Threshold.
+	AlertRuleThreshold AlertRuleType = "threshold"
+	// AlertRuleDaysOfCover fires when the quantity covers fewer than DaysOfCover days of sales.
+	AlertRuleDaysOfCover AlertRuleType = "days_of_cover"
+	// AlertRuleStatusChange fires when Walmart flips the listing to out of stock.
+	AlertRuleStatusChange AlertRuleType = "status_change"
+)
+
+func (t AlertRuleType) Valid() bool {
+	switch t {
+	case AlertRuleThreshold, AlertRuleDaysOfCover, AlertRuleStatusChange:
+		return true
+	}
+	return false
+}
+
+type AlertStatus string
+
+const (
+	AlertStatusOpen     AlertStatus = "open"
+	AlertStatusResolved AlertStatus = "resolved"
+)
+
+// AlertRule applies to a single SKU or, when SKU is empty, to every SKU that has
+// no rule of the same type of its own.
+type AlertRule struct {
+	ID          int64         `json:"id"`
+	SKU         string        `json:"sku,omitempty"`
+	Type        AlertRuleType `json:"type"`
+	StockType   StockType     `json:"stockType"`
+	Threshold   int           `json:"threshold"`
+	DaysOfCover float64       `json:"daysOfCover"`
+	Channels    []string      `json:"channels"`
+	Active      bool          `json:"active"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+}
+
+type Alert struct {
+	ID         int64         `json:"id"`
+	RuleID     int64         `json:"ruleId"`
+	ProductID  int64         `json:"productId"`
+	SKU        string        `json:"sku"`
+	Type       AlertRuleType `json:"type"`
+	Message    string        `json:"message"`
+	Quantity   int           `json:"quantity"`
+	Status     AlertStatus   `json:"status"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	NotifiedAt *time.Time    `json:"notifiedAt,omitempty"`
+	ResolvedAt *time.Time    `json:"resolvedAt,omitempty"`
+}
+
+// AlertSubject is the product state alert rules are evaluated against.
+type AlertSubject struct {
+	ProductID        int64         `json:"productId"`
+	SKU              string        `json:"sku"`
+	ProductName      string        `json:"productName"`
+	WarehouseStock   int           `json:"warehouseStock"`
+	WalmartAvailable int           `json:"walmartAvailable"`
+	Availability     string        `json:"availability"`
+	ListingStatusID  ListingStatus `json:"listingStatusId"`
+	DailyVelocity    float64       `json:"dailyVelocity"`
+}
+
+func (s AlertSubject) Quantity(stockType StockType) int {
+	if stockType == StockTypeWarehouse {
+		return s.WarehouseStock
+	}
+	return s.WalmartAvailable
+}
+
+type AlertEvaluation struct {
+	RunID     string `json:"runId,omitempty"`
+	Evaluated int    `json:"evaluated"`
+	Raised    int    `json:"raised"`
+	Notified  int    `json:"notified"`
+	Resolved  int    `json:"resolved"`
+}
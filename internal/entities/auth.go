@@ -0,0 +1,88 @@
+package entities
+
+import "time"
+
+// APIKey is a client credential. Only a hash of the key is stored; Prefix is
+// the start of the key, kept so people can tell their keys apart.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       Role       `json:"role"`
+	Hash       string     `json:"-"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+}
+
+func (k APIKey) Active() bool {
+	return k.RevokedAt == nil
+}
+
+// Role is what a caller may do. Each role includes the permissions of the
+// roles before it.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+// Permissions required by routes: reading data, changing it (stock, prices,
+// running jobs), and administering the service (rules, fees, API keys).
+// PermissionPublic routes need no credentials; it is meant for probes.
+const (
+	PermissionRead   = "read"
+	PermissionWrite  = "write"
+	PermissionAdmin  = "admin"
+	PermissionPublic = "public"
+)
+
+var rolePermissions = map[Role][]string{
+	RoleViewer:   {PermissionRead},
+	RoleOperator: {PermissionRead, PermissionWrite},
+	RoleAdmin:    {PermissionRead, PermissionWrite, PermissionAdmin},
+}
+
+func (r Role) Valid() bool {
+	_, ok := rolePermissions[r]
+	return ok
+}
+
+// Allows reports whether the role grants the permission. Unknown roles and
+// permissions are never allowed.
+func (r Role) Allows(permission string) bool {
+	for _, p := range rolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type AuthMethod string
+
+const (
+	AuthMethodAPIKey AuthMethod = "api_key"
+	AuthMethodJWT    AuthMethod = "jwt"
+)
+
+// Principal is the caller of an authenticated request. Subject is the key
+// name for API keys and the "sub" claim for JWTs.
+type Principal struct {
+	Subject string     `json:"subject"`
+	Method  AuthMethod `json:"method"`
+	Role    Role       `json:"role"`
+	KeyID   int64      `json:"keyId,omitempty"`
+}
+
+// JWTPolicy is how bearer tokens issued by another service are validated.
+// Tokens must be signed with HS256 and Secret; an empty Secret turns JWT
+// authentication off. Issuer and Audience are only checked when set.
+type JWTPolicy struct {
+	Secret   []byte
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
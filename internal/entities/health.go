@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthOK   HealthStatus = "ok"
+	HealthFail HealthStatus = "fail"
+)
+
+// HealthCheck is the outcome of one readiness check.
+type HealthCheck struct {
+	Name    string       `json:"name"`
+	Status  HealthStatus `json:"status"`
+	Message string       `json:"message,omitempty"`
+}
+
+// Readiness is ok only when every check is.
+type Readiness struct {
+	Status    HealthStatus  `json:"status"`
+	Checks    []HealthCheck `json:"checks"`
+	CheckedAt time.Time     `json:"checkedAt"`
+}
+
+// ReadinessPolicy bounds the readiness probe. The service stops being ready
+// when the last successful catalog sync is older than MaxSyncAge; zero turns
+// the check off. Checks still running after Timeout fail.
+type ReadinessPolicy struct {
+	MaxSyncAge time.Duration
+	Timeout    time.Duration
+}
+
+// TokenState describes the cached Walmart access token. LastError is the error
+// of the last token request, nil once a request succeeds again.
+type TokenState struct {
+	ExpiresAt time.Time
+	LastError error
+}
@@ -0,0 +1,175 @@
+package entities
+
+import "time"
+
+type ImageSource string
+
+const (
+	ImageSourceWalmart ImageSource = "walmart"
+	ImageSourceUpload  ImageSource = "upload"
+)
+
+// ProductImage is an image of a product cached on local disk. URL and
+// ThumbnailURL point at this API, not at the original source.
+type ProductImage struct {
+	ID            int64       `json:"id"`
+	ProductID     int64       `json:"productId"`
+	SKU           string      `json:"sku"`
+	Source        ImageSource `json:"source"`
+	SourceURL     string      `json:"sourceUrl,omitempty"`
+	Position      int         `json:"position"`
+	Primary       bool        `json:"primary"`
+	FileName      string      `json:"-"`
+	ThumbnailName string      `json:"-"`
+	ContentType   string      `json:"contentType"`
+	Width         int         `json:"width"`
+	Height        int         `json:"height"`
+	SizeBytes     int64       `json:"sizeBytes"`
+	Checksum      string      `json:"checksum"`
+	URL           string      `json:"url"`
+	ThumbnailURL  string      `json:"thumbnailUrl"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+}
+
+// ImagePolicy limits image downloads and sets when Walmart images are fetched
+// again. Catalog matches scoring at least MatchThreshold are used right away,
+// those scoring at least ReviewThreshold wait for a person to review them and
+// weaker ones are dropped.
+type ImagePolicy struct {
+	MaxBytes        int64
+	RefreshAfter    time.Duration
+	MatchThreshold  float64
+	ReviewThreshold float64
+}
+
+// ImageMatchMethod is how a catalog item was matched to a product.
+type ImageMatchMethod string
+
+const (
+	ImageMatchGTIN  ImageMatchMethod = "gtin"
+	ImageMatchUPC   ImageMatchMethod = "upc"
+	ImageMatchTitle ImageMatchMethod = "title"
+)
+
+// ImageMatch is the Walmart catalog item chosen as the source of a product's
+// images. Score is 1 for barcode matches and the title similarity otherwise.
+type ImageMatch struct {
+	ItemID    string           `json:"itemId"`
+	Title     string           `json:"title"`
+	Method    ImageMatchMethod `json:"method"`
+	Score     float64          `json:"score"`
+	ImageURLs []string         `json:"imageUrls"`
+}
+
+// ImageMatchOutcome is what was done with a catalog match.
+type ImageMatchOutcome string
+
+const (
+	ImageMatchAccepted  ImageMatchOutcome = "accepted"
+	ImageMatchQueued    ImageMatchOutcome = "queued"
+	ImageMatchDiscarded ImageMatchOutcome = "discarded"
+)
+
+type ImageReviewStatus string
+
+const (
+	ImageReviewPending  ImageReviewStatus = "pending"
+	ImageReviewApproved ImageReviewStatus = "approved"
+	ImageReviewRejected ImageReviewStatus = "rejected"
+)
+
+func (s ImageReviewStatus) Valid() bool {
+	switch s {
+	case ImageReviewPending, ImageReviewApproved, ImageReviewRejected:
+		return true
+	}
+	return false
+}
+
+// ImageReview is a low-confidence match kept for a person to approve or reject.
+type ImageReview struct {
+	ID          int64             `json:"id"`
+	ProductID   int64             `json:"productId"`
+	SKU         string            `json:"sku"`
+	ProductName string            `json:"productName"`
+	Match       ImageMatch        `json:"match"`
+	Status      ImageReviewStatus `json:"status"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	ReviewedAt  *time.Time        `json:"reviewedAt"`
+}
+
+type ImageBackfillStatus string
+
+const (
+	// ImageBackfillQueued products are looked up once their next attempt is due.
+	ImageBackfillQueued ImageBackfillStatus = "queued"
+	// ImageBackfillDone products have images; they are queued again when the
+	// Walmart images are due for a refresh.
+	ImageBackfillDone ImageBackfillStatus = "done"
+	// ImageBackfillReview products have a match waiting for review.
+	ImageBackfillReview ImageBackfillStatus = "review"
+	// ImageBackfillExhausted products ran out of attempts and stay until requeued.
+	ImageBackfillExhausted ImageBackfillStatus = "exhausted"
+)
+
+func (s ImageBackfillStatus) Valid() bool {
+	switch s {
+	case ImageBackfillQueued, ImageBackfillDone, ImageBackfillReview, ImageBackfillExhausted:
+		return true
+	}
+	return false
+}
+
+// ImageBackfillItem is a product's entry in the image backfill queue.
+type ImageBackfillItem struct {
+	ProductID     int64               `json:"productId"`
+	SKU           string              `json:"sku"`
+	ProductName   string              `json:"productName"`
+	UPC           string              `json:"-"`
+	GTIN          string              `json:"-"`
+	ProductImage  string              `json:"-"`
+	Status        ImageBackfillStatus `json:"status"`
+	Attempts      int                 `json:"attempts"`
+	NextAttemptAt time.Time           `json:"nextAttemptAt"`
+	LastAttemptAt *time.Time          `json:"lastAttemptAt"`
+	LastOutcome   string              `json:"lastOutcome"`
+	LastError     string              `json:"lastError"`
+	UpdatedAt     time.Time           `json:"updatedAt"`
+}
+
+// ImageBackfillRun summarizes one pass of the backfill job.
+type ImageBackfillRun struct {
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+	Enqueued    int64     `json:"enqueued"`
+	Processed   int       `json:"processed"`
+	Matched     int       `json:"matched"`
+	Review      int       `json:"review"`
+	Retried     int       `json:"retried"`
+	Exhausted   int       `json:"exhausted"`
+	ImagesAdded int       `json:"imagesAdded"`
+}
+
+// ImageBackfillProgress counts the queue by status.
+type ImageBackfillProgress struct {
+	Queued    int               `json:"queued"`
+	Due       int               `json:"due"`
+	Done      int               `json:"done"`
+	Review    int               `json:"review"`
+	Exhausted int               `json:"exhausted"`
+	Total     int               `json:"total"`
+	Running   bool              `json:"running"`
+	LastRun   *ImageBackfillRun `json:"lastRun"`
+}
+
+// ImageBackfillPolicy paces the backfill job. A failed lookup waits RetryBase,
+// doubling with every attempt up to RetryMax, and is given up after
+// MaxAttempts.
+type ImageBackfillPolicy struct {
+	Interval    time.Duration
+	BatchSize   int
+	RetryBase   time.Duration
+	RetryMax    time.Duration
+	MaxAttempts int
+}
@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// Background jobs, as named in logs, metrics and job runs.
+const (
+	JobCatalogSync   = "catalog_sync"
+	JobOrdersSync    = "orders_sync"
+	JobImageBackfill = "image_backfill"
+)
+
+// Jobs lists the background jobs in the order they are reported.
+var Jobs = []string{JobCatalogSync, JobOrdersSync, JobImageBackfill}
+
+type JobResult string
+
+const (
+	JobResultSuccess JobResult = "success"
+	JobResultFailure JobResult = "failure"
+)
+
+// JobRun is one finished run of a background job.
+type JobRun struct {
+	ID         int64     `json:"id"`
+	Job        string    `json:"job"`
+	RunID      string    `json:"runId"`
+	Result     JobResult `json:"result"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// JobStatus is when a job runs next and how its last runs went. NextRunAt is
+// nil for jobs that are not scheduled.
+type JobStatus struct {
+	Job           string     `json:"job"`
+	NextRunAt     *time.Time `json:"nextRunAt"`
+	LastRun       *JobRun    `json:"lastRun"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt"`
+}
+
+// DailySchedule runs a job once a day at Hour:Minute in Location.
+type DailySchedule struct {
+	Location *time.Location
+	Hour     int
+	Minute   int
+}
+
+// ParseDailySchedule reads a time of day such as "15:25" in the named time
+// zone.
+func ParseDailySchedule(at, timezone string) (DailySchedule, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return DailySchedule{}, err
+	}
+
+	var clock time.Time
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if clock, err = time.Parse(layout, at); err == nil {
+			return DailySchedule{Location: location, Hour: clock.Hour(), Minute: clock.Minute()}, nil
+		}
+	}
+	return DailySchedule{}, fmt.Errorf("time of day %q is not HH:MM", at)
+}
+
+// Next is the first run after now.
+func (s DailySchedule) Next(now time.Time) time.Time {
+	now = now.In(s.Location)
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.Hour, s.Minute, 0, 0, s.Location)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"strings"
+	"time"
+)
+
+// ListingStatus is the state of a SKU's Walmart listing, stored as
+// products.listing_status_id.
+type ListingStatus int
+
+const (
+	ListingStatusUnknown    ListingStatus = 0
+	ListingStatusActive     ListingStatus = 1
+	ListingStatusOutOfStock ListingStatus = 2
+	ListingStatusInactive   ListingStatus = 3
+	ListingStatusPending    ListingStatus = 4
+	ListingStatusDelisted   ListingStatus = 5
+)
+
+var listingStatusNames = map[ListingStatus]string{
+	ListingStatusUnknown:    "unknown",
+	ListingStatusActive:     "active",
+	ListingStatusOutOfStock: "out_of_stock",
+	ListingStatusInactive:   "inactive",
+	ListingStatusPending:    "pending",
+	ListingStatusDelisted:   "delisted",
+}
+
+// ListingStatuses lists every status in ID order.
+var ListingStatuses = []ListingStatus{
+	ListingStatusUnknown,
+	ListingStatusActive,
+	ListingStatusOutOfStock,
+	ListingStatusInactive,
+	ListingStatusPending,
+	ListingStatusDelisted,
+}
+
+func (s ListingStatus) String() string {
+	if name, ok := listingStatusNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func (s ListingStatus) Valid() bool {
+	_, ok := listingStatusNames[s]
+	return ok
+}
+
+func ParseListingStatus(name string) (ListingStatus, bool) {
+	for status, n := range listingStatusNames {
+		if strings.EqualFold(n, name) {
+			return status, true
+		}
+	}
+	return ListingStatusUnknown, false
+}
+
+type ListingStatusInfo struct {
+	ID   ListingStatus `json:"id"`
+	Name string        `json:"name"`
+}
+
+// ListingStatusRule maps Walmart's lifecycle, published and availability
+// values to a listing status. Empty fields match any value; rules are tried in
+// Position order and the first match wins.
+type ListingStatusRule struct {
+	ID              int64         `json:"id"`
+	Position        int           `json:"position"`
+	LifecycleStatus string        `json:"lifecycleStatus"`
+	PublishedStatus string        `json:"publishedStatus"`
+	Availability    string        `json:"availability"`
+	StatusID        ListingStatus `json:"statusId"`
+	Status          string        `json:"status"`
+}
+
+// DelistPolicy guards against delisting SKUs because of an incomplete items
+// response. A SKU is delisted after GraceRuns consecutive misses, and no SKU is
+// touched in a run where more than MaxMissingPercent of the catalog is missing.
+type DelistPolicy struct {
+	GraceRuns         int
+	MaxMissingPercent float64
+}
+
+// ListingTransition is one recorded change of a SKU's listing status.
+type ListingTransition struct {
+	ID         int64         `json:"id"`
+	ProductID  int64         `json:"productId"`
+	SKU        string        `json:"sku"`
+	FromID     ListingStatus `json:"fromStatusId"`
+	From       string        `json:"fromStatus"`
+	ToID       ListingStatus `json:"toStatusId"`
+	To         string        `json:"toStatus"`
+	Reason     string        `json:"reason"`
+	SyncRunID  string        `json:"syncRunId,omitempty"`
+	OccurredAt time.Time     `json:"occurredAt"`
+}
@@ -0,0 +1,53 @@
+package entities
+
+import "time"
+
+type LocationType string
+
+const (
+	LocationTypeWarehouse       LocationType = "warehouse"
+	LocationTypeWalmartShipNode LocationType = "walmart_ship_node"
+)
+
+func (t LocationType) Valid() bool {
+	return t == LocationTypeWarehouse || t == LocationTypeWalmartShipNode
+}
+
+// StockType returns the balance that stock held at this kind of location counts towards.
+func (t LocationType) StockType() StockType {
+	if t == LocationTypeWalmartShipNode {
+		return StockTypeWalmart
+	}
+	return StockTypeWarehouse
+}
+
+type Location struct {
+	ID        int64        `json:"id"`
+	Code      string       `json:"code"`
+	Name      string       `json:"name"`
+	Type      LocationType `json:"type"`
+	Address   string       `json:"address"`
+	IsDefault bool         `json:"isDefault"`
+	Active    bool         `json:"active"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// LocationStock is the cached balance of one SKU at one location.
+type LocationStock struct {
+	LocationID   int64        `json:"locationId"`
+	LocationCode string       `json:"locationCode"`
+	LocationName string       `json:"locationName"`
+	LocationType LocationType `json:"locationType"`
+	SKU          string       `json:"sku"`
+	Quantity     int          `json:"quantity"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
+}
+
+// StockByLocation is the per-location and aggregate stock view of a SKU.
+type StockByLocation struct {
+	SKU              string          `json:"sku"`
+	WarehouseStock   int             `json:"warehouseStock"`
+	WalmartAvailable int             `json:"walmartAvailable"`
+	Locations        []LocationStock `json:"locations"`
+}
@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/money"
+)
+
+// ReferralFee is the share of the selling price Walmart keeps for a category.
+type ReferralFee struct {
+	Category   string      `json:"category"`
+	Percent    float64     `json:"percent"`
+	MinimumFee money.Money `json:"minimumFee"`
+}
+
+// FulfillmentFee is the WFS fee charged per unit shipped for a size tier.
+type FulfillmentFee struct {
+	SizeTier   string      `json:"sizeTier"`
+	FeePerUnit money.Money `json:"feePerUnit"`
+}
+
+// FeeSchedule holds the fee tables. Rows with an empty category or size tier
+// apply to SKUs that match no other row.
+type FeeSchedule struct {
+	Referral    []ReferralFee    `json:"referral"`
+	Fulfillment []FulfillmentFee `json:"fulfillment"`
+}
+
+type FeeProfile struct {
+	Category string `json:"category"`
+	SizeTier string `json:"sizeTier"`
+}
+
+// MarginItem is a SKU with its price, cost and sales over the report period.
+type MarginItem struct {
+	SKU         string       `json:"sku"`
+	ProductName string       `json:"productName"`
+	Category    string       `json:"category"`
+	SizeTier    string       `json:"sizeTier"`
+	Price       money.Money  `json:"price"`
+	ProductCost *money.Money `json:"productCost"`
+	UnitsSold   int          `json:"unitsSold"`
+	WFSUnits    int          `json:"wfsUnits"`
+	Revenue     money.Money  `json:"revenue"`
+}
+
+// MarginLine is the profitability of one SKU. Unit figures use the current
+// list price and assume WFS fulfillment; period figures use what was actually
+// sold. Margins are nil when the product cost is unknown.
+type MarginLine struct {
+	SKU            string       `json:"sku"`
+	ProductName    string       `json:"productName"`
+	Category       string       `json:"category"`
+	SizeTier       string       `json:"sizeTier"`
+	Price          money.Money  `json:"price"`
+	ProductCost    *money.Money `json:"productCost"`
+	ReferralFee    money.Money  `json:"referralFee"`
+	FulfillmentFee money.Money  `json:"fulfillmentFee"`
+	UnitMargin     *money.Money `json:"unitMargin"`
+	MarginPercent  *float64     `json:"marginPercent"`
+	UnitsSold      int          `json:"unitsSold"`
+	Revenue        money.Money  `json:"revenue"`
+	PeriodFees     money.Money  `json:"periodFees"`
+	PeriodProfit   *money.Money `json:"periodProfit"`
+}
+
+type MarginReport struct {
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	Lines        []MarginLine `json:"lines"`
+	TotalRevenue money.Totals `json:"totalRevenue"`
+	TotalProfit  money.Totals `json:"totalProfit"`
+	MissingCost  int          `json:"missingCost"`
+	GeneratedAt  time.Time    `json:"generatedAt"`
+}
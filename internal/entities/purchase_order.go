@@ -0,0 +1,97 @@
+package entities
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/money"
+)
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusOpen              PurchaseOrderStatus = "open"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusClosed            PurchaseOrderStatus = "closed"
+)
+
+func (s PurchaseOrderStatus) Valid() bool {
+	switch s {
+	case PurchaseOrderStatusOpen, PurchaseOrderStatusPartiallyReceived, PurchaseOrderStatusReceived, PurchaseOrderStatusClosed:
+		return true
+	}
+	return false
+}
+
+// Inbound reports whether the order still counts towards inbound stock.
+func (s PurchaseOrderStatus) Inbound() bool {
+	return s == PurchaseOrderStatusOpen || s == PurchaseOrderStatusPartiallyReceived
+}
+
+type PurchaseOrder struct {
+	ID           int64               `json:"id"`
+	Number       string              `json:"number"`
+	SupplierID   int64               `json:"supplierId"`
+	SupplierName string              `json:"supplierName"`
+	Status       PurchaseOrderStatus `json:"status"`
+	ExpectedDate *time.Time          `json:"expectedDate"`
+	Note         string              `json:"note"`
+	Lines        []PurchaseOrderLine `json:"lines"`
+	TotalUnits   int                 `json:"totalUnits"`
+	TotalCost    money.Money         `json:"totalCost"`
+	CreatedAt    time.Time           `json:"createdAt"`
+	UpdatedAt    time.Time           `json:"updatedAt"`
+	ClosedAt     *time.Time          `json:"closedAt"`
+}
+
+// PurchaseOrderLine is one SKU on an order. A line-level expected date
+// overrides the order's when part of the order ships later.
+type PurchaseOrderLine struct {
+	ID               int64       `json:"id"`
+	ProductID        int64       `json:"productId"`
+	SKU              string      `json:"sku"`
+	ProductName      string      `json:"productName"`
+	QuantityOrdered  int         `json:"quantityOrdered"`
+	QuantityReceived int         `json:"quantityReceived"`
+	UnitCost         money.Money `json:"unitCost"`
+	ExpectedDate     *time.Time  `json:"expectedDate"`
+}
+
+// Outstanding is the quantity still to be delivered.
+func (l PurchaseOrderLine) Outstanding() int {
+	if l.QuantityReceived >= l.QuantityOrdered {
+		return 0
+	}
+	return l.QuantityOrdered - l.QuantityReceived
+}
+
+type PurchaseOrderFilter struct {
+	Status     PurchaseOrderStatus
+	SupplierID int64
+}
+
+// PurchaseOrderReceipt is a delivery against an order. Lines without a SKU in
+// a request mean every outstanding line is received in full.
+type PurchaseOrderReceipt struct {
+	ID              int64                      `json:"id"`
+	PurchaseOrderID int64                      `json:"purchaseOrderId"`
+	Location        string                     `json:"location"`
+	Note            string                     `json:"note"`
+	Lines           []PurchaseOrderReceiptLine `json:"lines"`
+	ReceivedAt      time.Time                  `json:"receivedAt"`
+}
+
+type PurchaseOrderReceiptLine struct {
+	ID       int64  `json:"id"`
+	LineID   int64  `json:"lineId"`
+	SKU      string `json:"sku"`
+	Quantity int    `json:"quantity"`
+}
+
+// InboundStock is the quantity of a SKU still expected on open purchase orders.
+type InboundStock struct {
+	SKU              string     `json:"sku"`
+	ProductName      string     `json:"productName"`
+	Quantity         int        `json:"quantity"`
+	PurchaseOrders   int        `json:"purchaseOrders"`
+	NextExpectedDate *time.Time `json:"nextExpectedDate"`
+}
@@ -0,0 +1,42 @@
+package entities
+
+import "time"
+
+type DiscrepancyStatus string
+
+const (
+	// DiscrepancyWalmartHigher means Walmart shows more available units than we hold.
+	DiscrepancyWalmartHigher DiscrepancyStatus = "walmart_higher"
+	// DiscrepancyWalmartLower means we hold units that Walmart does not offer.
+	DiscrepancyWalmartLower DiscrepancyStatus = "walmart_lower"
+)
+
+type ReconciliationTolerance struct {
+	Units   int     `json:"units"`
+	Percent float64 `json:"percent"`
+}
+
+// StockLevel is the pair of balances compared by the reconciliation engine.
+type StockLevel struct {
+	ProductID        int64  `json:"productId"`
+	SKU              string `json:"sku"`
+	ProductName      string `json:"productName"`
+	WarehouseStock   int    `json:"warehouseStock"`
+	WalmartAvailable int    `json:"walmartAvailable"`
+}
+
+type ReconciliationItem struct {
+	StockLevel
+	Difference int               `json:"difference"`
+	Status     DiscrepancyStatus `json:"status"`
+}
+
+type ReconciliationRun struct {
+	ID               int64                   `json:"id"`
+	SyncRunID        string                  `json:"syncRunId,omitempty"`
+	Tolerance        ReconciliationTolerance `json:"tolerance"`
+	TotalSKUs        int                     `json:"totalSkus"`
+	DiscrepancyCount int                     `json:"discrepancyCount"`
+	CreatedAt        time.Time               `json:"createdAt"`
+	Items            []ReconciliationItem    `json:"items"`
+}
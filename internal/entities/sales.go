@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/money"
+)
+
+// DailySales totals a SKU's orders for one day. WFSUnits is the part of Units
+// fulfilled by WFS, and Revenue the item price of the units not cancelled.
+type DailySales struct {
+	SKU      string      `json:"sku"`
+	Date     time.Time   `json:"date"`
+	Units    int         `json:"units"`
+	WFSUnits int         `json:"wfsUnits"`
+	Orders   int         `json:"orders"`
+	Revenue  money.Money `json:"revenue"`
+}
+
+type Forecast struct {
+	SKU                string     `json:"sku"`
+	Velocity7          float64    `json:"velocity7d"`
+	Velocity30         float64    `json:"velocity30d"`
+	Velocity90         float64    `json:"velocity90d"`
+	ForecastDailyUnits float64    `json:"forecastDailyUnits"`
+	WalmartAvailable   int        `json:"walmartAvailable"`
+	WarehouseStock     int        `json:"warehouseStock"`
+	DaysOfCover        *float64   `json:"daysOfCover"`
+	TotalDaysOfCover   *float64   `json:"totalDaysOfCover"`
+	ProjectedStockout  *time.Time `json:"projectedStockout"`
+	GeneratedAt        time.Time  `json:"generatedAt"`
+}
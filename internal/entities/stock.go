@@ -0,0 +1,103 @@
+package entities
+
+import "time"
+
+type StockReason string
+
+const (
+	StockReasonReceipt    StockReason = "receipt"
+	StockReasonAdjustment StockReason = "adjustment"
+	StockReasonDamage     StockReason = "damage"
+	StockReasonCount      StockReason = "count"
+)
+
+func (r StockReason) Valid() bool {
+	switch r {
+	case StockReasonReceipt, StockReasonAdjustment, StockReasonDamage, StockReasonCount:
+		return true
+	}
+	return false
+}
+
+// StockType identifies which balance a movement belongs to: our own warehouse
+// stock or the quantity Walmart reports as available to sell.
+type StockType string
+
+const (
+	StockTypeWarehouse StockType = "warehouse"
+	StockTypeWalmart   StockType = "walmart"
+)
+
+func (t StockType) Valid() bool {
+	return t == StockTypeWarehouse || t == StockTypeWalmart
+}
+
+type MovementSource string
+
+const (
+	MovementSourceOpeningBalance MovementSource = "opening_balance"
+	MovementSourceWalmartSync    MovementSource = "walmart_sync"
+	MovementSourceManual         MovementSource = "manual"
+	MovementSourceOrder          MovementSource = "order"
+	MovementSourceReturn         MovementSource = "return"
+	MovementSourcePurchaseOrder  MovementSource = "purchase_order"
+)
+
+// StockChange is a requested change to a product's warehouse stock. Either Delta
+// is applied to the current quantity or, when SetTo is present, the quantity is
+// replaced outright.
+type StockChange struct {
+	SKU      string      `json:"sku"`
+	Location string      `json:"location,omitempty"`
+	Delta    int         `json:"delta"`
+	SetTo    *int        `json:"setTo,omitempty"`
+	Reason   StockReason `json:"reason"`
+	Note     string      `json:"note"`
+}
+
+// StockMovement is one append-only ledger entry. The balance of a SKU at a
+// location is the sum of Quantity over its movements; BalanceAfter caches that
+// running total at the time the entry was written.
+type StockMovement struct {
+	ID           int64          `json:"id"`
+	ProductID    int64          `json:"productId"`
+	SKU          string         `json:"sku"`
+	StockType    StockType      `json:"stockType"`
+	LocationID   int64          `json:"locationId"`
+	LocationCode string         `json:"location"`
+	Source       MovementSource `json:"source"`
+	Reason       StockReason    `json:"reason,omitempty"`
+	Quantity     int            `json:"quantity"`
+	BalanceAfter int            `json:"balanceAfter"`
+	ReferenceID  string         `json:"referenceId,omitempty"`
+	Note         string         `json:"note,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+}
+
+type MovementFilter struct {
+	SKU       string
+	StockType StockType
+	Location  string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+// StockSnapshot is the ledger-derived stock of a SKU at a point in time, next to
+// the balances currently cached on the product.
+type StockSnapshot struct {
+	SKU              string          `json:"sku"`
+	At               time.Time       `json:"at"`
+	WarehouseStock   int             `json:"warehouseStock"`
+	WalmartAvailable int             `json:"walmartAvailable"`
+	Locations        []LocationStock `json:"locations"`
+}
+
+type LedgerCheck struct {
+	SKU                    string `json:"sku"`
+	LedgerWarehouseStock   int    `json:"ledgerWarehouseStock"`
+	RecordedWarehouseStock int    `json:"recordedWarehouseStock"`
+	LedgerWalmartQty       int    `json:"ledgerWalmartQty"`
+	RecordedWalmartQty     int    `json:"recordedWalmartQty"`
+	InSync                 bool   `json:"inSync"`
+}
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/money"
+)
+
+type Supplier struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Phone        string    `json:"phone"`
+	LeadTimeDays int       `json:"leadTimeDays"`
+	MinOrderQty  int       `json:"minOrderQty"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// SupplySettings links a SKU to its supplier. Nil lead time and MOQ fall back
+// to the supplier defaults.
+type SupplySettings struct {
+	SupplierID         *int64       `json:"supplierId"`
+	SupplierItemNumber string       `json:"supplierItemNumber"`
+	ProductCost        *money.Money `json:"productCost"`
+	LeadTimeDays       *int         `json:"leadTimeDays"`
+	MinOrderQty        *int         `json:"minOrderQty"`
+}
+
+// SupplyItem is a SKU with its supplier terms and current stock, as read by the
+// reorder engine.
+type SupplyItem struct {
+	ProductID          int64       `json:"productId"`
+	SKU                string      `json:"sku"`
+	ProductName        string      `json:"productName"`
+	SupplierID         int64       `json:"supplierId"`
+	SupplierItemNumber string      `json:"supplierItemNumber"`
+	ProductCost        money.Money `json:"productCost"`
+	LeadTimeDays       int         `json:"leadTimeDays"`
+	MinOrderQty        int         `json:"minOrderQty"`
+	WarehouseStock     int         `json:"warehouseStock"`
+	WalmartAvailable   int         `json:"walmartAvailable"`
+}
+
+type ReorderLine struct {
+	SKU                string      `json:"sku"`
+	ProductName        string      `json:"productName"`
+	SupplierItemNumber string      `json:"supplierItemNumber"`
+	DailyVelocity      float64     `json:"dailyVelocity"`
+	AvailableStock     int         `json:"availableStock"`
+	InboundStock       int         `json:"inboundStock"`
+	LeadTimeDays       int         `json:"leadTimeDays"`
+	ReorderPoint       int         `json:"reorderPoint"`
+	TargetStock        int         `json:"targetStock"`
+	SuggestedQty       int         `json:"suggestedQty"`
+	UnitCost           money.Money `json:"unitCost"`
+	LineTotal          money.Money `json:"lineTotal"`
+}
+
+// ReorderSuggestion groups the lines to order from one supplier, which is the
+// shape of a draft purchase order.
+type ReorderSuggestion struct {
+	Supplier    Supplier      `json:"supplier"`
+	Lines       []ReorderLine `json:"lines"`
+	TotalUnits  int           `json:"totalUnits"`
+	TotalCost   money.Money   `json:"totalCost"`
+	GeneratedAt time.Time     `json:"generatedAt"`
+}
+
+type ReorderPolicy struct {
+	SafetyStockDays int `json:"safetyStockDays"`
+	CoverageDays    int `json:"coverageDays"`
+}
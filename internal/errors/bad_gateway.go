@@ -0,0 +1,27 @@
+package errors
+
+import "net/http"
+
+// BadGateway is a failure of a service we depend on, such as the Walmart API
+// or a notification channel, rather than of this one.
+type BadGateway struct {
+	message string
+}
+
+func NewBadGateway(message string) BadGateway {
+	return BadGateway{
+		message: message,
+	}
+}
+
+func (e BadGateway) Error() string {
+	return e.message
+}
+
+func (e BadGateway) StatusCode() int {
+	return http.StatusBadGateway
+}
+
+func (e BadGateway) Code() string {
+	return "bad_gateway"
+}
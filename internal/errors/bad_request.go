@@ -1,7 +1,16 @@
 package errors
 
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"io"
+	"net/http"
+	"strconv"
+)
+
 type BadRequest struct {
 	message string
+	details interface{}
 }
 
 func NewBadRequest(message string) BadRequest {
@@ -10,6 +19,63 @@ func NewBadRequest(message string) BadRequest {
 	}
 }
 
+// NewBadRequestWithDetails adds data that helps the client fix the request,
+// such as the offending field, to the error response.
+func NewBadRequestWithDetails(message string, details interface{}) BadRequest {
+	return BadRequest{
+		message: message,
+		details: details,
+	}
+}
+
+// NewInvalidBody describes why a request body could not be decoded. Bodies
+// cut off by the size limit are a PayloadTooLarge rather than a BadRequest.
+func NewInvalidBody(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case stdErrors.As(err, &tooLarge):
+		return NewPayloadTooLarge("request body is larger than " + strconv.FormatInt(tooLarge.Limit, 10) + " bytes")
+	case stdErrors.Is(err, io.EOF):
+		return NewBadRequest("request body is required")
+	case stdErrors.As(err, &syntaxErr):
+		return NewBadRequestWithDetails("invalid request body: malformed JSON", map[string]interface{}{
+			"offset": syntaxErr.Offset,
+		})
+	case stdErrors.As(err, &typeErr):
+		return NewBadRequestWithDetails("invalid request body: wrong type for "+describeField(typeErr), map[string]interface{}{
+			"field":    typeErr.Field,
+			"expected": typeErr.Type.String(),
+			"got":      typeErr.Value,
+		})
+	default:
+		return NewBadRequestWithDetails("invalid request body", map[string]interface{}{
+			"reason": err.Error(),
+		})
+	}
+}
+
+func describeField(err *json.UnmarshalTypeError) string {
+	if err.Field == "" {
+		return "the body"
+	}
+	return err.Field
+}
+
 func (e BadRequest) Error() string {
 	return e.message
-}
\ No newline at end of file
+}
+
+func (e BadRequest) StatusCode() int {
+	return http.StatusBadRequest
+}
+
+func (e BadRequest) Code() string {
+	return "bad_request"
+}
+
+func (e BadRequest) Details() interface{} {
+	return e.details
+}
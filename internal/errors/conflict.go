@@ -0,0 +1,27 @@
+package errors
+
+import "net/http"
+
+// Conflict is returned when a request cannot run in the current state, such as
+// a job that is already running.
+type Conflict struct {
+	message string
+}
+
+func NewConflict(message string) Conflict {
+	return Conflict{
+		message: message,
+	}
+}
+
+func (e Conflict) Error() string {
+	return e.message
+}
+
+func (e Conflict) StatusCode() int {
+	return http.StatusConflict
+}
+
+func (e Conflict) Code() string {
+	return "conflict"
+}
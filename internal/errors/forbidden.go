@@ -0,0 +1,25 @@
+package errors
+
+import "net/http"
+
+type Forbidden struct {
+	message string
+}
+
+func NewForbidden(message string) Forbidden {
+	return Forbidden{
+		message: message,
+	}
+}
+
+func (e Forbidden) Error() string {
+	return e.message
+}
+
+func (e Forbidden) StatusCode() int {
+	return http.StatusForbidden
+}
+
+func (e Forbidden) Code() string {
+	return "forbidden"
+}
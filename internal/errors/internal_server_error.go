@@ -1,5 +1,7 @@
 package errors
 
+import "net/http"
+
 type InternalServerError struct {
 	message string
 }
@@ -12,4 +14,12 @@ func NewInternalServerError(message string) InternalServerError {
 
 func (e InternalServerError) Error() string {
 	return e.message
-}
\ No newline at end of file
+}
+
+func (e InternalServerError) StatusCode() int {
+	return http.StatusInternalServerError
+}
+
+func (e InternalServerError) Code() string {
+	return "internal_error"
+}
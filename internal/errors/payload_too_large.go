@@ -0,0 +1,25 @@
+package errors
+
+import "net/http"
+
+type PayloadTooLarge struct {
+	message string
+}
+
+func NewPayloadTooLarge(message string) PayloadTooLarge {
+	return PayloadTooLarge{
+		message: message,
+	}
+}
+
+func (e PayloadTooLarge) Error() string {
+	return e.message
+}
+
+func (e PayloadTooLarge) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+func (e PayloadTooLarge) Code() string {
+	return "payload_too_large"
+}
@@ -1,5 +1,7 @@
 package errors
 
+import "net/http"
+
 type ResourceNotFound struct {
 	message string
 }
@@ -12,4 +14,12 @@ func NewResourceNotFound(message string) ResourceNotFound {
 
 func (e ResourceNotFound) Error() string {
 	return e.message
-}
\ No newline at end of file
+}
+
+func (e ResourceNotFound) StatusCode() int {
+	return http.StatusNotFound
+}
+
+func (e ResourceNotFound) Code() string {
+	return "not_found"
+}
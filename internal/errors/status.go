@@ -0,0 +1,16 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"net/http"
+)
+
+// StatusCode returns the HTTP status that corresponds to a typed error, falling
+// back to 500 for anything that is not one of ours.
+func StatusCode(err error) int {
+	var typed interface{ StatusCode() int }
+	if stdErrors.As(err, &typed) {
+		return typed.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
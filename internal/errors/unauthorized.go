@@ -0,0 +1,25 @@
+package errors
+
+import "net/http"
+
+type Unauthorized struct {
+	message string
+}
+
+func NewUnauthorized(message string) Unauthorized {
+	return Unauthorized{
+		message: message,
+	}
+}
+
+func (e Unauthorized) Error() string {
+	return e.message
+}
+
+func (e Unauthorized) StatusCode() int {
+	return http.StatusUnauthorized
+}
+
+func (e Unauthorized) Code() string {
+	return "unauthorized"
+}
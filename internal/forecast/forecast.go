@@ -0,0 +1,65 @@
+package forecast
+
+import (
+	"math"
+	"time"
+)
+
+// Velocity returns the average daily units over the last days entries of a
+// daily series ordered from oldest to newest. Missing history counts as zero.
+func Velocity(series []int, days int) float64 {
+	if days <= 0 {
+		return 0
+	}
+
+	total := 0
+	for i := len(series) - 1; i >= 0 && i >= len(series)-days; i-- {
+		total += series[i]
+	}
+	return float64(total) / float64(days)
+}
+
+// Smooth applies simple exponential smoothing to a daily series and returns the
+// final level, which is used as the forecast of daily demand. alpha must be in
+// (0, 1]; larger values react faster to recent days.
+func Smooth(series []int, alpha float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	if alpha <= 0 || alpha > 1 {
+		alpha = 0.3
+	}
+
+	level := float64(series[0])
+	for _, units := range series[1:] {
+		level = alpha*float64(units) + (1-alpha)*level
+	}
+	return level
+}
+
+// DaysOfCover returns how many days quantity lasts at rate units per day, or
+// nil when there is no demand to deplete it.
+func DaysOfCover(quantity int, rate float64) *float64 {
+	if rate <= 0 {
+		return nil
+	}
+	days := Round(math.Max(float64(quantity), 0)/rate, 1)
+	return &days
+}
+
+// StockoutDate projects the day quantity runs out at rate units per day, or nil
+// when there is no demand. The day is midnight in loc, not in UTC.
+func StockoutDate(from time.Time, quantity int, rate float64, loc *time.Location) *time.Time {
+	cover := DaysOfCover(quantity, rate)
+	if cover == nil {
+		return nil
+	}
+	t := from.Add(time.Duration(*cover * float64(24*time.Hour))).In(loc)
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	return &date
+}
+
+func Round(value float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(value*p) / p
+}
@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/alert"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewAlertDefault(sv alert.AlertService) *AlertDefault {
+	return &AlertDefault{sv: sv}
+}
+
+type AlertDefault struct {
+	sv alert.AlertService
+}
+
+func (h *AlertDefault) FindAll(w http.ResponseWriter, r *http.Request) error {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	alerts, err := h.sv.Alerts(entities.AlertStatus(r.URL.Query().Get("status")), limit)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, alerts)
+	return nil
+}
+
+func (h *AlertDefault) Evaluate(w http.ResponseWriter, r *http.Request) error {
+	result, err := h.sv.Evaluate("")
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, result)
+	return nil
+}
+
+func (h *AlertDefault) Rules(w http.ResponseWriter, r *http.Request) error {
+	rules, err := h.sv.Rules()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, rules)
+	return nil
+}
+
+func (h *AlertDefault) CreateRule(w http.ResponseWriter, r *http.Request) error {
+	var body entities.AlertRule
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	rule, err := h.sv.CreateRule(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, rule)
+	return nil
+}
+
+func (h *AlertDefault) DeleteRule(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid rule id")
+	}
+
+	if err := h.sv.DeleteRule(id); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *AlertDefault) Channels(w http.ResponseWriter, r *http.Request) error {
+	response.JSON(w, http.StatusOK, h.sv.Channels())
+	return nil
+}
+
+func (h *AlertDefault) TestChannel(w http.ResponseWriter, r *http.Request) error {
+	name := chi.URLParam(r, "name")
+	if err := h.sv.TestChannel(name); err != nil {
+		// Anything but a typed error means the channel itself failed.
+		if appErrors.StatusCode(err) == http.StatusInternalServerError {
+			return appErrors.NewBadGateway(err.Error())
+		}
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, map[string]string{"channel": name, "status": "delivered"})
+	return nil
+}
@@ -0,0 +1,96 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/auth"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewAPIKeyDefault(sv auth.AuthService) *APIKeyDefault {
+	return &APIKeyDefault{sv: sv}
+}
+
+type APIKeyDefault struct {
+	sv auth.AuthService
+}
+
+type createRequest struct {
+	Name string        `json:"name"`
+	Role entities.Role `json:"role"`
+}
+
+type roleRequest struct {
+	Role entities.Role `json:"role"`
+}
+
+// createdKey is the only response that includes the key itself.
+type createdKey struct {
+	*entities.APIKey
+	Key string `json:"key"`
+}
+
+func (h *APIKeyDefault) FindAll(w http.ResponseWriter, r *http.Request) error {
+	keys, err := h.sv.Keys()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, keys)
+	return nil
+}
+
+func (h *APIKeyDefault) Create(w http.ResponseWriter, r *http.Request) error {
+	var body createRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	key, plain, err := h.sv.CreateKey(body.Name, body.Role)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, createdKey{APIKey: key, Key: plain})
+	return nil
+}
+
+func (h *APIKeyDefault) SetRole(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid API key id")
+	}
+
+	var body roleRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	key, err := h.sv.SetKeyRole(id, body.Role)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, key)
+	return nil
+}
+
+func (h *APIKeyDefault) Revoke(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid API key id")
+	}
+
+	key, err := h.sv.RevokeKey(id)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, key)
+	return nil
+}
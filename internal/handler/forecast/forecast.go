@@ -0,0 +1,37 @@
+package forecast
+
+import (
+	"net/http"
+	"walmart-inventory-manager/internal/service/forecast"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewForecastDefault(sv forecast.ForecastService) *ForecastDefault {
+	return &ForecastDefault{sv: sv}
+}
+
+type ForecastDefault struct {
+	sv forecast.ForecastService
+}
+
+func (h *ForecastDefault) FindAll(w http.ResponseWriter, r *http.Request) error {
+	forecasts, err := h.sv.ForecastAll()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, forecasts)
+	return nil
+}
+
+func (h *ForecastDefault) FindBySKU(w http.ResponseWriter, r *http.Request) error {
+	forecast, err := h.sv.ForecastSKU(chi.URLParam(r, "sku"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, forecast)
+	return nil
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"net/http"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/service/health"
+	"walmart-inventory-manager/platform/web/response"
+)
+
+func NewHealthDefault(sv health.HealthService) *HealthDefault {
+	return &HealthDefault{sv: sv}
+}
+
+type HealthDefault struct {
+	sv health.HealthService
+}
+
+// Live answers as long as the process can serve requests.
+func (h *HealthDefault) Live(w http.ResponseWriter, r *http.Request) error {
+	response.JSON(w, http.StatusOK, map[string]entities.HealthStatus{"status": entities.HealthOK})
+	return nil
+}
+
+// Ready answers 503 with the failed checks when the service should not get
+// traffic.
+func (h *HealthDefault) Ready(w http.ResponseWriter, r *http.Request) error {
+	readiness := h.sv.Ready()
+
+	status := http.StatusOK
+	if readiness.Status != entities.HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	response.JSON(w, status, readiness)
+	return nil
+}
+
+func (h *HealthDefault) Status(w http.ResponseWriter, r *http.Request) error {
+	statuses, err := h.sv.Status()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, statuses)
+	return nil
+}
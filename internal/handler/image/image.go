@@ -0,0 +1,231 @@
+package image
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/image"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxUploadMemory is how much of a multipart upload is held in memory; the
+// service enforces the actual size limit.
+const maxUploadMemory = 32 << 20
+
+func NewImageDefault(sv image.ImageService) *ImageDefault {
+	return &ImageDefault{sv: sv}
+}
+
+type ImageDefault struct {
+	sv image.ImageService
+}
+
+type requeueRequest struct {
+	SKUs []string `json:"skus"`
+}
+
+type imageURLRequest struct {
+	URL     string `json:"url"`
+	Primary bool   `json:"primary"`
+}
+
+func (h *ImageDefault) List(w http.ResponseWriter, r *http.Request) error {
+	images, err := h.sv.List(chi.URLParam(r, "sku"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, images)
+	return nil
+}
+
+// Add stores a new image for the SKU, either uploaded as the multipart field
+// "file" or downloaded from the URL in a JSON body.
+func (h *ImageDefault) Add(w http.ResponseWriter, r *http.Request) error {
+	sku := chi.URLParam(r, "sku")
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var body imageURLRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return appErrors.NewInvalidBody(err)
+		}
+
+		img, err := h.sv.AddFromURL(sku, body.URL, body.Primary)
+		if err != nil {
+			return err
+		}
+
+		response.JSON(w, http.StatusCreated, img)
+		return nil
+	}
+
+	if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return appErrors.NewBadRequest("file is required")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+	primary, _ := strconv.ParseBool(r.FormValue("primary"))
+
+	img, err := h.sv.Upload(sku, data, primary)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, img)
+	return nil
+}
+
+func (h *ImageDefault) Serve(w http.ResponseWriter, r *http.Request) error {
+	return h.serve(w, r, false)
+}
+
+func (h *ImageDefault) Thumbnail(w http.ResponseWriter, r *http.Request) error {
+	return h.serve(w, r, true)
+}
+
+func (h *ImageDefault) serve(w http.ResponseWriter, r *http.Request, thumbnail bool) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid image id")
+	}
+
+	file, err := h.sv.Open(chi.URLParam(r, "sku"), id, thumbnail)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Files are named after their checksum, so a cached copy never goes stale.
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Header().Set("ETag", `"`+file.Image.Checksum+`"`)
+	http.ServeContent(w, r, "", file.Image.UpdatedAt, file)
+	return nil
+}
+
+func (h *ImageDefault) SetPrimary(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid image id")
+	}
+
+	images, err := h.sv.SetPrimary(chi.URLParam(r, "sku"), id)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, images)
+	return nil
+}
+
+func (h *ImageDefault) Delete(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid image id")
+	}
+
+	if err := h.sv.Delete(chi.URLParam(r, "sku"), id); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *ImageDefault) Reviews(w http.ResponseWriter, r *http.Request) error {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	status := entities.ImageReviewStatus(r.URL.Query().Get("status"))
+
+	reviews, err := h.sv.Reviews(status, limit)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, reviews)
+	return nil
+}
+
+func (h *ImageDefault) ApproveReview(w http.ResponseWriter, r *http.Request) error {
+	return h.review(w, r, h.sv.ApproveReview)
+}
+
+func (h *ImageDefault) RejectReview(w http.ResponseWriter, r *http.Request) error {
+	return h.review(w, r, h.sv.RejectReview)
+}
+
+func (h *ImageDefault) review(w http.ResponseWriter, r *http.Request, decide func(int64) (*entities.ImageReview, error)) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid review id")
+	}
+
+	review, err := decide(id)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, review)
+	return nil
+}
+
+func (h *ImageDefault) BackfillProgress(w http.ResponseWriter, r *http.Request) error {
+	progress, err := h.sv.BackfillProgress()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, progress)
+	return nil
+}
+
+func (h *ImageDefault) Backfills(w http.ResponseWriter, r *http.Request) error {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	status := entities.ImageBackfillStatus(r.URL.Query().Get("status"))
+
+	items, err := h.sv.Backfills(status, limit)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, items)
+	return nil
+}
+
+func (h *ImageDefault) RunBackfill(w http.ResponseWriter, r *http.Request) error {
+	run, err := h.sv.RunBackfill()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, run)
+	return nil
+}
+
+func (h *ImageDefault) Requeue(w http.ResponseWriter, r *http.Request) error {
+	var body requeueRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	progress, err := h.sv.Requeue(body.SKUs)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusAccepted, progress)
+	return nil
+}
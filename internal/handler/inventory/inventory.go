@@ -2,8 +2,11 @@ package inventory
 
 import (
 	"net/http"
+	"walmart-inventory-manager/internal/entities"
 	"walmart-inventory-manager/internal/service/inventory"
 	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
 )
 
 func NewInventoryDefault(sv inventory.InventoryService) *InventoryDefault {
@@ -17,10 +20,35 @@ type InventoryDefault struct {
 func (h *InventoryDefault) FindAll(w http.ResponseWriter, r *http.Request) error {
 	products, err := h.sv.FindAll()
 	if err != nil {
-		response.Error(w, http.StatusInternalServerError, "Error al obtener los productos")
 		return err
 	}
 
 	response.JSON(w, http.StatusOK, products)
 	return nil
 }
+
+func (h *InventoryDefault) FindBySKU(w http.ResponseWriter, r *http.Request) error {
+	return h.findOne(w, h.sv.FindBySKU, chi.URLParam(r, "sku"))
+}
+
+func (h *InventoryDefault) FindByGTIN(w http.ResponseWriter, r *http.Request) error {
+	return h.findOne(w, h.sv.FindByGTIN, chi.URLParam(r, "gtin"))
+}
+
+func (h *InventoryDefault) FindByUPC(w http.ResponseWriter, r *http.Request) error {
+	return h.findOne(w, h.sv.FindByUPC, chi.URLParam(r, "upc"))
+}
+
+func (h *InventoryDefault) FindByWPID(w http.ResponseWriter, r *http.Request) error {
+	return h.findOne(w, h.sv.FindByWPID, chi.URLParam(r, "wpid"))
+}
+
+func (h *InventoryDefault) findOne(w http.ResponseWriter, find func(string) (*entities.Product, error), value string) error {
+	product, err := find(value)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, product)
+	return nil
+}
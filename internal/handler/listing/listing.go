@@ -0,0 +1,63 @@
+package listing
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/listing"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewListingDefault(sv listing.ListingService) *ListingDefault {
+	return &ListingDefault{sv: sv}
+}
+
+type ListingDefault struct {
+	sv listing.ListingService
+}
+
+func (h *ListingDefault) Statuses(w http.ResponseWriter, r *http.Request) error {
+	response.JSON(w, http.StatusOK, h.sv.Statuses())
+	return nil
+}
+
+func (h *ListingDefault) Rules(w http.ResponseWriter, r *http.Request) error {
+	rules, err := h.sv.Rules()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, rules)
+	return nil
+}
+
+func (h *ListingDefault) ReplaceRules(w http.ResponseWriter, r *http.Request) error {
+	var body []entities.ListingStatusRule
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	rules, err := h.sv.ReplaceRules(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, rules)
+	return nil
+}
+
+func (h *ListingDefault) History(w http.ResponseWriter, r *http.Request) error {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	history, err := h.sv.History(chi.URLParam(r, "sku"), limit)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, history)
+	return nil
+}
@@ -0,0 +1,90 @@
+package location
+
+import (
+	"encoding/json"
+	"net/http"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/location"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewLocationDefault(sv location.LocationService) *LocationDefault {
+	return &LocationDefault{sv: sv}
+}
+
+type LocationDefault struct {
+	sv location.LocationService
+}
+
+func (h *LocationDefault) FindAll(w http.ResponseWriter, r *http.Request) error {
+	locations, err := h.sv.FindAll()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, locations)
+	return nil
+}
+
+func (h *LocationDefault) FindByCode(w http.ResponseWriter, r *http.Request) error {
+	location, err := h.sv.FindByCode(chi.URLParam(r, "code"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, location)
+	return nil
+}
+
+func (h *LocationDefault) Create(w http.ResponseWriter, r *http.Request) error {
+	var body entities.Location
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	location, err := h.sv.Create(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, location)
+	return nil
+}
+
+func (h *LocationDefault) Update(w http.ResponseWriter, r *http.Request) error {
+	var body entities.Location
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	location, err := h.sv.Update(chi.URLParam(r, "code"), body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, location)
+	return nil
+}
+
+func (h *LocationDefault) Stock(w http.ResponseWriter, r *http.Request) error {
+	stock, err := h.sv.Stock(chi.URLParam(r, "code"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, stock)
+	return nil
+}
+
+func (h *LocationDefault) StockBySKU(w http.ResponseWriter, r *http.Request) error {
+	stock, err := h.sv.StockBySKU(chi.URLParam(r, "sku"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, stock)
+	return nil
+}
@@ -0,0 +1,157 @@
+package margin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/money"
+	"walmart-inventory-manager/internal/service/margin"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewMarginDefault(sv margin.MarginService) *MarginDefault {
+	return &MarginDefault{sv: sv}
+}
+
+type MarginDefault struct {
+	sv margin.MarginService
+}
+
+// Report returns per-SKU margins for ?from=&to= (YYYY-MM-DD), as CSV when
+// requested through ?format=csv or the Accept header.
+func (h *MarginDefault) Report(w http.ResponseWriter, r *http.Request) error {
+	from, err := parseDate(r.URL.Query().Get("from"))
+	if err != nil {
+		return err
+	}
+	to, err := parseDate(r.URL.Query().Get("to"))
+	if err != nil {
+		return err
+	}
+
+	report, err := h.sv.Report(from, to)
+	if err != nil {
+		return err
+	}
+
+	if !response.WantsCSV(r) {
+		response.JSON(w, http.StatusOK, report)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(report.Lines))
+	for _, line := range report.Lines {
+		rows = append(rows, []string{
+			line.SKU,
+			line.ProductName,
+			line.Category,
+			line.SizeTier,
+			formatMoney(line.Price),
+			formatOptional(line.ProductCost),
+			formatMoney(line.ReferralFee),
+			formatMoney(line.FulfillmentFee),
+			formatOptional(line.UnitMargin),
+			formatPercent(line.MarginPercent),
+			strconv.Itoa(line.UnitsSold),
+			formatMoney(line.Revenue),
+			formatMoney(line.PeriodFees),
+			formatOptional(line.PeriodProfit),
+			line.Price.Currency(),
+		})
+	}
+
+	header := []string{"sku", "product_name", "category", "size_tier", "price", "product_cost", "referral_fee", "fulfillment_fee", "unit_margin", "margin_percent", "units_sold", "revenue", "period_fees", "period_profit", "currency"}
+	filename := fmt.Sprintf("margins-%s-%s.csv", report.From.Format("20060102"), report.To.Format("20060102"))
+	response.CSV(w, filename, header, rows)
+	return nil
+}
+
+func (h *MarginDefault) FeeSchedule(w http.ResponseWriter, r *http.Request) error {
+	schedule, err := h.sv.FeeSchedule()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, schedule)
+	return nil
+}
+
+func (h *MarginDefault) ReplaceReferralFees(w http.ResponseWriter, r *http.Request) error {
+	var body []entities.ReferralFee
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	schedule, err := h.sv.ReplaceReferralFees(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, schedule)
+	return nil
+}
+
+func (h *MarginDefault) ReplaceFulfillmentFees(w http.ResponseWriter, r *http.Request) error {
+	var body []entities.FulfillmentFee
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	schedule, err := h.sv.ReplaceFulfillmentFees(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, schedule)
+	return nil
+}
+
+func (h *MarginDefault) SetFeeProfile(w http.ResponseWriter, r *http.Request) error {
+	var body entities.FeeProfile
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	profile, err := h.sv.SetFeeProfile(chi.URLParam(r, "sku"), body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, profile)
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, appErrors.NewBadRequest("invalid date " + value + ": expected YYYY-MM-DD")
+	}
+	return t, nil
+}
+
+func formatMoney(value money.Money) string {
+	return value.Decimal()
+}
+
+func formatOptional(value *money.Money) string {
+	if value == nil {
+		return ""
+	}
+	return value.Decimal()
+}
+
+func formatPercent(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', 2, 64)
+}
@@ -0,0 +1,195 @@
+package purchaseorder
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/money"
+	"walmart-inventory-manager/internal/service/purchaseorder"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewPurchaseOrderDefault(sv purchaseorder.PurchaseOrderService) *PurchaseOrderDefault {
+	return &PurchaseOrderDefault{sv: sv}
+}
+
+type PurchaseOrderDefault struct {
+	sv purchaseorder.PurchaseOrderService
+}
+
+type purchaseOrderRequest struct {
+	SupplierID   int64                  `json:"supplierId"`
+	ExpectedDate string                 `json:"expectedDate"`
+	Note         string                 `json:"note"`
+	Lines        []purchaseOrderLineReq `json:"lines"`
+}
+
+type purchaseOrderLineReq struct {
+	SKU          string      `json:"sku"`
+	Quantity     int         `json:"quantity"`
+	UnitCost     money.Money `json:"unitCost"`
+	ExpectedDate string      `json:"expectedDate"`
+}
+
+func (h *PurchaseOrderDefault) FindAll(w http.ResponseWriter, r *http.Request) error {
+	filter := entities.PurchaseOrderFilter{
+		Status: entities.PurchaseOrderStatus(r.URL.Query().Get("status")),
+	}
+	if value := r.URL.Query().Get("supplierId"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return appErrors.NewBadRequest("invalid supplier id")
+		}
+		filter.SupplierID = id
+	}
+
+	orders, err := h.sv.FindAll(filter)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, orders)
+	return nil
+}
+
+func (h *PurchaseOrderDefault) FindByID(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid purchase order id")
+	}
+
+	order, err := h.sv.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, order)
+	return nil
+}
+
+func (h *PurchaseOrderDefault) Create(w http.ResponseWriter, r *http.Request) error {
+	var body purchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	order := entities.PurchaseOrder{SupplierID: body.SupplierID, Note: body.Note}
+	expected, err := parseDate(body.ExpectedDate)
+	if err != nil {
+		return err
+	}
+	order.ExpectedDate = expected
+
+	for _, line := range body.Lines {
+		expected, err := parseDate(line.ExpectedDate)
+		if err != nil {
+			return err
+		}
+		order.Lines = append(order.Lines, entities.PurchaseOrderLine{
+			SKU:             line.SKU,
+			QuantityOrdered: line.Quantity,
+			UnitCost:        line.UnitCost,
+			ExpectedDate:    expected,
+		})
+	}
+
+	created, err := h.sv.Create(order)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, created)
+	return nil
+}
+
+func (h *PurchaseOrderDefault) CreateFromDraft(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid supplier id")
+	}
+
+	order, err := h.sv.CreateFromDraft(id)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, order)
+	return nil
+}
+
+// Receive books a delivery. An empty body receives everything outstanding at
+// the default warehouse.
+func (h *PurchaseOrderDefault) Receive(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid purchase order id")
+	}
+
+	var body entities.PurchaseOrderReceipt
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return appErrors.NewInvalidBody(err)
+		}
+	}
+
+	receipt, err := h.sv.Receive(id, body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, receipt)
+	return nil
+}
+
+func (h *PurchaseOrderDefault) Close(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid purchase order id")
+	}
+
+	order, err := h.sv.Close(id)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, order)
+	return nil
+}
+
+func (h *PurchaseOrderDefault) Inbound(w http.ResponseWriter, r *http.Request) error {
+	inbound, err := h.sv.Inbound(r.URL.Query().Get("sku"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, inbound)
+	return nil
+}
+
+func (h *PurchaseOrderDefault) InboundBySKU(w http.ResponseWriter, r *http.Request) error {
+	inbound, err := h.sv.Inbound(chi.URLParam(r, "sku"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, inbound)
+	return nil
+}
+
+func parseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return &t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	return nil, appErrors.NewBadRequest("invalid date " + value + ": expected YYYY-MM-DD")
+}
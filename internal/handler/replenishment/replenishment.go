@@ -0,0 +1,66 @@
+package replenishment
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/replenishment"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewReplenishmentDefault(sv replenishment.ReplenishmentService) *ReplenishmentDefault {
+	return &ReplenishmentDefault{sv: sv}
+}
+
+type ReplenishmentDefault struct {
+	sv replenishment.ReplenishmentService
+}
+
+func (h *ReplenishmentDefault) Suggestions(w http.ResponseWriter, r *http.Request) error {
+	suggestions, err := h.sv.Suggestions()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, suggestions)
+	return nil
+}
+
+// DraftPurchaseOrder returns the reorder lines of one supplier, as CSV when
+// requested through ?format=csv or the Accept header.
+func (h *ReplenishmentDefault) DraftPurchaseOrder(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid supplier id")
+	}
+
+	draft, err := h.sv.DraftPurchaseOrder(id)
+	if err != nil {
+		return err
+	}
+
+	if !response.WantsCSV(r) {
+		response.JSON(w, http.StatusOK, draft)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(draft.Lines))
+	for _, line := range draft.Lines {
+		rows = append(rows, []string{
+			line.SKU,
+			line.SupplierItemNumber,
+			line.ProductName,
+			strconv.Itoa(line.SuggestedQty),
+			line.UnitCost.Decimal(),
+			line.LineTotal.Decimal(),
+		})
+	}
+
+	header := []string{"sku", "supplier_item_number", "product_name", "quantity", "unit_cost", "line_total"}
+	filename := fmt.Sprintf("draft-po-%d-%s.csv", draft.Supplier.ID, draft.GeneratedAt.Format("20060102"))
+	response.CSV(w, filename, header, rows)
+	return nil
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"net/http"
+	"strconv"
+	"walmart-inventory-manager/internal/service/reconciliation"
+	"walmart-inventory-manager/platform/web/response"
+)
+
+func NewReportDefault(reconciliation reconciliation.ReconciliationService) *ReportDefault {
+	return &ReportDefault{reconciliation: reconciliation}
+}
+
+type ReportDefault struct {
+	reconciliation reconciliation.ReconciliationService
+}
+
+func (h *ReportDefault) Reconciliation(w http.ResponseWriter, r *http.Request) error {
+	run, err := h.reconciliation.Latest()
+	if err != nil {
+		return err
+	}
+
+	if !response.WantsCSV(r) {
+		response.JSON(w, http.StatusOK, run)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(run.Items))
+	for _, item := range run.Items {
+		rows = append(rows, []string{
+			item.SKU,
+			item.ProductName,
+			strconv.Itoa(item.WarehouseStock),
+			strconv.Itoa(item.WalmartAvailable),
+			strconv.Itoa(item.Difference),
+			string(item.Status),
+		})
+	}
+
+	filename := "reconciliation_" + run.CreatedAt.Format("2006-01-02_15-04-05") + ".csv"
+	header := []string{"sku", "product_name", "warehouse_stock", "walmart_available", "difference", "status"}
+	response.CSV(w, filename, header, rows)
+	return nil
+}
+
+func (h *ReportDefault) RunReconciliation(w http.ResponseWriter, r *http.Request) error {
+	run, err := h.reconciliation.Reconcile("")
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, run)
+	return nil
+}
@@ -0,0 +1,164 @@
+package stock
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/stock"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewStockDefault(sv stock.StockService) *StockDefault {
+	return &StockDefault{sv: sv}
+}
+
+type StockDefault struct {
+	sv stock.StockService
+}
+
+type setStockRequest struct {
+	Location string               `json:"location"`
+	Quantity *int                 `json:"quantity"`
+	Reason   entities.StockReason `json:"reason"`
+	Note     string               `json:"note"`
+}
+
+type adjustStockRequest struct {
+	Location string               `json:"location"`
+	Delta    int                  `json:"delta"`
+	Reason   entities.StockReason `json:"reason"`
+	Note     string               `json:"note"`
+}
+
+func (h *StockDefault) SetWarehouseStock(w http.ResponseWriter, r *http.Request) error {
+	var body setStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+	if body.Quantity == nil {
+		return appErrors.NewBadRequest("quantity is required")
+	}
+
+	adjustment, err := h.sv.SetWarehouseStock(chi.URLParam(r, "sku"), body.Location, *body.Quantity, body.Reason, body.Note)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, adjustment)
+	return nil
+}
+
+func (h *StockDefault) AdjustWarehouseStock(w http.ResponseWriter, r *http.Request) error {
+	var body adjustStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	adjustment, err := h.sv.AdjustWarehouseStock(chi.URLParam(r, "sku"), body.Location, body.Delta, body.Reason, body.Note)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, adjustment)
+	return nil
+}
+
+func (h *StockDefault) BulkAdjust(w http.ResponseWriter, r *http.Request) error {
+	var body []entities.StockChange
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	adjustments, err := h.sv.BulkAdjust(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, adjustments)
+	return nil
+}
+
+func (h *StockDefault) RecordMovements(w http.ResponseWriter, r *http.Request) error {
+	var body []entities.StockMovement
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	movements, err := h.sv.RecordMovements(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, movements)
+	return nil
+}
+
+func (h *StockDefault) Movements(w http.ResponseWriter, r *http.Request) error {
+	query := r.URL.Query()
+	filter := entities.MovementFilter{
+		SKU:       chi.URLParam(r, "sku"),
+		StockType: entities.StockType(query.Get("type")),
+		Location:  query.Get("location"),
+	}
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+
+	var err error
+	if filter.From, err = parseTime(query.Get("from")); err != nil {
+		return err
+	}
+	if filter.To, err = parseTime(query.Get("to")); err != nil {
+		return err
+	}
+
+	movements, err := h.sv.Movements(filter)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, movements)
+	return nil
+}
+
+func (h *StockDefault) BalanceAt(w http.ResponseWriter, r *http.Request) error {
+	at, err := parseTime(r.URL.Query().Get("at"))
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := h.sv.BalanceAt(chi.URLParam(r, "sku"), at)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, snapshot)
+	return nil
+}
+
+func (h *StockDefault) CheckLedger(w http.ResponseWriter, r *http.Request) error {
+	check, err := h.sv.CheckLedger(chi.URLParam(r, "sku"))
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, check)
+	return nil
+}
+
+// parseTime accepts RFC 3339 timestamps or plain dates; an empty value yields the zero time.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, appErrors.NewBadRequest("invalid time " + value + ": expected RFC 3339 or YYYY-MM-DD")
+}
@@ -0,0 +1,96 @@
+package supplier
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/service/supplier"
+	"walmart-inventory-manager/platform/web/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewSupplierDefault(sv supplier.SupplierService) *SupplierDefault {
+	return &SupplierDefault{sv: sv}
+}
+
+type SupplierDefault struct {
+	sv supplier.SupplierService
+}
+
+func (h *SupplierDefault) FindAll(w http.ResponseWriter, r *http.Request) error {
+	suppliers, err := h.sv.FindAll()
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, suppliers)
+	return nil
+}
+
+func (h *SupplierDefault) FindByID(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid supplier id")
+	}
+
+	supplier, err := h.sv.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, supplier)
+	return nil
+}
+
+func (h *SupplierDefault) Create(w http.ResponseWriter, r *http.Request) error {
+	var body entities.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	supplier, err := h.sv.Create(body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusCreated, supplier)
+	return nil
+}
+
+func (h *SupplierDefault) Update(w http.ResponseWriter, r *http.Request) error {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		return appErrors.NewBadRequest("invalid supplier id")
+	}
+
+	var body entities.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	supplier, err := h.sv.Update(id, body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, supplier)
+	return nil
+}
+
+func (h *SupplierDefault) AssignProduct(w http.ResponseWriter, r *http.Request) error {
+	var body entities.SupplySettings
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return appErrors.NewInvalidBody(err)
+	}
+
+	product, err := h.sv.AssignProduct(chi.URLParam(r, "sku"), body)
+	if err != nil {
+		return err
+	}
+
+	response.JSON(w, http.StatusOK, product)
+	return nil
+}
@@ -0,0 +1,158 @@
+package imagematch
+
+import (
+	"strings"
+	"unicode"
+	"walmart-inventory-manager/internal/barcode"
+	"walmart-inventory-manager/internal/entities"
+)
+
+// Query is the product an image is searched for.
+type Query struct {
+	ProductName string
+	UPC         string
+	GTIN        string
+}
+
+// Candidate is an item returned by the Walmart catalog search.
+type Candidate struct {
+	ItemID    string
+	Title     string
+	UPC       string
+	GTIN      string
+	ImageURLs []string
+}
+
+// Best picks the candidate most likely to be the queried product. A candidate
+// whose GTIN or UPC is the same number as the product's wins outright with a
+// score of 1; otherwise the title with the highest similarity is returned,
+// whatever its score, so the caller can decide what to do with a weak match.
+// Candidates without images are skipped, and nil means none had any.
+func Best(query Query, candidates []Candidate) *entities.ImageMatch {
+	codes := productCodes(query)
+
+	var best *entities.ImageMatch
+	for _, candidate := range candidates {
+		if len(candidate.ImageURLs) == 0 {
+			continue
+		}
+
+		if method, ok := codeMatch(codes, candidate); ok {
+			return newMatch(candidate, method, 1)
+		}
+
+		score := TitleSimilarity(query.ProductName, candidate.Title)
+		if best == nil || score > best.Score {
+			best = newMatch(candidate, entities.ImageMatchTitle, score)
+		}
+	}
+
+	return best
+}
+
+// Decide says what to do with a match of the score: use it when it reaches
+// the match threshold, queue it for review when it reaches the review
+// threshold, and drop it otherwise.
+func Decide(score float64, policy entities.ImagePolicy) entities.ImageMatchOutcome {
+	switch {
+	case score >= policy.MatchThreshold:
+		return entities.ImageMatchAccepted
+	case score >= policy.ReviewThreshold:
+		return entities.ImageMatchQueued
+	default:
+		return entities.ImageMatchDiscarded
+	}
+}
+
+func newMatch(candidate Candidate, method entities.ImageMatchMethod, score float64) *entities.ImageMatch {
+	return &entities.ImageMatch{
+		ItemID:    candidate.ItemID,
+		Title:     candidate.Title,
+		Method:    method,
+		Score:     score,
+		ImageURLs: candidate.ImageURLs,
+	}
+}
+
+// productCodes returns the product's barcodes in canonical GTIN-14 form.
+func productCodes(query Query) map[string]bool {
+	codes := make(map[string]bool, 2)
+	for _, code := range []string{query.GTIN, query.UPC} {
+		if gtin, err := barcode.Parse(code); err == nil {
+			codes[gtin.GTIN14()] = true
+		}
+	}
+	return codes
+}
+
+func codeMatch(codes map[string]bool, candidate Candidate) (entities.ImageMatchMethod, bool) {
+	if gtin, err := barcode.Parse(candidate.GTIN); err == nil && codes[gtin.GTIN14()] {
+		return entities.ImageMatchGTIN, true
+	}
+	if upc, err := barcode.Parse(candidate.UPC); err == nil && codes[upc.GTIN14()] {
+		return entities.ImageMatchUPC, true
+	}
+	return "", false
+}
+
+// TitleSimilarity scores how alike two product titles are, from 0 to 1. It
+// averages the overlap of their words, which ignores word order, with the
+// overlap of their letter pairs, which tolerates typos and plurals. Case,
+// punctuation and repeated words do not count.
+func TitleSimilarity(a, b string) float64 {
+	wordsA, wordsB := words(a), words(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	if strings.Join(wordsA, " ") == strings.Join(wordsB, " ") {
+		return 1
+	}
+
+	return (dice(set(wordsA), set(wordsB)) + dice(bigrams(wordsA), bigrams(wordsB))) / 2
+}
+
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func set(items []string) map[string]int {
+	counts := make(map[string]int, len(items))
+	for _, item := range items {
+		counts[item] = 1
+	}
+	return counts
+}
+
+// bigrams counts the letter pairs within each word.
+func bigrams(words []string) map[string]int {
+	counts := make(map[string]int)
+	for _, word := range words {
+		runes := []rune(word)
+		if len(runes) == 1 {
+			counts[word]++
+			continue
+		}
+		for i := 0; i < len(runes)-1; i++ {
+			counts[string(runes[i:i+2])]++
+		}
+	}
+	return counts
+}
+
+// dice is the Sørensen–Dice coefficient of two multisets.
+func dice(a, b map[string]int) float64 {
+	var shared, total int
+	for item, n := range a {
+		shared += min(n, b[item])
+		total += n
+	}
+	for _, n := range b {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	return 2 * float64(shared) / float64(total)
+}
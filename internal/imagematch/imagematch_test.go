@@ -0,0 +1,136 @@
+package imagematch
+
+import (
+	"math"
+	"testing"
+	"walmart-inventory-manager/internal/entities"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Red Shoe!", "red   shoe", 1},
+		{"red shoe", "shoe red", 1},
+		{"blue mug", "green plate", 0},
+		{"", "anything", 0},
+		{"", "", 0},
+		// Single letters count as their own pair, so words and pairs both
+		// score 2*1/3.
+		{"a", "a b", 2.0 / 3},
+		// No shared word; ht is the one letter pair of eight in common.
+		{"night", "nacht", (0 + 2.0/8) / 2},
+		// Words share coffee (2*1/4), letter pairs 7 of 7 and 8 (14/15).
+		{"coffee mug", "coffee mugs", (0.5 + 14.0/15) / 2},
+	}
+
+	for _, tt := range tests {
+		if got := TitleSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("TitleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBest(t *testing.T) {
+	images := []string{"https://example.com/a.jpg"}
+	query := Query{ProductName: "Coffee Mug", UPC: "036000291452"}
+
+	tests := []struct {
+		name       string
+		query      Query
+		candidates []Candidate
+		wantItem   string
+		wantMethod entities.ImageMatchMethod
+		wantScore  float64
+	}{
+		{
+			name:  "code hit wins over a better title",
+			query: query,
+			candidates: []Candidate{
+				{ItemID: "title", Title: "Coffee Mug", ImageURLs: images},
+				{ItemID: "upc", Title: "Something else", UPC: "36000291452", ImageURLs: images},
+			},
+			wantItem:   "upc",
+			wantMethod: entities.ImageMatchUPC,
+			wantScore:  1,
+		},
+		{
+			name:  "GTIN of the same number",
+			query: query,
+			candidates: []Candidate{
+				{ItemID: "gtin", Title: "Mug", GTIN: "00036000291452", ImageURLs: images},
+			},
+			wantItem:   "gtin",
+			wantMethod: entities.ImageMatchGTIN,
+			wantScore:  1,
+		},
+		{
+			name:  "code hit without images is skipped",
+			query: query,
+			candidates: []Candidate{
+				{ItemID: "upc", UPC: "036000291452"},
+				{ItemID: "title", Title: "Coffee Mugs", ImageURLs: images},
+			},
+			wantItem:   "title",
+			wantMethod: entities.ImageMatchTitle,
+			wantScore:  (0.5 + 14.0/15) / 2,
+		},
+		{
+			name:  "highest title score",
+			query: Query{ProductName: "Coffee Mug"},
+			candidates: []Candidate{
+				{ItemID: "plate", Title: "Dinner Plate", ImageURLs: images},
+				{ItemID: "mug", Title: "coffee mug", ImageURLs: images},
+			},
+			wantItem:   "mug",
+			wantMethod: entities.ImageMatchTitle,
+			wantScore:  1,
+		},
+		{
+			name:       "no candidate with images",
+			query:      query,
+			candidates: []Candidate{{ItemID: "bare", Title: "Coffee Mug"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Best(tt.query, tt.candidates)
+			if tt.wantItem == "" {
+				if got != nil {
+					t.Fatalf("Best = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("Best = nil")
+			}
+			if got.ItemID != tt.wantItem || got.Method != tt.wantMethod || math.Abs(got.Score-tt.wantScore) > 1e-9 {
+				t.Errorf("Best = %s/%s/%v, want %s/%s/%v", got.ItemID, got.Method, got.Score, tt.wantItem, tt.wantMethod, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestDecide(t *testing.T) {
+	policy := entities.ImagePolicy{MatchThreshold: 0.75, ReviewThreshold: 0.4}
+
+	tests := []struct {
+		score float64
+		want  entities.ImageMatchOutcome
+	}{
+		{1, entities.ImageMatchAccepted},
+		{0.75, entities.ImageMatchAccepted},
+		{0.7499, entities.ImageMatchQueued},
+		{0.4, entities.ImageMatchQueued},
+		{0.3999, entities.ImageMatchDiscarded},
+		{0, entities.ImageMatchDiscarded},
+	}
+
+	for _, tt := range tests {
+		if got := Decide(tt.score, policy); got != tt.want {
+			t.Errorf("Decide(%v) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package imagestore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format, expected JPEG, PNG or GIF")
+	ErrTooLarge         = errors.New("image is too large")
+	ErrTooManyPixels    = errors.New("image has too many pixels")
+)
+
+var contentTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+	"gif":  "image/gif",
+}
+
+var extensions = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"gif":  ".gif",
+}
+
+// Stored describes an image written to the store. File names are relative to
+// the store directory.
+type Stored struct {
+	FileName      string
+	ThumbnailName string
+	ContentType   string
+	Width         int
+	Height        int
+	SizeBytes     int64
+	Checksum      string
+}
+
+// Store keeps original images and their JPEG thumbnails on local disk, one
+// directory per product. Files are named after their SHA-256 checksum, so
+// saving the same image twice is harmless.
+type Store struct {
+	dir           string
+	thumbnailSize int
+	maxPixels     int
+}
+
+// New opens a store in dir. Images with more than maxPixels pixels are
+// refused before they are decoded, since a few kilobytes of compressed data
+// can declare dimensions that take gigabytes to decode.
+func New(dir string, thumbnailSize, maxPixels int) *Store {
+	if thumbnailSize <= 0 {
+		thumbnailSize = 200
+	}
+	return &Store{dir: dir, thumbnailSize: thumbnailSize, maxPixels: maxPixels}
+}
+
+// Save decodes data, writes it unchanged together with a thumbnail that fits in
+// a thumbnailSize square, and returns what was stored.
+func (s *Store) Save(productID int64, data []byte) (*Stored, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+	if int64(config.Width)*int64(config.Height) > int64(s.maxPixels) {
+		return nil, fmt.Errorf("%w: %dx%d exceeds the %d pixel limit", ErrTooManyPixels, config.Width, config.Height, s.maxPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	productDir := strconv.FormatInt(productID, 10)
+
+	stored := &Stored{
+		FileName:      filepath.Join(productDir, checksum+extensions[format]),
+		ThumbnailName: filepath.Join(productDir, checksum+"_thumb.jpg"),
+		ContentType:   contentType,
+		Width:         img.Bounds().Dx(),
+		Height:        img.Bounds().Dy(),
+		SizeBytes:     int64(len(data)),
+		Checksum:      checksum,
+	}
+
+	if err := os.MkdirAll(filepath.Join(s.dir, productDir), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create image directory: %w", err)
+	}
+	if err := writeFile(filepath.Join(s.dir, stored.FileName), data); err != nil {
+		return nil, err
+	}
+
+	var thumbnail bytes.Buffer
+	if err := jpeg.Encode(&thumbnail, Thumbnail(img, s.thumbnailSize), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := writeFile(filepath.Join(s.dir, stored.ThumbnailName), thumbnail.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// Open opens a stored file by the name Save returned.
+func (s *Store) Open(name string) (*os.File, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Remove deletes stored files, ignoring those that are already gone.
+func (s *Store) Remove(names ...string) error {
+	for _, name := range names {
+		path, err := s.path(name)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) path(name string) (string, error) {
+	clean := filepath.Clean(name)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid image file name %q", name)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
+
+// writeFile writes through a temporary file so a crash never leaves a
+// truncated image behind.
+func writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create image file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write image file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write image file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Download fetches an image, refusing bodies larger than maxBytes. Use a client
+// from NewClient for URLs that come from users.
+func Download(client *http.Client, url string, maxBytes int64) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: unexpected status %s", url, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	return data, nil
+}
+
+// Thumbnail scales img down to fit in a size x size square, averaging the
+// source pixels behind every thumbnail pixel and flattening transparency onto
+// white. Images that already fit keep their size.
+func Thumbnail(img image.Image, size int) *image.RGBA {
+	bounds := img.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	dstW, dstH := srcW, srcH
+	if srcW > size || srcH > size {
+		if srcW >= srcH {
+			dstW, dstH = size, max(1, srcH*size/srcW)
+		} else {
+			dstW, dstH = max(1, srcW*size/srcH), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			// Colors are alpha-premultiplied, so compositing over white adds
+			// the uncovered share of white.
+			white := 0xffff*n - a
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r + white) / n >> 8),
+				G: uint8((g + white) / n >> 8),
+				B: uint8((b + white) / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
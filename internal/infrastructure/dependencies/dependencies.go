@@ -2,54 +2,290 @@ package dependencies
 
 import (
 	"log"
+	"log/slog"
+	"os"
+	"time"
 	"walmart-inventory-manager/internal/config"
 	"walmart-inventory-manager/internal/db"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/handler/alert"
+	"walmart-inventory-manager/internal/handler/apikey"
+	"walmart-inventory-manager/internal/handler/forecast"
+	"walmart-inventory-manager/internal/handler/health"
+	"walmart-inventory-manager/internal/handler/image"
 	"walmart-inventory-manager/internal/handler/inventory"
-	"walmart-inventory-manager/internal/handler/walmart"
+	"walmart-inventory-manager/internal/handler/listing"
+	"walmart-inventory-manager/internal/handler/location"
+	"walmart-inventory-manager/internal/handler/margin"
+	"walmart-inventory-manager/internal/handler/purchaseorder"
+	"walmart-inventory-manager/internal/handler/replenishment"
+	"walmart-inventory-manager/internal/handler/report"
+	"walmart-inventory-manager/internal/handler/stock"
+	"walmart-inventory-manager/internal/handler/supplier"
+	"walmart-inventory-manager/internal/imagestore"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/notifier"
+	alertRepository "walmart-inventory-manager/internal/repositories/alert"
+	apiKeyRepository "walmart-inventory-manager/internal/repositories/apikey"
+	imageRepository "walmart-inventory-manager/internal/repositories/image"
 	inventoryRepository "walmart-inventory-manager/internal/repositories/inventory"
+	jobRunRepository "walmart-inventory-manager/internal/repositories/jobrun"
+	listingRepository "walmart-inventory-manager/internal/repositories/listing"
+	locationRepository "walmart-inventory-manager/internal/repositories/location"
+	marginRepository "walmart-inventory-manager/internal/repositories/margin"
+	purchaseOrderRepository "walmart-inventory-manager/internal/repositories/purchaseorder"
+	reconciliationRepository "walmart-inventory-manager/internal/repositories/reconciliation"
+	salesRepository "walmart-inventory-manager/internal/repositories/sales"
+	stockRepository "walmart-inventory-manager/internal/repositories/stock"
+	supplierRepository "walmart-inventory-manager/internal/repositories/supplier"
+	alertService "walmart-inventory-manager/internal/service/alert"
+	authService "walmart-inventory-manager/internal/service/auth"
+	forecastService "walmart-inventory-manager/internal/service/forecast"
+	healthService "walmart-inventory-manager/internal/service/health"
+	imageService "walmart-inventory-manager/internal/service/image"
 	inventoryService "walmart-inventory-manager/internal/service/inventory"
+	listingService "walmart-inventory-manager/internal/service/listing"
+	locationService "walmart-inventory-manager/internal/service/location"
+	marginService "walmart-inventory-manager/internal/service/margin"
+	purchaseOrderService "walmart-inventory-manager/internal/service/purchaseorder"
+	reconciliationService "walmart-inventory-manager/internal/service/reconciliation"
+	replenishmentService "walmart-inventory-manager/internal/service/replenishment"
+	stockService "walmart-inventory-manager/internal/service/stock"
+	supplierService "walmart-inventory-manager/internal/service/supplier"
 	walmartClient "walmart-inventory-manager/internal/walmart"
 )
 
 type HandlerContainer struct {
-	InventoryHandler    *inventory.InventoryDefault
-	InventoryRepository inventoryRepository.InventoryRepository
-	WalmartHandler      *walmart.TokenHandler
-	WalmartClient       *walmartClient.Client
+	Config               *config.Config
+	Logger               *slog.Logger
+	InventoryHandler     *inventory.InventoryDefault
+	InventoryRepository  inventoryRepository.InventoryRepository
+	StockHandler         *stock.StockDefault
+	SalesRepository      salesRepository.SalesRepository
+	ForecastHandler      *forecast.ForecastDefault
+	Forecasts            forecastService.ForecastService
+	StockRepository      stockRepository.StockRepository
+	LocationHandler      *location.LocationDefault
+	ReportHandler        *report.ReportDefault
+	Reconciliation       reconciliationService.ReconciliationService
+	AlertHandler         *alert.AlertDefault
+	SupplierHandler      *supplier.SupplierDefault
+	ReplenishmentHandler *replenishment.ReplenishmentDefault
+	PurchaseOrderHandler *purchaseorder.PurchaseOrderDefault
+	MarginHandler        *margin.MarginDefault
+	ListingHandler       *listing.ListingDefault
+	ListingRepository    listingRepository.ListingRepository
+	DelistPolicy         entities.DelistPolicy
+	ImageHandler         *image.ImageDefault
+	Images               imageService.ImageService
+	ImageBackfillPolicy  entities.ImageBackfillPolicy
+	CatalogSyncSchedule  entities.DailySchedule
+	OrdersSyncSchedule   entities.DailySchedule
+	Alerts               alertService.AlertService
+	Auth                 authService.AuthService
+	APIKeyHandler        *apikey.APIKeyDefault
+	HealthHandler        *health.HealthDefault
+	Health               healthService.HealthService
+	WalmartClient        *walmartClient.Client
 }
 
-func NewDependencies() (*HandlerContainer, error) {
+func NewDependencies(cfg *config.Config) (*HandlerContainer, error) {
 
-	cfg := config.NewConfig()
+	logger := logging.New(logging.Config{Level: cfg.LogLevel, Format: cfg.LogFormat}, os.Stdout)
+	slog.SetDefault(logger)
 
 	db, err := db.ConnectDB(cfg)
 	if err != nil {
 		return nil, err
 	}
+	logger.Info("Database connected")
+
+	stockRepo := stockRepository.NewStockRepository(db)
+
+	salesRepo := salesRepository.NewSalesRepository(db)
+
+	catalogSyncSchedule, err := entities.ParseDailySchedule(cfg.CatalogSyncTime, cfg.SchedulerTimezone)
+	if err != nil {
+		return nil, err
+	}
+
+	ordersSyncSchedule, err := entities.ParseDailySchedule(cfg.OrdersSyncTime, cfg.SchedulerTimezone)
+	if err != nil {
+		return nil, err
+	}
+
+	forecastUsecase := forecastService.NewForecastDefault(salesRepo, stockRepo, cfg.ForecastSmoothingAlpha, ordersSyncSchedule.Location)
+
+	forecastHandler := forecast.NewForecastDefault(forecastUsecase)
 
 	inventoryRepo := inventoryRepository.NewInventoryRepository(db)
 
-	inventoryUsecase := inventoryService.NewInventoryDefault(inventoryRepo)
+	inventoryUsecase := inventoryService.NewInventoryDefault(inventoryRepo, forecastUsecase)
 
 	inventoryHandler := inventory.NewInventoryDefault(inventoryUsecase)
 
-	walmart_client, err := walmartClient.NewClient()
+	stockUsecase := stockService.NewStockDefault(stockRepo)
+
+	stockHandler := stock.NewStockDefault(stockUsecase)
+
+	locationRepo := locationRepository.NewLocationRepository(db)
+
+	locationUsecase := locationService.NewLocationDefault(locationRepo, stockRepo)
+
+	locationHandler := location.NewLocationDefault(locationUsecase)
+
+	reconciliationRepo := reconciliationRepository.NewReconciliationRepository(db)
+
+	reconciliationUsecase := reconciliationService.NewReconciliationDefault(reconciliationRepo, stockRepo, entities.ReconciliationTolerance{
+		Units:   cfg.ReconciliationToleranceUnits,
+		Percent: cfg.ReconciliationTolerancePercent,
+	})
+
+	reportHandler := report.NewReportDefault(reconciliationUsecase)
+
+	alertRepo := alertRepository.NewAlertRepository(db)
+
+	alertUsecase := alertService.NewAlertDefault(alertRepo, forecastUsecase, logger, newNotifiers(cfg, logger)...)
+
+	alertHandler := alert.NewAlertDefault(alertUsecase)
+
+	supplierRepo := supplierRepository.NewSupplierRepository(db)
+
+	supplierUsecase := supplierService.NewSupplierDefault(supplierRepo, inventoryRepo)
+
+	supplierHandler := supplier.NewSupplierDefault(supplierUsecase)
+
+	purchaseOrderRepo := purchaseOrderRepository.NewPurchaseOrderRepository(db)
+
+	replenishmentUsecase := replenishmentService.NewReplenishmentDefault(supplierRepo, purchaseOrderRepo, forecastUsecase, entities.ReorderPolicy{
+		SafetyStockDays: cfg.ReorderSafetyStockDays,
+		CoverageDays:    cfg.ReorderCoverageDays,
+	})
+
+	replenishmentHandler := replenishment.NewReplenishmentDefault(replenishmentUsecase)
+
+	purchaseOrderUsecase := purchaseOrderService.NewPurchaseOrderDefault(purchaseOrderRepo, supplierRepo, inventoryRepo, replenishmentUsecase)
+
+	purchaseOrderHandler := purchaseorder.NewPurchaseOrderDefault(purchaseOrderUsecase)
+
+	marginRepo := marginRepository.NewMarginRepository(db)
+
+	marginUsecase := marginService.NewMarginDefault(marginRepo, inventoryRepo)
+
+	marginHandler := margin.NewMarginDefault(marginUsecase)
+
+	listingRepo := listingRepository.NewListingRepository(db)
+
+	listingUsecase := listingService.NewListingDefault(listingRepo, inventoryRepo)
+
+	listingHandler := listing.NewListingDefault(listingUsecase)
+
+	walmart_client, err := walmartClient.NewClient(walmartClient.Credentials{
+		PartnerID:    cfg.WalmartPartnerID,
+		ClientID:     cfg.WalmartClientID,
+		ClientSecret: cfg.WalmartClientSecret,
+	}, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	walmartHandler := walmart.NewTokenHandler(walmart_client)
+	healthUsecase := healthService.NewHealthDefault(jobRunRepository.NewJobRunRepository(db), db, walmart_client, entities.ReadinessPolicy{
+		MaxSyncAge: time.Duration(cfg.ReadyMaxSyncAgeHours) * time.Hour,
+		Timeout:    time.Duration(cfg.ReadyCheckTimeoutMillis) * time.Millisecond,
+	}, logger)
+
+	healthHandler := health.NewHealthDefault(healthUsecase)
+
+	imageRepo := imageRepository.NewImageRepository(db)
+
+	imageBackfillPolicy := entities.ImageBackfillPolicy{
+		Interval:    time.Duration(cfg.ImageBackfillIntervalMinutes) * time.Minute,
+		BatchSize:   cfg.ImageBackfillBatchSize,
+		RetryBase:   time.Duration(cfg.ImageBackfillRetryBaseMinutes) * time.Minute,
+		RetryMax:    time.Duration(cfg.ImageBackfillRetryMaxHours) * time.Hour,
+		MaxAttempts: cfg.ImageBackfillMaxAttempts,
+	}
+
+	imageUsecase := imageService.NewImageDefault(imageRepo, inventoryRepo, imagestore.New(cfg.ImageCacheDir, cfg.ImageThumbnailSize, cfg.ImageMaxPixels), walmart_client, entities.ImagePolicy{
+		MaxBytes:        int64(cfg.ImageMaxBytes),
+		RefreshAfter:    time.Duration(cfg.ImageRefreshDays) * 24 * time.Hour,
+		MatchThreshold:  cfg.ImageMatchThreshold,
+		ReviewThreshold: cfg.ImageReviewThreshold,
+	}, imageBackfillPolicy)
+
+	imageHandler := image.NewImageDefault(imageUsecase)
+
+	authUsecase := authService.NewAuthDefault(apiKeyRepository.NewAPIKeyRepository(db), entities.JWTPolicy{
+		Secret:   []byte(cfg.AuthJWTSecret),
+		Issuer:   cfg.AuthJWTIssuer,
+		Audience: cfg.AuthJWTAudience,
+		Leeway:   time.Duration(cfg.AuthJWTLeewaySeconds) * time.Second,
+	}, logger)
+
+	apiKeyHandler := apikey.NewAPIKeyDefault(authUsecase)
 
 	return &HandlerContainer{
-		InventoryHandler:    inventoryHandler,
-		InventoryRepository: inventoryRepo, 
-		WalmartHandler:      walmartHandler,
+		Config:               cfg,
+		Logger:               logger,
+		InventoryHandler:     inventoryHandler,
+		InventoryRepository:  inventoryRepo,
+		StockHandler:         stockHandler,
+		SalesRepository:      salesRepo,
+		ForecastHandler:      forecastHandler,
+		Forecasts:            forecastUsecase,
+		StockRepository:      stockRepo,
+		LocationHandler:      locationHandler,
+		ReportHandler:        reportHandler,
+		Reconciliation:       reconciliationUsecase,
+		AlertHandler:         alertHandler,
+		Alerts:               alertUsecase,
+		SupplierHandler:      supplierHandler,
+		ReplenishmentHandler: replenishmentHandler,
+		PurchaseOrderHandler: purchaseOrderHandler,
+		MarginHandler:        marginHandler,
+		ListingHandler:       listingHandler,
+		ListingRepository:    listingRepo,
+		DelistPolicy: entities.DelistPolicy{
+			GraceRuns:         cfg.DelistGraceRuns,
+			MaxMissingPercent: cfg.DelistMaxMissingPercent,
+		},
+		ImageHandler:        imageHandler,
+		Images:              imageUsecase,
+		ImageBackfillPolicy: imageBackfillPolicy,
+		CatalogSyncSchedule: catalogSyncSchedule,
+		OrdersSyncSchedule:  ordersSyncSchedule,
+		Auth:                authUsecase,
+		APIKeyHandler:       apiKeyHandler,
+		HealthHandler:       healthHandler,
+		Health:              healthUsecase,
 		WalmartClient:       walmart_client,
 	}, nil
 }
 
-func Start() *HandlerContainer {
-	deps, err := NewDependencies()
+// newNotifiers builds the alert channels that are configured. The log sink is always available.
+func newNotifiers(cfg *config.Config, logger *slog.Logger) []notifier.Notifier {
+	notifiers := []notifier.Notifier{notifier.NewLogNotifier(logger)}
+
+	if cfg.AlertWebhookURL != "" {
+		notifiers = append(notifiers, notifier.NewWebhookNotifier(cfg.AlertWebhookURL, cfg.AlertWebhookSecret))
+	}
+
+	if cfg.SMTPHost != "" {
+		notifiers = append(notifiers, notifier.NewEmailNotifier(notifier.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.AlertEmailFrom,
+			To:       cfg.AlertEmailTo,
+		}))
+	}
+
+	return notifiers
+}
+
+func Start(cfg *config.Config) *HandlerContainer {
+	deps, err := NewDependencies(cfg)
 	if err != nil {
 		log.Fatalf("Error al iniciar las dependencias: %v", err)
 	}
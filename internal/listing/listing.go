@@ -0,0 +1,80 @@
+package listing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+)
+
+// Observation is what the Walmart items API reports about a listing.
+type Observation struct {
+	LifecycleStatus string
+	PublishedStatus string
+	Availability    string
+}
+
+func (o Observation) String() string {
+	return fmt.Sprintf("lifecycle=%s published=%s availability=%s", o.LifecycleStatus, o.PublishedStatus, o.Availability)
+}
+
+// DefaultRules are used when no rules are configured. They keep the mapping
+// the sync always applied and give IN_PROGRESS style listings the pending
+// status instead of unknown.
+var DefaultRules = []entities.ListingStatusRule{
+	{Position: 10, LifecycleStatus: "ACTIVE", PublishedStatus: "PUBLISHED", Availability: "In_stock", StatusID: entities.ListingStatusActive},
+	{Position: 20, LifecycleStatus: "ACTIVE", PublishedStatus: "PUBLISHED", Availability: "Out_of_stock", StatusID: entities.ListingStatusOutOfStock},
+	{Position: 30, LifecycleStatus: "ARCHIVED", StatusID: entities.ListingStatusInactive},
+	{Position: 40, LifecycleStatus: "RETIRED", StatusID: entities.ListingStatusInactive},
+	{Position: 50, LifecycleStatus: "ACTIVE", PublishedStatus: "UNPUBLISHED", StatusID: entities.ListingStatusInactive},
+	{Position: 60, LifecycleStatus: "ACTIVE", PublishedStatus: "SYSTEM_PROBLEM", StatusID: entities.ListingStatusInactive},
+	{Position: 70, LifecycleStatus: "ACTIVE", PublishedStatus: "IN_PROGRESS", StatusID: entities.ListingStatusPending},
+	{Position: 80, LifecycleStatus: "ACTIVE", PublishedStatus: "READY_TO_PUBLISH", StatusID: entities.ListingStatusPending},
+	{Position: 90, LifecycleStatus: "ACTIVE", PublishedStatus: "STAGE", StatusID: entities.ListingStatusPending},
+}
+
+// Resolve returns the status of the first rule matching the observation and
+// the reason to record with it. An observation no rule covers resolves to
+// unknown, and the reason says so.
+func Resolve(rules []entities.ListingStatusRule, obs Observation) (entities.ListingStatus, string) {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+
+	ordered := make([]entities.ListingStatusRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Position < ordered[j].Position })
+
+	for _, rule := range ordered {
+		if matches(rule.LifecycleStatus, obs.LifecycleStatus) &&
+			matches(rule.PublishedStatus, obs.PublishedStatus) &&
+			matches(rule.Availability, obs.Availability) {
+			return rule.StatusID, fmt.Sprintf("%s matched rule at position %d", obs, rule.Position)
+		}
+	}
+
+	return entities.ListingStatusUnknown, fmt.Sprintf("no rule matches %s", obs)
+}
+
+// Missing decides what happens to a SKU that has now been absent from misses
+// consecutive items responses. It is delisted once the grace period is used up.
+func Missing(misses int, policy entities.DelistPolicy) (entities.ListingStatus, string, bool) {
+	reason := fmt.Sprintf("missing from %d consecutive Walmart items responses", misses)
+	if misses < policy.GraceRuns {
+		return entities.ListingStatusUnknown, reason, false
+	}
+	return entities.ListingStatusDelisted, reason, true
+}
+
+// CircuitOpen reports whether too much of the catalog is missing from a run for
+// its misses to be trusted.
+func CircuitOpen(missing, total int, policy entities.DelistPolicy) bool {
+	if total == 0 || missing == 0 {
+		return false
+	}
+	return float64(missing)/float64(total)*100 > policy.MaxMissingPercent
+}
+
+func matches(pattern, value string) bool {
+	return pattern == "" || strings.EqualFold(pattern, value)
+}
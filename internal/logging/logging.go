@@ -0,0 +1,98 @@
+// Package logging builds the service's structured logger. Records are
+// written as JSON or text and never include the values of sensitive fields.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Field names shared by every component, so one job run, request or SKU can
+// be followed through the logs.
+const (
+	KeyJob           = "job"
+	KeyRunID         = "run_id"
+	KeySKU           = "sku"
+	KeyCorrelationID = "correlation_id" // sent to Walmart with every API call
+	KeyError         = "error"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are matched against field names with case, "_" and "-"
+// ignored; a field whose name contains one of them is redacted.
+var sensitiveKeys = []string{
+	"token",
+	"secret",
+	"password",
+	"authorization",
+	"apikey",
+	"credential",
+	"address",
+	"postalcode",
+	"phone",
+	"email",
+}
+
+type Config struct {
+	// Level is debug, info, warn or error. Defaults to info.
+	Level string
+	// Format is json or text. Defaults to json.
+	Format string
+}
+
+// New returns a logger writing to w.
+func New(cfg Config, w io.Writer) *slog.Logger {
+	opts := &slog.HandlerOptions{
+		Level:       ParseLevel(cfg.Level),
+		ReplaceAttr: redact,
+	}
+
+	var handler slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	return slog.New(handler)
+}
+
+// ParseLevel reads a level name, falling back to info.
+func ParseLevel(name string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// Err is the attribute for an error; a nil error is logged as an empty value.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.String(KeyError, "")
+	}
+	return slog.String(KeyError, err.Error())
+}
+
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	if Sensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// Sensitive reports whether a field with this name must not be logged.
+func Sensitive(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(normalized, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package margin
+
+import (
+	"fmt"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/forecast"
+	"walmart-inventory-manager/internal/money"
+)
+
+// ReferralFeeFor returns the referral fee row for a category, falling back to
+// the row with an empty category.
+func ReferralFeeFor(schedule entities.FeeSchedule, category string) entities.ReferralFee {
+	var fallback entities.ReferralFee
+	for _, fee := range schedule.Referral {
+		if fee.Category == category {
+			return fee
+		}
+		if fee.Category == "" {
+			fallback = fee
+		}
+	}
+	return fallback
+}
+
+// FulfillmentFeeFor returns the WFS fee row for a size tier, falling back to
+// the row with an empty size tier.
+func FulfillmentFeeFor(schedule entities.FeeSchedule, sizeTier string) entities.FulfillmentFee {
+	var fallback entities.FulfillmentFee
+	for _, fee := range schedule.Fulfillment {
+		if fee.SizeTier == sizeTier {
+			return fee
+		}
+		if fee.SizeTier == "" {
+			fallback = fee
+		}
+	}
+	return fallback
+}
+
+// Calculate works out the unit and period profitability of a SKU. The WFS fee
+// is counted per unit unless every unit sold in the period was seller
+// fulfilled; the referral fee minimum applies per unit. Fees are charged in the
+// currency of the price, so an item priced in another currency than its cost is
+// an error.
+func Calculate(item entities.MarginItem, schedule entities.FeeSchedule) (entities.MarginLine, error) {
+	referral := ReferralFeeFor(schedule, item.Category)
+	fulfillment := FulfillmentFeeFor(schedule, item.SizeTier)
+
+	line := entities.MarginLine{
+		SKU:         item.SKU,
+		ProductName: item.ProductName,
+		Category:    item.Category,
+		SizeTier:    item.SizeTier,
+		Price:       item.Price,
+		ProductCost: item.ProductCost,
+		UnitsSold:   item.UnitsSold,
+		Revenue:     item.Revenue,
+	}
+
+	referralFee, err := money.Max(item.Price.Percent(referral.Percent), referral.MinimumFee)
+	if err != nil {
+		return line, fmt.Errorf("sku %s: %w", item.SKU, err)
+	}
+	line.ReferralFee = referralFee
+	if item.UnitsSold == 0 || item.WFSUnits > 0 {
+		line.FulfillmentFee = fulfillment.FeePerUnit
+	}
+
+	periodReferral, err := money.Max(item.Revenue.Percent(referral.Percent), referral.MinimumFee.Mul(int64(item.UnitsSold)))
+	if err != nil {
+		return line, fmt.Errorf("sku %s: %w", item.SKU, err)
+	}
+	periodFees, err := periodReferral.Add(fulfillment.FeePerUnit.Mul(int64(item.WFSUnits)))
+	if err != nil {
+		return line, fmt.Errorf("sku %s: %w", item.SKU, err)
+	}
+	line.PeriodFees = periodFees
+
+	if item.ProductCost == nil {
+		return line, nil
+	}
+
+	unitMargin, err := money.Sum(item.Price, item.ProductCost.Neg(), line.ReferralFee.Neg(), line.FulfillmentFee.Neg())
+	if err != nil {
+		return line, fmt.Errorf("sku %s: %w", item.SKU, err)
+	}
+	line.UnitMargin = &unitMargin
+	if item.Price.Minor() > 0 {
+		percent := forecast.Round(unitMargin.Ratio(item.Price)*100, 2)
+		line.MarginPercent = &percent
+	}
+
+	profit, err := money.Sum(item.Revenue, item.ProductCost.Mul(int64(item.UnitsSold)).Neg(), line.PeriodFees.Neg())
+	if err != nil {
+		return line, fmt.Errorf("sku %s: %w", item.SKU, err)
+	}
+	line.PeriodProfit = &profit
+
+	return line, nil
+}
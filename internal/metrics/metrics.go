@@ -0,0 +1,153 @@
+// Package metrics holds the Prometheus collectors of the service and the
+// handler that exposes them. Collectors are registered on a registry of their
+// own, so only this service's metrics and the Go runtime's are exported.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "wim"
+
+// Reasons a SKU counts as out of stock: Walmart lists it as out of stock, or
+// no ship node has any quantity available to sell.
+const (
+	OutOfStockListing    = "listing_status"
+	OutOfStockNoQuantity = "no_quantity"
+)
+
+// unmatchedRoute labels requests no route matched, so probes of random paths
+// cannot grow the number of series.
+const unmatchedRoute = "unmatched"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by method, route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time spent serving HTTP requests, by method and route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	walmartRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "walmart_requests_total",
+		Help:      "Calls to the Walmart API, by endpoint and status code; \"error\" when no response came back.",
+	}, []string{"endpoint", "status"})
+
+	walmartDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "walmart_request_duration_seconds",
+		Help:      "Time until the Walmart API answered, by endpoint.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"endpoint"})
+
+	syncRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_runs_total",
+		Help:      "Background job runs, by job and result.",
+	}, []string{"job", "result"})
+
+	syncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sync_duration_seconds",
+		Help:      "Duration of background job runs, by job.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 13),
+	}, []string{"job"})
+
+	syncSKUs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "sync_skus_total",
+		Help:      "SKUs handled by background jobs, by job and outcome.",
+	}, []string{"job", "outcome"})
+
+	outOfStock = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "out_of_stock_skus",
+		Help:      "SKUs out of stock as of the last catalog sync, by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		walmartRequests,
+		walmartDuration,
+		syncRuns,
+		syncDuration,
+		syncSKUs,
+		outOfStock,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveHTTPRequest records a served request. The route is the pattern that
+// matched, never the raw path, to keep the number of series bounded.
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	method = normalizeMethod(method)
+
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+func normalizeMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+	return "OTHER"
+}
+
+// ObserveWalmartRequest records a Walmart API call. A zero status means the
+// call failed before a response came back.
+func ObserveWalmartRequest(endpoint string, status int, duration time.Duration) {
+	label := "error"
+	if status != 0 {
+		label = strconv.Itoa(status)
+	}
+
+	walmartRequests.WithLabelValues(endpoint, label).Inc()
+	walmartDuration.WithLabelValues(endpoint).Observe(duration.Seconds())
+}
+
+// ObserveSync records a finished run of a background job.
+func ObserveSync(job, result string, duration time.Duration) {
+	syncRuns.WithLabelValues(job, result).Inc()
+	syncDuration.WithLabelValues(job).Observe(duration.Seconds())
+}
+
+// CountSKUs adds n SKUs with the outcome to the job's totals.
+func CountSKUs(job, outcome string, n int) {
+	if n > 0 {
+		syncSKUs.WithLabelValues(job, outcome).Add(float64(n))
+	}
+}
+
+// SetOutOfStock sets how many SKUs are out of stock for the reason.
+func SetOutOfStock(reason string, n int) {
+	outOfStock.WithLabelValues(reason).Set(float64(n))
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"walmart-inventory-manager/internal/auth"
+	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	authService "walmart-inventory-manager/internal/service/auth"
+	"walmart-inventory-manager/platform/web"
+)
+
+// APIKeyHeader carries an API key. Keys and JWTs are also accepted as an
+// "Authorization: Bearer" credential.
+const APIKeyHeader = "X-API-Key"
+
+// Authenticate rejects requests without a valid API key or JWT and stores the
+// caller in the request context for the handlers. Public routes are let
+// through without looking at credentials.
+func Authenticate(sv authService.AuthService) func(web.HandleFunc) web.HandleFunc {
+	return func(next web.HandleFunc) web.HandleFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if web.RoutePermission(r) == entities.PermissionPublic {
+				return next(w, r)
+			}
+
+			principal, err := sv.Authenticate(credential(r))
+			if err != nil {
+				if appErrors.StatusCode(err) == http.StatusUnauthorized {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="walmart-inventory-manager"`)
+				}
+				return err
+			}
+
+			return next(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		}
+	}
+}
+
+// Authorize rejects callers whose role lacks the permission the route was
+// registered with. It must run after Authenticate.
+func Authorize(sv authService.AuthService) func(web.HandleFunc) web.HandleFunc {
+	return func(next web.HandleFunc) web.HandleFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if web.RoutePermission(r) == entities.PermissionPublic {
+				return next(w, r)
+			}
+
+			if err := sv.Authorize(auth.PrincipalFrom(r.Context()), web.RoutePermission(r)); err != nil {
+				return err
+			}
+
+			return next(w, r)
+		}
+	}
+}
+
+func credential(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return strings.TrimSpace(key)
+	}
+
+	scheme, value, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(value)
+	}
+	return ""
+}
@@ -0,0 +1,391 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is the currency of amounts stored without one: the
+// marketplace sells in US dollars and DECIMAL columns hold no currency.
+const DefaultCurrency = "USD"
+
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// exponents lists currencies whose minor unit is not the cent.
+var exponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+	"CLP": 0,
+}
+
+// Money is an exact amount in the minor unit of its currency. The zero value
+// is zero dollars and adopts the currency of whatever it is added to, so sums
+// can start from Money{}.
+//
+// Rounding: any operation that produces a fraction of a minor unit (parsing
+// extra decimals, percentages, prorating) rounds half away from zero.
+type Money struct {
+	minor    int64
+	currency string
+}
+
+func New(minor int64, currency string) Money {
+	return Money{minor: minor, currency: normalizeCurrency(currency)}
+}
+
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// FromFloat converts an amount received as a float, such as a JSON number
+// from the Walmart API, rounding to the nearest minor unit.
+func FromFloat(amount float64, currency string) Money {
+	m, err := Parse(strconv.FormatFloat(amount, 'f', -1, 64), currency)
+	if err != nil {
+		return Zero(currency)
+	}
+	return m
+}
+
+// decimalPattern is a plain decimal number. big.Rat also reads fractions and
+// exponents such as "1/3" and "1e3", which are not amounts.
+var decimalPattern = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)$`)
+
+// Parse reads a decimal amount such as "12.34" or "-0.5".
+func Parse(amount string, currency string) (Money, error) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return Zero(currency), nil
+	}
+	if !decimalPattern.MatchString(amount) {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	currency = normalizeCurrency(currency)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent(currency))), nil))
+	minor, err := roundRat(r.Mul(r, scale))
+	if err != nil {
+		return Money{}, fmt.Errorf("amount %q: %w", amount, err)
+	}
+
+	return Money{minor: minor, currency: currency}, nil
+}
+
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// Minor returns the amount in minor units, e.g. cents.
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// Float64 is for ratios and display only; never sum the result.
+func (m Money) Float64() float64 {
+	return float64(m.minor) / math.Pow10(exponent(m.Currency()))
+}
+
+// Decimal formats the amount with the currency's number of decimals, e.g. "12.30".
+func (m Money) Decimal() string {
+	exp := exponent(m.Currency())
+	minor := m.minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+
+	digits := strconv.FormatInt(minor, 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency()
+}
+
+// Add sums two amounts of the same currency. A zero value without a currency
+// takes the other operand's currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.currency == "" && m.minor == 0 {
+		return o, nil
+	}
+	if o.currency == "" && o.minor == 0 {
+		return m, nil
+	}
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{minor: m.minor + o.minor, currency: m.Currency()}, nil
+}
+
+// sameCurrency fails unless both amounts can be combined: they share a
+// currency, or one of them is the zero value.
+func (m Money) sameCurrency(o Money) error {
+	if (m.currency == "" && m.minor == 0) || (o.currency == "" && o.minor == 0) {
+		return nil
+	}
+	if m.Currency() != o.Currency() {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), o.Currency())
+	}
+	return nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	return m.Add(o.Neg())
+}
+
+func (m Money) Neg() Money {
+	return Money{minor: -m.minor, currency: m.currency}
+}
+
+// Mul multiplies by a quantity, e.g. a unit cost by the units ordered.
+func (m Money) Mul(quantity int64) Money {
+	return Money{minor: m.minor * quantity, currency: m.currency}
+}
+
+// MulFrac returns m * num / den, e.g. a line total prorated to the units that
+// were not cancelled.
+func (m Money) MulFrac(num, den int64) Money {
+	if den == 0 {
+		return Money{currency: m.currency}
+	}
+	minor, _ := roundRat(new(big.Rat).SetFrac(big.NewInt(m.minor*num), big.NewInt(den)))
+	return Money{minor: minor, currency: m.currency}
+}
+
+// Percent returns p percent of m, e.g. a referral fee.
+func (m Money) Percent(p float64) Money {
+	r := new(big.Rat).SetFloat64(p)
+	if r == nil {
+		return Money{currency: m.currency}
+	}
+	r.Mul(r, new(big.Rat).SetInt64(m.minor))
+	r.Quo(r, big.NewRat(100, 1))
+	minor, _ := roundRat(r)
+	return Money{minor: minor, currency: m.currency}
+}
+
+// Ratio returns m / o as a float, e.g. for a margin percentage. It is zero when
+// o is zero or the currencies differ.
+func (m Money) Ratio(o Money) float64 {
+	if o.minor == 0 || m.Currency() != o.Currency() {
+		return 0
+	}
+	return float64(m.minor) / float64(o.minor)
+}
+
+// Cmp compares amounts of the same currency, returning -1, 0 or 1.
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.minor < o.minor:
+		return -1, nil
+	case m.minor > o.minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Max returns the larger of two amounts of the same currency.
+func Max(a, b Money) (Money, error) {
+	c, err := a.Cmp(b)
+	if err != nil {
+		return Money{}, err
+	}
+	if c >= 0 {
+		return a, nil
+	}
+	return b, nil
+}
+
+// Sum adds amounts that must all share a currency.
+func Sum(values ...Money) (Money, error) {
+	var total Money
+	for _, v := range values {
+		var err error
+		if total, err = total.Add(v); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Totals aggregates amounts per currency, for sums that may mix currencies.
+type Totals map[string]Money
+
+func (t Totals) Add(m Money) {
+	total, _ := t[m.Currency()].Add(m)
+	t[m.Currency()] = total
+}
+
+// Get returns the total of one currency, zero when there is none.
+func (t Totals) Get(currency string) Money {
+	if total, ok := t[normalizeCurrency(currency)]; ok {
+		return total
+	}
+	return Zero(currency)
+}
+
+type jsonMoney struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON writes {"amount": 12.30, "currency": "USD"} with the amount as an
+// exact decimal number.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: json.RawMessage(m.Decimal()), Currency: m.Currency()})
+}
+
+// UnmarshalJSON reads the object form, with the amount as a number or a
+// string, or a bare amount in the default currency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*m = Money{}
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		var v jsonMoney
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		parsed, err := Parse(strings.Trim(string(v.Amount), `"`), v.Currency)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	}
+
+	parsed, err := Parse(strings.Trim(string(data), `"`), DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan reads a DECIMAL column in the default currency. NULL scans as zero; use
+// NullMoney where NULL must be told apart.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = Money{}
+		return nil
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case float64:
+		*m = FromFloat(v, DefaultCurrency)
+		return nil
+	case int64:
+		*m = New(v*int64(math.Pow10(exponent(DefaultCurrency))), DefaultCurrency)
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into money", src)
+}
+
+func (m *Money) scanString(s string) error {
+	parsed, err := Parse(s, DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value writes the amount as a decimal string for a DECIMAL column.
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+// NullMoney is a Money that may be NULL in the database.
+type NullMoney struct {
+	Money Money
+	Valid bool
+}
+
+func (n *NullMoney) Scan(src interface{}) error {
+	if src == nil {
+		n.Money, n.Valid = Money{}, false
+		return nil
+	}
+	n.Valid = true
+	return n.Money.Scan(src)
+}
+
+// Ptr returns the amount, or nil when it is NULL.
+func (n NullMoney) Ptr() *Money {
+	if !n.Valid {
+		return nil
+	}
+	m := n.Money
+	return &m
+}
+
+func exponent(currency string) int {
+	if exp, ok := exponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+func normalizeCurrency(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		return DefaultCurrency
+	}
+	return currency
+}
+
+// roundRat rounds half away from zero to an integer.
+func roundRat(r *big.Rat) (int64, error) {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, errors.New("amount out of range")
+	}
+	return q.Int64(), nil
+}
@@ -0,0 +1,252 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+	}{
+		{"12.34", "USD", 1234},
+		{"+1", "usd", 100},
+		{".5", "USD", 50},
+		{"5.", "USD", 500},
+		{"", "USD", 0},
+		// Extra decimals round half away from zero.
+		{"1.005", "USD", 101},
+		{"-1.005", "USD", -101},
+		{"1.0049", "USD", 100},
+		{"-1.0049", "USD", -100},
+		// Zero-exponent currencies have no minor unit below one.
+		{"100", "JPY", 100},
+		{"100.5", "JPY", 101},
+		{"-100.5", "JPY", -101},
+		{"100.49", "JPY", 100},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.amount, tt.currency)
+		if err != nil {
+			t.Errorf("Parse(%q, %q) error: %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if got.Minor() != tt.want {
+			t.Errorf("Parse(%q, %q) = %d minor units, want %d", tt.amount, tt.currency, got.Minor(), tt.want)
+		}
+	}
+}
+
+func TestParseRejectsNonDecimals(t *testing.T) {
+	for _, amount := range []string{"1/3", "1e3", "0x10", "abc", "1.2.3", "--1", "1,50", ".", "-"} {
+		if got, err := Parse(amount, "USD"); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", amount, got)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{New(1230, "USD"), "12.30"},
+		{New(-5, "USD"), "-0.05"},
+		{New(0, "USD"), "0.00"},
+		{New(1500, "JPY"), "1500"},
+		{Money{}, "0.00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("%#v.Decimal() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		money   Money
+		percent float64
+		want    int64
+	}{
+		{New(999, "USD"), 15, 150},   // 149.85
+		{New(-999, "USD"), 15, -150}, // -149.85
+		{New(1, "USD"), 50, 1},       // 0.5
+		{New(-1, "USD"), 50, -1},     // -0.5
+		{New(1000, "USD"), 8, 80},
+		{New(333, "JPY"), 10, 33}, // 33.3
+	}
+
+	for _, tt := range tests {
+		got := tt.money.Percent(tt.percent)
+		if got.Minor() != tt.want || got.Currency() != tt.money.Currency() {
+			t.Errorf("%v.Percent(%v) = %v, want %d %s", tt.money, tt.percent, got, tt.want, tt.money.Currency())
+		}
+	}
+}
+
+func TestMulFrac(t *testing.T) {
+	tests := []struct {
+		money    Money
+		num, den int64
+		want     int64
+	}{
+		{New(100, "USD"), 1, 3, 33},
+		{New(200, "USD"), 1, 3, 67},
+		{New(5, "USD"), 1, 2, 3},
+		{New(-5, "USD"), 1, 2, -3},
+		{New(999, "USD"), 2, 2, 999},
+		{New(999, "USD"), 1, 0, 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.MulFrac(tt.num, tt.den); got.Minor() != tt.want {
+			t.Errorf("%v.MulFrac(%d, %d) = %v, want %d minor units", tt.money, tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func TestMixedCurrencies(t *testing.T) {
+	usd, eur := New(100, "USD"), New(100, "EUR")
+
+	if _, err := usd.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Sub(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := usd.Cmp(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Cmp error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := Max(usd, eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Max error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := Sum(usd, usd, eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sum error = %v, want ErrCurrencyMismatch", err)
+	}
+}
+
+func TestZeroValueTakesTheOtherCurrency(t *testing.T) {
+	eur := New(250, "EUR")
+
+	for _, sum := range []func() (Money, error){
+		func() (Money, error) { return Money{}.Add(eur) },
+		func() (Money, error) { return eur.Add(Money{}) },
+		func() (Money, error) { return Sum(Money{}, eur) },
+	} {
+		got, err := sum()
+		if err != nil {
+			t.Fatalf("adding the zero value: %v", err)
+		}
+		if got != eur {
+			t.Errorf("sum = %v, want %v", got, eur)
+		}
+	}
+
+	if c, err := (Money{}).Cmp(eur); err != nil || c != -1 {
+		t.Errorf("Money{}.Cmp(%v) = %d, %v, want -1", eur, c, err)
+	}
+	if got, err := Max(Money{}, eur); err != nil || got != eur {
+		t.Errorf("Max(Money{}, %v) = %v, %v, want %v", eur, got, err, eur)
+	}
+	if got, err := Max(New(-100, "EUR"), Money{}); err != nil || !got.IsZero() {
+		t.Errorf("Max(-1.00 EUR, Money{}) = %v, %v, want zero", got, err)
+	}
+}
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		a, b Money
+		want int
+	}{
+		{New(100, "USD"), New(200, "USD"), -1},
+		{New(200, "USD"), New(200, "USD"), 0},
+		{New(300, "USD"), New(200, "USD"), 1},
+		{New(-300, "USD"), New(-200, "USD"), -1},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.a.Cmp(tt.b)
+		if err != nil || got != tt.want {
+			t.Errorf("%v.Cmp(%v) = %d, %v, want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
+
+func TestTotals(t *testing.T) {
+	totals := Totals{}
+	totals.Add(New(100, "USD"))
+	totals.Add(New(200, "eur"))
+	totals.Add(New(50, "USD"))
+	totals.Add(Money{})
+
+	if got := totals.Get("USD"); got != New(150, "USD") {
+		t.Errorf("USD total = %v, want 1.50 USD", got)
+	}
+	if got := totals.Get("eur"); got != New(200, "EUR") {
+		t.Errorf("EUR total = %v, want 2.00 EUR", got)
+	}
+	if got := totals.Get("GBP"); !got.IsZero() || got.Currency() != "GBP" {
+		t.Errorf("GBP total = %v, want 0.00 GBP", got)
+	}
+	if len(totals) != 2 {
+		t.Errorf("totals hold %d currencies, want 2", len(totals))
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want Money
+	}{
+		{"object with a number", `{"amount": 12.30, "currency": "eur"}`, New(1230, "EUR")},
+		{"object with a string", `{"amount": "12.345", "currency": "USD"}`, New(1235, "USD")},
+		{"object without a currency", `{"amount": 1}`, New(100, "USD")},
+		{"zero-exponent object", `{"amount": 99.5, "currency": "JPY"}`, New(100, "JPY")},
+		{"bare number", `-12.345`, New(-1235, "USD")},
+		{"bare string", `"7.5"`, New(750, "USD")},
+		{"null", `null`, Money{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			if err := json.Unmarshal([]byte(tt.json), &got); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.json, err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal(%s) = %#v, want %#v", tt.json, got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{`"1/3"`, `1e3`, `{"amount": "abc"}`, `[1]`} {
+		var m Money
+		if err := json.Unmarshal([]byte(bad), &m); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want an error", bad, m)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	for _, m := range []Money{New(1230, "USD"), New(-5, "EUR"), New(1500, "JPY"), Money{}} {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", m, err)
+		}
+
+		var got Money
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if got.Minor() != m.Minor() || got.Currency() != m.Currency() {
+			t.Errorf("round trip of %v through %s gave %v", m, data, got)
+		}
+	}
+}
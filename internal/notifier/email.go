@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailNotifier sends alerts over SMTP. Authentication is only attempted when a
+// username is configured, so a plain local SMTP server works as a stand-in.
+type EmailNotifier struct {
+	cfg SMTPConfig
+}
+
+func NewEmailNotifier(cfg SMTPConfig) *EmailNotifier {
+	return &EmailNotifier{cfg: cfg}
+}
+
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, alert entities.Alert) error {
+	if len(n.cfg.To) == 0 {
+		return fmt.Errorf("no email recipients configured")
+	}
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	subject := fmt.Sprintf("[Inventory alert] %s %s", alert.Type, alert.SKU)
+	message := strings.Join([]string{
+		"From: " + n.cfg.From,
+		"To: " + strings.Join(n.cfg.To, ", "),
+		"Subject: " + subject,
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		alert.Message,
+		"",
+		fmt.Sprintf("SKU: %s\r\nQuantity: %d\r\nRaised at: %s", alert.SKU, alert.Quantity, alert.CreatedAt.Format("2006-01-02 15:04:05 MST")),
+	}, "\r\n")
+
+	addr := net.JoinHostPort(n.cfg.Host, n.cfg.Port)
+	return smtp.SendMail(addr, auth, n.cfg.From, n.cfg.To, []byte(message))
+}
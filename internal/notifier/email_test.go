@@ -0,0 +1,128 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+// message is what the fake SMTP server received for one mail.
+type message struct {
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTP accepts a single SMTP session on a local port and reports the mail
+// it received. It speaks just enough of the protocol for net/smtp.
+func fakeSMTP(t *testing.T) (host, port string, received <-chan message) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	ch := make(chan message, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var m message
+		reply("220 localhost fake SMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "MAIL":
+				m.from = command
+				reply("250 OK")
+			case "RCPT":
+				m.to = append(m.to, command)
+				reply("250 OK")
+			case "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				m.data = data.String()
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				ch <- m
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ = net.SplitHostPort(listener.Addr().String())
+	return host, port, ch
+}
+
+func TestEmailNotifierSendsAlert(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+
+	n := NewEmailNotifier(SMTPConfig{
+		Host: host,
+		Port: port,
+		From: "alerts@example.com",
+		To:   []string{"ops@example.com", "buyer@example.com"},
+	})
+	alert := entities.Alert{SKU: "SKU-1", Type: entities.AlertRuleThreshold, Message: "SKU-1 stock is low", Quantity: 3, CreatedAt: time.Now()}
+	if err := n.Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	var m message
+	select {
+	case m = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the fake SMTP server received no mail")
+	}
+
+	if !strings.Contains(m.from, "<alerts@example.com>") {
+		t.Errorf("MAIL = %q, want the configured sender", m.from)
+	}
+	if len(m.to) != 2 {
+		t.Errorf("RCPT = %v, want both recipients", m.to)
+	}
+	for _, want := range []string{"Subject: [Inventory alert] threshold SKU-1", "SKU-1 stock is low", "Quantity: 3"} {
+		if !strings.Contains(m.data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, m.data)
+		}
+	}
+}
+
+func TestEmailNotifierWithoutRecipients(t *testing.T) {
+	n := NewEmailNotifier(SMTPConfig{Host: "127.0.0.1", Port: "25"})
+	if err := n.Notify(context.Background(), entities.Alert{SKU: "SKU-1"}); err == nil {
+		t.Fatal("Notify succeeded, want an error without recipients")
+	}
+}
@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/logging"
+)
+
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LogNotifier{logger: logger}
+}
+
+func (n *LogNotifier) Name() string {
+	return "log"
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, alert entities.Alert) error {
+	n.logger.WarnContext(ctx, alert.Message,
+		slog.String("alert_type", string(alert.Type)),
+		slog.String(logging.KeySKU, alert.SKU),
+		slog.Int64("alert_id", alert.ID),
+	)
+	return nil
+}
@@ -0,0 +1,12 @@
+package notifier
+
+import (
+	"context"
+	"walmart-inventory-manager/internal/entities"
+)
+
+// Notifier delivers alerts through one channel.
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert entities.Alert) error
+}
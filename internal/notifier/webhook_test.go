@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"walmart-inventory-manager/internal/entities"
+)
+
+func TestWebhookNotifierPostsSignedAlert(t *testing.T) {
+	const secret = "shh"
+
+	var got entities.Alert
+	var signature, contentType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		signature = r.Header.Get("X-Signature")
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("decoding body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	alert := entities.Alert{ID: 7, SKU: "SKU-1", Type: entities.AlertRuleThreshold, Message: "low", Quantity: 2}
+	if err := NewWebhookNotifier(server.URL, secret).Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if got.ID != alert.ID || got.SKU != alert.SKU || got.Message != alert.Message {
+		t.Errorf("received %+v, want %+v", got, alert)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Errorf("X-Signature = %q, want %q", signature, want)
+	}
+}
+
+func TestWebhookNotifierWithoutSecretIsUnsigned(t *testing.T) {
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("X-Signature")
+	}))
+	defer server.Close()
+
+	if err := NewWebhookNotifier(server.URL, "").Notify(context.Background(), entities.Alert{SKU: "SKU-1"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if signature != "" {
+		t.Errorf("X-Signature = %q, want none", signature)
+	}
+}
+
+func TestWebhookNotifierFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := NewWebhookNotifier(server.URL, "").Notify(context.Background(), entities.Alert{SKU: "SKU-1"}); err == nil {
+		t.Fatal("Notify succeeded, want an error for a 500 response")
+	}
+}
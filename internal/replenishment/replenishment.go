@@ -0,0 +1,47 @@
+package replenishment
+
+import (
+	"math"
+	"walmart-inventory-manager/internal/entities"
+)
+
+// Suggest computes the reorder line for one SKU. Stock on hand plus inbound is
+// compared with the reorder point (demand over the lead time plus safety days);
+// when it is at or below it, enough is ordered to reach the target of lead time,
+// safety and coverage days of demand, rounded up to the minimum order quantity.
+// A zero SuggestedQty means nothing needs ordering.
+func Suggest(item entities.SupplyItem, dailyVelocity float64, inbound int, policy entities.ReorderPolicy) entities.ReorderLine {
+	available := item.WarehouseStock + item.WalmartAvailable
+	reorderPoint := int(math.Ceil(dailyVelocity * float64(item.LeadTimeDays+policy.SafetyStockDays)))
+	target := int(math.Ceil(dailyVelocity * float64(item.LeadTimeDays+policy.SafetyStockDays+policy.CoverageDays)))
+
+	line := entities.ReorderLine{
+		SKU:                item.SKU,
+		ProductName:        item.ProductName,
+		SupplierItemNumber: item.SupplierItemNumber,
+		DailyVelocity:      dailyVelocity,
+		AvailableStock:     available,
+		InboundStock:       inbound,
+		LeadTimeDays:       item.LeadTimeDays,
+		ReorderPoint:       reorderPoint,
+		TargetStock:        target,
+		UnitCost:           item.ProductCost,
+	}
+
+	position := available + inbound
+	if dailyVelocity <= 0 || position > reorderPoint {
+		return line
+	}
+
+	qty := target - position
+	if qty < item.MinOrderQty {
+		qty = item.MinOrderQty
+	}
+	if qty < 0 {
+		qty = 0
+	}
+
+	line.SuggestedQty = qty
+	line.LineTotal = item.ProductCost.Mul(int64(qty))
+	return line
+}
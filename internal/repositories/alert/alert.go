@@ -0,0 +1,246 @@
+package alert
+
+import (
+	"database/sql"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type alertRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRepository(db *sql.DB) *alertRepository {
+	return &alertRepository{
+		db: db,
+	}
+}
+
+func (r *alertRepository) FindRules(activeOnly bool) ([]entities.AlertRule, error) {
+	query := `
+		SELECT id, seller_sku, type, stock_type, threshold, days_of_cover, channels, active, createdAt, updatedAt
+		FROM alert_rules
+	`
+	if activeOnly {
+		query += ` WHERE active = 1`
+	}
+	query += ` ORDER BY id`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []entities.AlertRule{}
+	for rows.Next() {
+		var rule entities.AlertRule
+		var sku, channels sql.NullString
+		err := rows.Scan(
+			&rule.ID,
+			&sku,
+			&rule.Type,
+			&rule.StockType,
+			&rule.Threshold,
+			&rule.DaysOfCover,
+			&channels,
+			&rule.Active,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		rule.SKU = sku.String
+		rule.Channels = []string{}
+		if channels.String != "" {
+			rule.Channels = strings.Split(channels.String, ",")
+		}
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *alertRepository) CreateRule(rule entities.AlertRule) (int64, error) {
+	query := `
+		INSERT INTO alert_rules (seller_sku, type, stock_type, threshold, days_of_cover, channels, active, createdAt, updatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+	`
+	result, err := r.db.Exec(query,
+		sql.NullString{String: rule.SKU, Valid: rule.SKU != ""},
+		rule.Type,
+		rule.StockType,
+		rule.Threshold,
+		rule.DaysOfCover,
+		strings.Join(rule.Channels, ","),
+		rule.Active,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (r *alertRepository) DeleteRule(id int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM alert_rules WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// FindSubjects loads the current stock and listing state of every product.
+func (r *alertRepository) FindSubjects() ([]entities.AlertSubject, error) {
+	query := `
+		SELECT
+			p.id,
+			p.seller_sku,
+			p.product_name,
+			p.warehouse_stock,
+			d.available_to_sell_qty,
+			d.availability,
+			p.listing_status_id
+		FROM products p
+		INNER JOIN wmt_product_details d ON p.id = d.product_id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subjects []entities.AlertSubject
+	for rows.Next() {
+		var s entities.AlertSubject
+		var sku, productName, availability sql.NullString
+		var warehouseStock, listingStatusID sql.NullInt32
+		err := rows.Scan(
+			&s.ProductID,
+			&sku,
+			&productName,
+			&warehouseStock,
+			&s.WalmartAvailable,
+			&availability,
+			&listingStatusID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.SKU = sku.String
+		s.ProductName = productName.String
+		s.WarehouseStock = int(warehouseStock.Int32)
+		s.Availability = availability.String
+		s.ListingStatusID = entities.ListingStatus(listingStatusID.Int32)
+		subjects = append(subjects, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subjects, nil
+}
+
+func (r *alertRepository) FindOpenAlerts() ([]entities.Alert, error) {
+	return r.findAlerts(`WHERE status = ?`, []interface{}{entities.AlertStatusOpen})
+}
+
+func (r *alertRepository) FindAlerts(status entities.AlertStatus, limit int) ([]entities.Alert, error) {
+	if status == "" {
+		return r.findAlerts(`ORDER BY id DESC LIMIT ?`, []interface{}{limit})
+	}
+	return r.findAlerts(`WHERE status = ? ORDER BY id DESC LIMIT ?`, []interface{}{status, limit})
+}
+
+func (r *alertRepository) findAlerts(clause string, args []interface{}) ([]entities.Alert, error) {
+	query := `
+		SELECT id, rule_id, product_id, seller_sku, type, message, quantity, status, createdAt, notifiedAt, resolvedAt
+		FROM alerts
+	` + clause
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []entities.Alert{}
+	for rows.Next() {
+		var a entities.Alert
+		var notifiedAt, resolvedAt sql.NullTime
+		err := rows.Scan(
+			&a.ID,
+			&a.RuleID,
+			&a.ProductID,
+			&a.SKU,
+			&a.Type,
+			&a.Message,
+			&a.Quantity,
+			&a.Status,
+			&a.CreatedAt,
+			&notifiedAt,
+			&resolvedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if notifiedAt.Valid {
+			a.NotifiedAt = &notifiedAt.Time
+		}
+		if resolvedAt.Valid {
+			a.ResolvedAt = &resolvedAt.Time
+		}
+		alerts = append(alerts, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (r *alertRepository) CreateAlert(alert entities.Alert) (int64, error) {
+	query := `
+		INSERT INTO alerts (rule_id, product_id, seller_sku, type, message, quantity, status, createdAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query,
+		alert.RuleID,
+		alert.ProductID,
+		alert.SKU,
+		alert.Type,
+		alert.Message,
+		alert.Quantity,
+		alert.Status,
+		alert.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (r *alertRepository) MarkNotified(id int64) error {
+	_, err := r.db.Exec(`UPDATE alerts SET notifiedAt = NOW() WHERE id = ?`, id)
+	return err
+}
+
+func (r *alertRepository) ResolveAlert(id int64) error {
+	_, err := r.db.Exec(`UPDATE alerts SET status = ?, resolvedAt = NOW() WHERE id = ?`, entities.AlertStatusResolved, id)
+	return err
+}
@@ -0,0 +1,15 @@
+package alert
+
+import "walmart-inventory-manager/internal/entities"
+
+type AlertRepository interface {
+	FindRules(activeOnly bool) ([]entities.AlertRule, error)
+	CreateRule(rule entities.AlertRule) (int64, error)
+	DeleteRule(id int64) (bool, error)
+	FindSubjects() ([]entities.AlertSubject, error)
+	FindOpenAlerts() ([]entities.Alert, error)
+	FindAlerts(status entities.AlertStatus, limit int) ([]entities.Alert, error)
+	CreateAlert(alert entities.Alert) (int64, error)
+	MarkNotified(id int64) error
+	ResolveAlert(id int64) error
+}
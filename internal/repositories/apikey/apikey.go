@@ -0,0 +1,121 @@
+package apikey
+
+import (
+	"database/sql"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *apiKeyRepository {
+	return &apiKeyRepository{
+		db: db,
+	}
+}
+
+const selectAPIKey = `
+	SELECT id, name, prefix, role, key_hash, createdAt, last_used_at, revoked_at
+	FROM api_keys
+`
+
+func (r *apiKeyRepository) FindAll() ([]entities.APIKey, error) {
+	rows, err := r.db.Query(selectAPIKey + ` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []entities.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepository) FindByID(id int64) (*entities.APIKey, error) {
+	return r.findOne(`WHERE id = ?`, id)
+}
+
+func (r *apiKeyRepository) FindByHash(hash string) (*entities.APIKey, error) {
+	return r.findOne(`WHERE key_hash = ?`, hash)
+}
+
+func (r *apiKeyRepository) findOne(where string, arg interface{}) (*entities.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(selectAPIKey+where, arg))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepository) Create(key entities.APIKey) (int64, error) {
+	query := `
+		INSERT INTO api_keys (name, prefix, role, key_hash, createdAt)
+		VALUES (?, ?, ?, ?, NOW())
+	`
+	result, err := r.db.Exec(query, key.Name, key.Prefix, key.Role, key.Hash)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (r *apiKeyRepository) Revoke(id int64) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET revoked_at = NOW() WHERE id = ? AND revoked_at IS NULL`, id)
+	return err
+}
+
+func (r *apiKeyRepository) SetRole(id int64, role entities.Role) error {
+	_, err := r.db.Exec(`UPDATE api_keys SET role = ? WHERE id = ?`, role, id)
+	return err
+}
+
+// Touch records that the key was used. It writes at most once a minute per
+// key so busy clients do not turn every request into an UPDATE.
+func (r *apiKeyRepository) Touch(id int64) error {
+	query := `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE id = ? AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL 1 MINUTE)
+	`
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row scanner) (entities.APIKey, error) {
+	var key entities.APIKey
+	var lastUsedAt, revokedAt sql.NullTime
+
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Role, &key.Hash, &key.CreatedAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return key, err
+	}
+
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return key, nil
+}
@@ -0,0 +1,13 @@
+package apikey
+
+import "walmart-inventory-manager/internal/entities"
+
+type APIKeyRepository interface {
+	FindAll() ([]entities.APIKey, error)
+	FindByID(id int64) (*entities.APIKey, error)
+	FindByHash(hash string) (*entities.APIKey, error)
+	Create(key entities.APIKey) (int64, error)
+	Revoke(id int64) error
+	SetRole(id int64, role entities.Role) error
+	Touch(id int64) error
+}
@@ -0,0 +1,523 @@
+package image
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type imageRepository struct {
+	db *sql.DB
+}
+
+func NewImageRepository(db *sql.DB) *imageRepository {
+	return &imageRepository{
+		db: db,
+	}
+}
+
+const selectImage = `
+	SELECT i.id, i.product_id, p.seller_sku, i.source, i.source_url, i.position, i.is_primary, i.file_name, i.thumbnail_name,
+		i.content_type, i.width, i.height, i.size_bytes, i.checksum, i.createdAt, i.updatedAt
+	FROM product_images i
+	INNER JOIN products p ON p.id = i.product_id
+`
+
+// FindByProduct lists a product's images, primary first and then by position.
+func (r *imageRepository) FindByProduct(productID int64) ([]entities.ProductImage, error) {
+	rows, err := r.db.Query(selectImage+` WHERE i.product_id = ? ORDER BY i.is_primary DESC, i.position, i.id`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := []entities.ProductImage{}
+	for rows.Next() {
+		image, err := scanImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, image)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return images, nil
+}
+
+func (r *imageRepository) FindByID(productID, id int64) (*entities.ProductImage, error) {
+	return r.findOne(`WHERE i.product_id = ? AND i.id = ?`, productID, id)
+}
+
+func (r *imageRepository) FindByChecksum(productID int64, checksum string) (*entities.ProductImage, error) {
+	return r.findOne(`WHERE i.product_id = ? AND i.checksum = ?`, productID, checksum)
+}
+
+func (r *imageRepository) findOne(clause string, args ...interface{}) (*entities.ProductImage, error) {
+	image, err := scanImage(r.db.QueryRow(selectImage+clause, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &image, nil
+}
+
+// Insert adds an image after the product's existing ones. It becomes the
+// primary image when asked to or when the product has none yet.
+func (r *imageRepository) Insert(image entities.ProductImage, primary bool) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var position int
+	var primaries int
+	err = tx.QueryRow(`SELECT COALESCE(MAX(position), 0), COALESCE(SUM(is_primary), 0) FROM product_images WHERE product_id = ? FOR UPDATE`, image.ProductID).
+		Scan(&position, &primaries)
+	if err != nil {
+		return 0, fmt.Errorf("failed to lock product images: %w", err)
+	}
+
+	query := `
+		INSERT INTO product_images (product_id, source, source_url, position, is_primary, file_name, thumbnail_name, content_type, width, height, size_bytes, checksum, createdAt, updatedAt)
+		VALUES (?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?, ?, NOW(), NOW())
+	`
+	result, err := tx.Exec(query,
+		image.ProductID,
+		image.Source,
+		sql.NullString{String: image.SourceURL, Valid: image.SourceURL != ""},
+		position+1,
+		image.FileName,
+		image.ThumbnailName,
+		image.ContentType,
+		image.Width,
+		image.Height,
+		image.SizeBytes,
+		image.Checksum,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert product image: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if primary || primaries == 0 {
+		if err := setPrimary(tx, image.ProductID, id); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, tx.Commit()
+}
+
+// Touch records that an image was fetched again unchanged, possibly from a new URL.
+func (r *imageRepository) Touch(id int64, sourceURL string) error {
+	_, err := r.db.Exec(`UPDATE product_images SET source_url = COALESCE(?, source_url), updatedAt = NOW() WHERE id = ?`,
+		sql.NullString{String: sourceURL, Valid: sourceURL != ""}, id)
+	return err
+}
+
+func (r *imageRepository) SetPrimary(productID, id int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setPrimary(tx, productID, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes an image. When it was the primary one, the next image by
+// position takes its place.
+func (r *imageRepository) Delete(productID, id int64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var wasPrimary bool
+	err = tx.QueryRow(`SELECT is_primary FROM product_images WHERE product_id = ? AND id = ? FOR UPDATE`, productID, id).Scan(&wasPrimary)
+	if err != nil {
+		return fmt.Errorf("failed to lock product image %d: %w", id, err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM product_images WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete product image %d: %w", id, err)
+	}
+
+	if wasPrimary {
+		var next int64
+		err := tx.QueryRow(`SELECT id FROM product_images WHERE product_id = ? ORDER BY position, id LIMIT 1`, productID).Scan(&next)
+		switch {
+		case err == sql.ErrNoRows:
+		case err != nil:
+			return err
+		default:
+			if err := setPrimary(tx, productID, next); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// LastFetched returns when an image from source was last stored or confirmed
+// for the product, or nil if it has none.
+func (r *imageRepository) LastFetched(productID int64, source entities.ImageSource) (*time.Time, error) {
+	var last sql.NullTime
+	err := r.db.QueryRow(`SELECT MAX(updatedAt) FROM product_images WHERE product_id = ? AND source = ?`, productID, source).Scan(&last)
+	if err != nil {
+		return nil, err
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+	return &last.Time, nil
+}
+
+func (r *imageRepository) CreateReview(review entities.ImageReview) (int64, error) {
+	urls, err := json.Marshal(review.Match.ImageURLs)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		INSERT INTO image_match_reviews (product_id, item_id, candidate_title, method, score, image_urls, status, createdAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NOW())
+	`
+	result, err := r.db.Exec(query,
+		review.ProductID,
+		sql.NullString{String: review.Match.ItemID, Valid: review.Match.ItemID != ""},
+		review.Match.Title,
+		review.Match.Method,
+		review.Match.Score,
+		string(urls),
+		review.Status,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+const selectReview = `
+	SELECT v.id, v.product_id, p.seller_sku, p.product_name, v.item_id, v.candidate_title, v.method, v.score, v.image_urls,
+		v.status, v.createdAt, v.reviewedAt
+	FROM image_match_reviews v
+	INNER JOIN products p ON p.id = v.product_id
+`
+
+func (r *imageRepository) FindReviews(status entities.ImageReviewStatus, limit int) ([]entities.ImageReview, error) {
+	if status == "" {
+		return r.findReviews(`ORDER BY v.id DESC LIMIT ?`, limit)
+	}
+	return r.findReviews(`WHERE v.status = ? ORDER BY v.id DESC LIMIT ?`, status, limit)
+}
+
+func (r *imageRepository) FindReviewsByProduct(productID int64) ([]entities.ImageReview, error) {
+	return r.findReviews(`WHERE v.product_id = ? ORDER BY v.id`, productID)
+}
+
+func (r *imageRepository) FindReview(id int64) (*entities.ImageReview, error) {
+	review, err := scanReview(r.db.QueryRow(selectReview+` WHERE v.id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (r *imageRepository) findReviews(clause string, args ...interface{}) ([]entities.ImageReview, error) {
+	rows, err := r.db.Query(selectReview+clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []entities.ImageReview{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (r *imageRepository) SetReviewStatus(id int64, status entities.ImageReviewStatus) error {
+	_, err := r.db.Exec(`UPDATE image_match_reviews SET status = ?, reviewedAt = NOW() WHERE id = ?`, status, id)
+	return err
+}
+
+// EnqueueMissing queues every product that has no images and is not queued yet.
+func (r *imageRepository) EnqueueMissing() (int64, error) {
+	query := `
+		INSERT INTO image_backfill_queue (product_id, status, attempts, next_attempt_at, createdAt, updatedAt)
+		SELECT p.id, ?, 0, NOW(), NOW(), NOW()
+		FROM products p
+		WHERE p.seller_sku IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM product_images i WHERE i.product_id = p.id)
+			AND NOT EXISTS (SELECT 1 FROM image_backfill_queue q WHERE q.product_id = p.id)
+	`
+	result, err := r.db.Exec(query, entities.ImageBackfillQueued)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// EnqueueRefresh queues again the finished products last looked up before the
+// given time. Products with uploaded images keep them and are skipped.
+func (r *imageRepository) EnqueueRefresh(before time.Time) (int64, error) {
+	query := `
+		UPDATE image_backfill_queue q
+		SET q.status = ?, q.attempts = 0, q.next_attempt_at = NOW(), q.updatedAt = NOW()
+		WHERE q.status = ?
+			AND q.updatedAt < ?
+			AND NOT EXISTS (SELECT 1 FROM product_images i WHERE i.product_id = q.product_id AND i.source = ?)
+	`
+	result, err := r.db.Exec(query, entities.ImageBackfillQueued, entities.ImageBackfillDone, before, entities.ImageSourceUpload)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// Requeue puts a product at the front of the queue with its attempts reset.
+func (r *imageRepository) Requeue(productID int64) error {
+	query := `
+		INSERT INTO image_backfill_queue (product_id, status, attempts, next_attempt_at, createdAt, updatedAt)
+		VALUES (?, ?, 0, NOW(), NOW(), NOW())
+		ON DUPLICATE KEY UPDATE status = VALUES(status), attempts = 0, next_attempt_at = NOW(), last_error = NULL, updatedAt = NOW()
+	`
+	_, err := r.db.Exec(query, productID, entities.ImageBackfillQueued)
+	return err
+}
+
+const selectBackfill = `
+	SELECT q.product_id, p.seller_sku, p.product_name, p.upc, d.gtin, p.product_image, q.status, q.attempts, q.next_attempt_at,
+		q.last_attempt_at, q.last_outcome, q.last_error, q.updatedAt
+	FROM image_backfill_queue q
+	INNER JOIN products p ON p.id = q.product_id
+	LEFT JOIN wmt_product_details d ON d.product_id = p.id
+`
+
+// FindDueBackfills returns the queued products whose next attempt is due,
+// longest waiting first.
+func (r *imageRepository) FindDueBackfills(limit int) ([]entities.ImageBackfillItem, error) {
+	return r.findBackfills(`WHERE q.status = ? AND q.next_attempt_at <= NOW() ORDER BY q.next_attempt_at LIMIT ?`, entities.ImageBackfillQueued, limit)
+}
+
+func (r *imageRepository) FindBackfills(status entities.ImageBackfillStatus, limit int) ([]entities.ImageBackfillItem, error) {
+	if status == "" {
+		return r.findBackfills(`ORDER BY q.updatedAt DESC LIMIT ?`, limit)
+	}
+	return r.findBackfills(`WHERE q.status = ? ORDER BY q.next_attempt_at LIMIT ?`, status, limit)
+}
+
+func (r *imageRepository) findBackfills(clause string, args ...interface{}) ([]entities.ImageBackfillItem, error) {
+	rows, err := r.db.Query(selectBackfill+clause, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entities.ImageBackfillItem{}
+	for rows.Next() {
+		var item entities.ImageBackfillItem
+		var sku, productName, upc, gtin, productImage, lastOutcome, lastError sql.NullString
+		var lastAttemptAt sql.NullTime
+		err := rows.Scan(
+			&item.ProductID,
+			&sku,
+			&productName,
+			&upc,
+			&gtin,
+			&productImage,
+			&item.Status,
+			&item.Attempts,
+			&item.NextAttemptAt,
+			&lastAttemptAt,
+			&lastOutcome,
+			&lastError,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.SKU = sku.String
+		item.ProductName = productName.String
+		item.UPC = upc.String
+		item.GTIN = gtin.String
+		item.ProductImage = productImage.String
+		item.LastOutcome = lastOutcome.String
+		item.LastError = lastError.String
+		if lastAttemptAt.Valid {
+			item.LastAttemptAt = &lastAttemptAt.Time
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// UpdateBackfill records the result of an attempt.
+func (r *imageRepository) UpdateBackfill(item entities.ImageBackfillItem) error {
+	query := `
+		UPDATE image_backfill_queue
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = NOW(), last_outcome = ?, last_error = ?, updatedAt = NOW()
+		WHERE product_id = ?
+	`
+	lastError := item.LastError
+	if len(lastError) > 512 {
+		lastError = lastError[:512]
+	}
+	_, err := r.db.Exec(query,
+		item.Status,
+		item.Attempts,
+		item.NextAttemptAt,
+		sql.NullString{String: item.LastOutcome, Valid: item.LastOutcome != ""},
+		sql.NullString{String: lastError, Valid: lastError != ""},
+		item.ProductID,
+	)
+	return err
+}
+
+func (r *imageRepository) CountBackfills() (*entities.ImageBackfillProgress, error) {
+	query := `
+		SELECT status, COUNT(*), COALESCE(SUM(next_attempt_at <= NOW()), 0)
+		FROM image_backfill_queue
+		GROUP BY status
+	`
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := &entities.ImageBackfillProgress{}
+	for rows.Next() {
+		var status entities.ImageBackfillStatus
+		var count, due int
+		if err := rows.Scan(&status, &count, &due); err != nil {
+			return nil, err
+		}
+		switch status {
+		case entities.ImageBackfillQueued:
+			progress.Queued = count
+			progress.Due = due
+		case entities.ImageBackfillDone:
+			progress.Done = count
+		case entities.ImageBackfillReview:
+			progress.Review = count
+		case entities.ImageBackfillExhausted:
+			progress.Exhausted = count
+		}
+		progress.Total += count
+	}
+
+	return progress, rows.Err()
+}
+
+func setPrimary(tx *sql.Tx, productID, id int64) error {
+	_, err := tx.Exec(`UPDATE product_images SET is_primary = (id = ?) WHERE product_id = ?`, id, productID)
+	if err != nil {
+		return fmt.Errorf("failed to set primary image: %w", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanImage(row scanner) (entities.ProductImage, error) {
+	var image entities.ProductImage
+	var sourceURL sql.NullString
+	err := row.Scan(
+		&image.ID,
+		&image.ProductID,
+		&image.SKU,
+		&image.Source,
+		&sourceURL,
+		&image.Position,
+		&image.Primary,
+		&image.FileName,
+		&image.ThumbnailName,
+		&image.ContentType,
+		&image.Width,
+		&image.Height,
+		&image.SizeBytes,
+		&image.Checksum,
+		&image.CreatedAt,
+		&image.UpdatedAt,
+	)
+	image.SourceURL = sourceURL.String
+	return image, err
+}
+
+func scanReview(row scanner) (entities.ImageReview, error) {
+	var review entities.ImageReview
+	var productName, itemID sql.NullString
+	var urls string
+	var reviewedAt sql.NullTime
+	err := row.Scan(
+		&review.ID,
+		&review.ProductID,
+		&review.SKU,
+		&productName,
+		&itemID,
+		&review.Match.Title,
+		&review.Match.Method,
+		&review.Match.Score,
+		&urls,
+		&review.Status,
+		&review.CreatedAt,
+		&reviewedAt,
+	)
+	if err != nil {
+		return review, err
+	}
+	review.ProductName = productName.String
+	review.Match.ItemID = itemID.String
+	if reviewedAt.Valid {
+		review.ReviewedAt = &reviewedAt.Time
+	}
+	if err := json.Unmarshal([]byte(urls), &review.Match.ImageURLs); err != nil {
+		return review, fmt.Errorf("image review %d has invalid image urls: %w", review.ID, err)
+	}
+	return review, nil
+}
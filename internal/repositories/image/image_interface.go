@@ -0,0 +1,29 @@
+package image
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type ImageRepository interface {
+	FindByProduct(productID int64) ([]entities.ProductImage, error)
+	FindByID(productID, id int64) (*entities.ProductImage, error)
+	FindByChecksum(productID int64, checksum string) (*entities.ProductImage, error)
+	Insert(image entities.ProductImage, primary bool) (int64, error)
+	Touch(id int64, sourceURL string) error
+	SetPrimary(productID, id int64) error
+	Delete(productID, id int64) error
+	LastFetched(productID int64, source entities.ImageSource) (*time.Time, error)
+	CreateReview(review entities.ImageReview) (int64, error)
+	FindReviews(status entities.ImageReviewStatus, limit int) ([]entities.ImageReview, error)
+	FindReviewsByProduct(productID int64) ([]entities.ImageReview, error)
+	FindReview(id int64) (*entities.ImageReview, error)
+	SetReviewStatus(id int64, status entities.ImageReviewStatus) error
+	EnqueueMissing() (int64, error)
+	EnqueueRefresh(before time.Time) (int64, error)
+	Requeue(productID int64) error
+	FindDueBackfills(limit int) ([]entities.ImageBackfillItem, error)
+	FindBackfills(status entities.ImageBackfillStatus, limit int) ([]entities.ImageBackfillItem, error)
+	UpdateBackfill(item entities.ImageBackfillItem) error
+	CountBackfills() (*entities.ImageBackfillProgress, error)
+}
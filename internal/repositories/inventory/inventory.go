@@ -3,7 +3,9 @@ package inventory
 import (
 	"database/sql"
 	"fmt"
+	"walmart-inventory-manager/internal/barcode"
 	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/money"
 )
 
 type inventoryRepository struct {
@@ -23,6 +25,7 @@ func (r *inventoryRepository) FindAll() ([]entities.Product, error) {
 			p.seller_sku,
 			p.upc,
 			p.product_name,
+			p.listing_status_id,
 			d.price,
 			d.available_to_sell_qty,
 			d.gtin
@@ -41,11 +44,13 @@ func (r *inventoryRepository) FindAll() ([]entities.Product, error) {
 	for rows.Next() {
 		var p entities.Product
 		var upc sql.NullString
+		var listingStatusID sql.NullInt32
 		err := rows.Scan(
 			&p.ID,
 			&p.SKU,
 			&upc,
 			&p.ProductName,
+			&listingStatusID,
 			&p.Price,
 			&p.AvailableToSellQTY,
 			&p.GTIN,
@@ -58,6 +63,8 @@ func (r *inventoryRepository) FindAll() ([]entities.Product, error) {
 		} else {
 			p.UPC = ""
 		}
+		p.ListingStatusID = entities.ListingStatus(listingStatusID.Int32)
+		p.ListingStatus = p.ListingStatusID.String()
 		products = append(products, p)
 	}
 
@@ -70,11 +77,25 @@ func (r *inventoryRepository) FindAll() ([]entities.Product, error) {
 
 func (r *inventoryRepository) InsertProduct(product entities.Product) (int64, error) {
 	query := `
-		INSERT INTO products (product_name, product_image, supplier_id, supplier_item_number, product_cost, upc, marketplace_id, seller_sku, createdAt, updatedAt)
-		VALUES (?, NULL, NULL, NULL, NULL, ?, 2, ?, NOW(), NOW())
+		INSERT INTO products (product_name, product_image, supplier_id, supplier_item_number, product_cost, lead_time_days, min_order_qty, upc, marketplace_id, seller_sku, createdAt, updatedAt)
+		VALUES (?, NULL, ?, ?, ?, ?, ?, ?, 2, ?, NOW(), NOW())
 	`
 
-	result, err := r.db.Exec(query, product.ProductName, product.UPC, product.SKU)
+	supply := product.Supply
+	if supply == nil {
+		supply = &entities.SupplySettings{}
+	}
+
+	result, err := r.db.Exec(query,
+		product.ProductName,
+		supply.SupplierID,
+		sql.NullString{String: supply.SupplierItemNumber, Valid: supply.SupplierItemNumber != ""},
+		supply.ProductCost,
+		supply.LeadTimeDays,
+		supply.MinOrderQty,
+		barcode.NormalizeUPC(product.UPC),
+		product.SKU,
+	)
 	if err != nil {
 		return 0, err
 	}
@@ -89,32 +110,29 @@ func (r *inventoryRepository) InsertProduct(product entities.Product) (int64, er
 
 func (r *inventoryRepository) InsertWmtProductDetail(productId int64, product entities.Product) error {
 	query := `
-		INSERT INTO wmt_product_details (product_id, gtin, wpid, available_to_sell_qty, price, createdAt, updatedAt)
-		VALUES (?, ?, ?, ?, ?, NOW(), NOW())
+		INSERT INTO wmt_product_details (product_id, gtin, wpid, available_to_sell_qty, price, availability, published_status, lifecycle_status, createdAt, updatedAt)
+		VALUES (?, ?, ?, 0, ?, ?, ?, ?, NOW(), NOW())
 	`
 
+	// available_to_sell_qty starts at zero; the quantity itself is written through the stock ledger.
 	_, err := r.db.Exec(query,
 		productId,
-		product.GTIN,
+		barcode.NormalizeGTIN(product.GTIN),
 		product.WPID,
-		product.AvailableToSellQTY,
 		product.Price,
+		product.Availability,
+		product.PublishedStatus,
+		product.LifecycleStatus,
 	)
 
 	return err
 }
 
-func (r *inventoryRepository) InsertProductImage(gtin, imageUrl string) error {
-	var productID int64
-
-	query := `SELECT product_id FROM wmt_product_details WHERE gtin = ? LIMIT 1`
-	err := r.db.QueryRow(query, gtin).Scan(&productID)
-	if err != nil {
-		return fmt.Errorf("failed to find product_id for gtin %s: %w", gtin, err)
-	}
-
-	updateQuery := `UPDATE products SET product_image = ? WHERE id = ?`
-	_, err = r.db.Exec(updateQuery, imageUrl, productID)
+// UpdateProductImage sets the URL shown as the product's main image; an empty
+// URL clears it.
+func (r *inventoryRepository) UpdateProductImage(productID int64, imageURL string) error {
+	_, err := r.db.Exec(`UPDATE products SET product_image = ? WHERE id = ?`,
+		sql.NullString{String: imageURL, Valid: imageURL != ""}, productID)
 	if err != nil {
 		return fmt.Errorf("failed to update product_image: %w", err)
 	}
@@ -160,69 +178,32 @@ func (r *inventoryRepository) GetFirstProductByMarketplaceID(marketplaceID int)
 	return &product, nil
 }
 
-func (r *inventoryRepository) UpdateListingStatus(productID int64, listingStatusID int) error {
-	query := `UPDATE products SET listing_status_id = ? WHERE id = ?`
-	_, err := r.db.Exec(query, listingStatusID, productID)
-	return err
+func (r *inventoryRepository) GetProductBySKU(sku string) (*entities.Product, error) {
+	return r.getProductDetail("p.seller_sku = ?", sku)
 }
 
-func (r *inventoryRepository) GetProductBySKU(sku string) (*entities.Product, error) {
-	query := `
-		SELECT 
-			p.id,
-			p.seller_sku,
-			p.upc,
-			p.product_name,
-			d.price,
-			d.available_to_sell_qty,
-			d.gtin,
-			p.warehouse_stock,
-			p.listing_status_id
-		FROM products p
-		INNER JOIN wmt_product_details d ON p.id = d.product_id
-		WHERE p.seller_sku = ?
-		LIMIT 1
-	`
+func (r *inventoryRepository) GetProductByGTIN(gtin string) (*entities.Product, error) {
+	return r.getProductByBarcode(gtin)
+}
 
-	var product entities.Product
-	var productID int64
-	var warehouseStock sql.NullInt32
-	var listingStatusID sql.NullInt32
-	var upc sql.NullString
+func (r *inventoryRepository) GetProductByUPC(upc string) (*entities.Product, error) {
+	return r.getProductByBarcode(upc)
+}
 
-	err := r.db.QueryRow(query, sku).Scan(
-		&productID,
-		&product.SKU,
-		&upc,
-		&product.ProductName,
-		&product.Price,
-		&product.AvailableToSellQTY,
-		&product.GTIN,
-		&warehouseStock,
-		&listingStatusID,
-	)
+// getProductByBarcode matches a UPC, EAN or GTIN in any format against both the
+// GTIN and the UPC column, which hold the normalized forms. Values that are not
+// valid barcodes are compared as typed.
+func (r *inventoryRepository) getProductByBarcode(code string) (*entities.Product, error) {
+	gtin, err := barcode.Parse(code)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, err
+		return r.getProductDetail("d.gtin = ? OR p.upc = ?", code, code)
 	}
 
-	product.ID = productID
-	if upc.Valid {
-		product.UPC = upc.String
-	}
-	if warehouseStock.Valid {
-		product.WarehouseStock = int(warehouseStock.Int32)
-	} else {
-		product.WarehouseStock = 0
-	}
-	if listingStatusID.Valid {
-		product.ListingStatusID = int(listingStatusID.Int32)
-	} else {
-		product.ListingStatusID = 0
+	upc, ok := gtin.UPCA()
+	if !ok {
+		upc = gtin.GTIN14()
 	}
-	return &product, nil
+	return r.getProductDetail("d.gtin = ? OR p.upc = ?", gtin.GTIN14(), upc)
 }
 
 func (r *inventoryRepository) GetAllProductsByMarketplaceID(marketplaceID int) ([]*entities.Product, error) {
@@ -287,38 +268,71 @@ func (r *inventoryRepository) GetAllProductsByMarketplaceID(marketplaceID int) (
 }
 
 func (r *inventoryRepository) GetProductByWPID(wpid string) (*entities.Product, error) {
+	return r.getProductDetail("d.wpid = ?", wpid)
+}
+
+// getProductDetail loads the full product view (Walmart details, stock, statuses
+// and last sync time) for the first product matching the given condition.
+func (r *inventoryRepository) getProductDetail(condition string, args ...interface{}) (*entities.Product, error) {
 	query := `
 		SELECT 
 			p.id,
 			p.seller_sku,
 			p.upc,
 			p.product_name,
+			p.product_image,
+			p.warehouse_stock,
+			p.listing_status_id,
 			d.price,
 			d.available_to_sell_qty,
 			d.gtin,
-			p.warehouse_stock,
-			p.product_image
+			d.wpid,
+			d.availability,
+			d.published_status,
+			d.lifecycle_status,
+			d.updatedAt,
+			p.supplier_id,
+			p.supplier_item_number,
+			p.product_cost,
+			p.lead_time_days,
+			p.min_order_qty
 		FROM products p
 		INNER JOIN wmt_product_details d ON p.id = d.product_id
-		WHERE d.wpid = ?
+		WHERE (` + condition + `)
 		LIMIT 1
 	`
 
 	var product entities.Product
-	var productID int64
-	var warehouseStock sql.NullInt32
-	var productImage sql.NullString
-	var upc sql.NullString
-	err := r.db.QueryRow(query, wpid).Scan(
-		&productID,
+	var upc, productImage, wpid sql.NullString
+	var availability, publishedStatus, lifecycleStatus sql.NullString
+	var warehouseStock, listingStatusID sql.NullInt32
+	var lastSyncedAt sql.NullTime
+	var supplierID sql.NullInt64
+	var supplierItemNumber sql.NullString
+	var productCost money.NullMoney
+	var leadTimeDays, minOrderQty sql.NullInt32
+
+	err := r.db.QueryRow(query, args...).Scan(
+		&product.ID,
 		&product.SKU,
 		&upc,
 		&product.ProductName,
+		&productImage,
+		&warehouseStock,
+		&listingStatusID,
 		&product.Price,
 		&product.AvailableToSellQTY,
 		&product.GTIN,
-		&warehouseStock,
-		&productImage,
+		&wpid,
+		&availability,
+		&publishedStatus,
+		&lifecycleStatus,
+		&lastSyncedAt,
+		&supplierID,
+		&supplierItemNumber,
+		&productCost,
+		&leadTimeDays,
+		&minOrderQty,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -327,20 +341,35 @@ func (r *inventoryRepository) GetProductByWPID(wpid string) (*entities.Product,
 		return nil, err
 	}
 
-	product.ID = productID
-	if upc.Valid {
-		product.UPC = upc.String
-	}
+	product.UPC = upc.String
+	product.ProductImage = productImage.String
+	product.WPID = wpid.String
+	product.Availability = availability.String
+	product.PublishedStatus = publishedStatus.String
+	product.LifecycleStatus = lifecycleStatus.String
 	if warehouseStock.Valid {
 		product.WarehouseStock = int(warehouseStock.Int32)
-	} else {
-		product.WarehouseStock = 0
 	}
-	if productImage.Valid {
-		product.ProductImage = productImage.String
-	} else {
-		product.ProductImage = ""
+	product.ListingStatusID = entities.ListingStatus(listingStatusID.Int32)
+	product.ListingStatus = product.ListingStatusID.String()
+	if lastSyncedAt.Valid {
+		product.LastSyncedAt = &lastSyncedAt.Time
+	}
+
+	product.Supply = &entities.SupplySettings{SupplierItemNumber: supplierItemNumber.String}
+	if supplierID.Valid {
+		product.Supply.SupplierID = &supplierID.Int64
+	}
+	product.Supply.ProductCost = productCost.Ptr()
+	if leadTimeDays.Valid {
+		days := int(leadTimeDays.Int32)
+		product.Supply.LeadTimeDays = &days
 	}
+	if minOrderQty.Valid {
+		qty := int(minOrderQty.Int32)
+		product.Supply.MinOrderQty = &qty
+	}
+
 	return &product, nil
 }
 
@@ -357,7 +386,7 @@ func (r *inventoryRepository) UpdateProduct(product entities.Product) error {
 
 	_, err := r.db.Exec(query,
 		product.ProductName,
-		product.UPC,
+		barcode.NormalizeUPC(product.UPC),
 		product.SKU,
 		product.ID,
 	)
@@ -370,16 +399,47 @@ func (r *inventoryRepository) UpdateWmtProductDetail(productID int64, product en
 		UPDATE wmt_product_details 
 		SET 
 			gtin = ?,
-			available_to_sell_qty = ?,
+			wpid = ?,
 			price = ?,
+			availability = ?,
+			published_status = ?,
+			lifecycle_status = ?,
 			updatedAt = NOW()
 		WHERE product_id = ?
 	`
 
 	_, err := r.db.Exec(query,
-		product.GTIN,
-		product.AvailableToSellQTY,
+		barcode.NormalizeGTIN(product.GTIN),
+		product.WPID,
 		product.Price,
+		product.Availability,
+		product.PublishedStatus,
+		product.LifecycleStatus,
+		productID,
+	)
+
+	return err
+}
+
+func (r *inventoryRepository) UpdateSupplySettings(productID int64, supply entities.SupplySettings) error {
+	query := `
+		UPDATE products
+		SET
+			supplier_id = ?,
+			supplier_item_number = ?,
+			product_cost = ?,
+			lead_time_days = ?,
+			min_order_qty = ?,
+			updatedAt = NOW()
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query,
+		supply.SupplierID,
+		sql.NullString{String: supply.SupplierItemNumber, Valid: supply.SupplierItemNumber != ""},
+		supply.ProductCost,
+		supply.LeadTimeDays,
+		supply.MinOrderQty,
 		productID,
 	)
 
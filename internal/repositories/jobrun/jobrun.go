@@ -0,0 +1,88 @@
+package jobrun
+
+import (
+	"database/sql"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type jobRunRepository struct {
+	db *sql.DB
+}
+
+func NewJobRunRepository(db *sql.DB) *jobRunRepository {
+	return &jobRunRepository{
+		db: db,
+	}
+}
+
+const selectJobRun = `
+	SELECT id, job, run_id, result, error, started_at, finished_at
+	FROM job_runs
+`
+
+func (r *jobRunRepository) Create(run entities.JobRun) (int64, error) {
+	query := `
+		INSERT INTO job_runs (job, run_id, result, error, started_at, finished_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := r.db.Exec(query, run.Job, run.RunID, run.Result, sql.NullString{String: run.Error, Valid: run.Error != ""}, run.StartedAt, run.FinishedAt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (r *jobRunRepository) Latest() ([]entities.JobRun, error) {
+	rows, err := r.db.Query(selectJobRun + ` WHERE id IN (SELECT MAX(id) FROM job_runs GROUP BY job)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []entities.JobRun{}
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
+func (r *jobRunRepository) LastSuccess(job string) (*entities.JobRun, error) {
+	row := r.db.QueryRow(selectJobRun+` WHERE job = ? AND result = ? ORDER BY finished_at DESC LIMIT 1`, job, entities.JobResultSuccess)
+
+	run, err := scanJobRun(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJobRun(row scanner) (entities.JobRun, error) {
+	var run entities.JobRun
+	var runError sql.NullString
+
+	err := row.Scan(&run.ID, &run.Job, &run.RunID, &run.Result, &runError, &run.StartedAt, &run.FinishedAt)
+	if err != nil {
+		return run, err
+	}
+	run.Error = runError.String
+
+	return run, nil
+}
@@ -0,0 +1,10 @@
+package jobrun
+
+import "walmart-inventory-manager/internal/entities"
+
+type JobRunRepository interface {
+	Create(run entities.JobRun) (int64, error)
+	// Latest returns the most recent run of every job that has run.
+	Latest() ([]entities.JobRun, error)
+	LastSuccess(job string) (*entities.JobRun, error)
+}
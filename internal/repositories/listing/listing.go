@@ -0,0 +1,227 @@
+package listing
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+)
+
+type listingRepository struct {
+	db *sql.DB
+}
+
+func NewListingRepository(db *sql.DB) *listingRepository {
+	return &listingRepository{
+		db: db,
+	}
+}
+
+func (r *listingRepository) FindRules() ([]entities.ListingStatusRule, error) {
+	query := `
+		SELECT id, position, lifecycle_status, published_status, availability, listing_status_id
+		FROM listing_status_rules
+		ORDER BY position, id
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []entities.ListingStatusRule{}
+	for rows.Next() {
+		var rule entities.ListingStatusRule
+		var lifecycle, published, availability sql.NullString
+		err := rows.Scan(&rule.ID, &rule.Position, &lifecycle, &published, &availability, &rule.StatusID)
+		if err != nil {
+			return nil, err
+		}
+		rule.LifecycleStatus = lifecycle.String
+		rule.PublishedStatus = published.String
+		rule.Availability = availability.String
+		rule.Status = rule.StatusID.String()
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r *listingRepository) ReplaceRules(rules []entities.ListingStatusRule) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM listing_status_rules`); err != nil {
+		return fmt.Errorf("failed to clear listing status rules: %w", err)
+	}
+
+	query := `
+		INSERT INTO listing_status_rules (position, lifecycle_status, published_status, availability, listing_status_id)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	for _, rule := range rules {
+		_, err := tx.Exec(query,
+			rule.Position,
+			nullString(rule.LifecycleStatus),
+			nullString(rule.PublishedStatus),
+			nullString(rule.Availability),
+			rule.StatusID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert listing status rule at position %d: %w", rule.Position, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ApplyTransition moves a product to the given status and records the change
+// in the history. Nothing is written, and nil is returned, when the product
+// already has that status.
+func (r *listingRepository) ApplyTransition(productID int64, to entities.ListingStatus, reason string, syncRunID string) (*entities.ListingTransition, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	transition := entities.ListingTransition{
+		ProductID:  productID,
+		ToID:       to,
+		Reason:     reason,
+		SyncRunID:  syncRunID,
+		OccurredAt: time.Now(),
+	}
+
+	var sku sql.NullString
+	var current sql.NullInt32
+	err = tx.QueryRow(`SELECT seller_sku, listing_status_id FROM products WHERE id = ? FOR UPDATE`, productID).Scan(&sku, &current)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewResourceNotFound(fmt.Sprintf("product %d not found", productID))
+		}
+		return nil, fmt.Errorf("failed to lock product %d: %w", productID, err)
+	}
+	transition.SKU = sku.String
+	transition.FromID = entities.ListingStatus(current.Int32)
+	if current.Valid && transition.FromID == to {
+		return nil, nil
+	}
+
+	if _, err := tx.Exec(`UPDATE products SET listing_status_id = ? WHERE id = ?`, to, productID); err != nil {
+		return nil, fmt.Errorf("failed to update listing status for %s: %w", transition.SKU, err)
+	}
+
+	query := `
+		INSERT INTO listing_status_history (product_id, seller_sku, from_status_id, to_status_id, reason, sync_run_id, createdAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.Exec(query,
+		productID,
+		transition.SKU,
+		transition.FromID,
+		transition.ToID,
+		truncate(reason, 255),
+		nullString(syncRunID),
+		transition.OccurredAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record listing status change for %s: %w", transition.SKU, err)
+	}
+
+	transition.ID, err = result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit listing status change: %w", err)
+	}
+
+	transition.From = transition.FromID.String()
+	transition.To = transition.ToID.String()
+	return &transition, nil
+}
+
+func (r *listingRepository) FindHistory(productID int64, limit int) ([]entities.ListingTransition, error) {
+	query := `
+		SELECT id, product_id, seller_sku, from_status_id, to_status_id, reason, sync_run_id, createdAt
+		FROM listing_status_history
+		WHERE product_id = ?
+		ORDER BY createdAt DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []entities.ListingTransition{}
+	for rows.Next() {
+		var t entities.ListingTransition
+		var syncRunID sql.NullString
+		err := rows.Scan(&t.ID, &t.ProductID, &t.SKU, &t.FromID, &t.ToID, &t.Reason, &syncRunID, &t.OccurredAt)
+		if err != nil {
+			return nil, err
+		}
+		t.SyncRunID = syncRunID.String
+		t.From = t.FromID.String()
+		t.To = t.ToID.String()
+		history = append(history, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// MarkSeen resets the missing counter of a product reported by Walmart.
+func (r *listingRepository) MarkSeen(productID int64) error {
+	_, err := r.db.Exec(`UPDATE products SET missing_sync_count = 0, last_seen_at = NOW() WHERE id = ?`, productID)
+	return err
+}
+
+// MarkMissing counts one more consecutive sync the product was missing from
+// and returns the new count.
+func (r *listingRepository) MarkMissing(productID int64) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE products SET missing_sync_count = missing_sync_count + 1 WHERE id = ?`, productID); err != nil {
+		return 0, fmt.Errorf("failed to count missing sync for product %d: %w", productID, err)
+	}
+
+	var misses int
+	if err := tx.QueryRow(`SELECT missing_sync_count FROM products WHERE id = ?`, productID).Scan(&misses); err != nil {
+		return 0, fmt.Errorf("failed to read missing sync count for product %d: %w", productID, err)
+	}
+
+	return misses, tx.Commit()
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,12 @@
+package listing
+
+import "walmart-inventory-manager/internal/entities"
+
+type ListingRepository interface {
+	FindRules() ([]entities.ListingStatusRule, error)
+	ReplaceRules(rules []entities.ListingStatusRule) error
+	ApplyTransition(productID int64, to entities.ListingStatus, reason string, syncRunID string) (*entities.ListingTransition, error)
+	FindHistory(productID int64, limit int) ([]entities.ListingTransition, error)
+	MarkSeen(productID int64) error
+	MarkMissing(productID int64) (int, error)
+}
@@ -0,0 +1,162 @@
+package location
+
+import (
+	"database/sql"
+	"fmt"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type locationRepository struct {
+	db *sql.DB
+}
+
+func NewLocationRepository(db *sql.DB) *locationRepository {
+	return &locationRepository{
+		db: db,
+	}
+}
+
+const selectLocation = `
+	SELECT id, code, name, type, address, is_default, active, createdAt, updatedAt
+	FROM locations
+`
+
+func (r *locationRepository) FindAll() ([]entities.Location, error) {
+	rows, err := r.db.Query(selectLocation + ` ORDER BY type, code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []entities.Location{}
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+func (r *locationRepository) FindByCode(code string) (*entities.Location, error) {
+	location, err := scanLocation(r.db.QueryRow(selectLocation+` WHERE code = ?`, code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func (r *locationRepository) Create(location entities.Location) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if location.IsDefault {
+		if err := clearDefault(tx, location.Type); err != nil {
+			return 0, err
+		}
+	}
+
+	query := `
+		INSERT INTO locations (code, name, type, address, is_default, active, createdAt, updatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+	`
+	result, err := tx.Exec(query,
+		location.Code,
+		location.Name,
+		location.Type,
+		location.Address,
+		location.IsDefault,
+		location.Active,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (r *locationRepository) Update(location entities.Location) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if location.IsDefault {
+		if err := clearDefault(tx, location.Type); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE locations
+		SET
+			name = ?,
+			address = ?,
+			is_default = ?,
+			active = ?,
+			updatedAt = NOW()
+		WHERE id = ?
+	`
+	_, err = tx.Exec(query,
+		location.Name,
+		location.Address,
+		location.IsDefault,
+		location.Active,
+		location.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// clearDefault unsets the current default so only one location per type is the default.
+func clearDefault(tx *sql.Tx, locationType entities.LocationType) error {
+	_, err := tx.Exec(`UPDATE locations SET is_default = 0 WHERE type = ? AND is_default = 1`, locationType)
+	if err != nil {
+		return fmt.Errorf("failed to clear default location: %w", err)
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanLocation(row scanner) (entities.Location, error) {
+	var location entities.Location
+	var address sql.NullString
+	err := row.Scan(
+		&location.ID,
+		&location.Code,
+		&location.Name,
+		&location.Type,
+		&address,
+		&location.IsDefault,
+		&location.Active,
+		&location.CreatedAt,
+		&location.UpdatedAt,
+	)
+	location.Address = address.String
+	return location, err
+}
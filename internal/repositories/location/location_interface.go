@@ -0,0 +1,10 @@
+package location
+
+import "walmart-inventory-manager/internal/entities"
+
+type LocationRepository interface {
+	FindAll() ([]entities.Location, error)
+	FindByCode(code string) (*entities.Location, error)
+	Create(location entities.Location) (int64, error)
+	Update(location entities.Location) error
+}
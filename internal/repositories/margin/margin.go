@@ -0,0 +1,175 @@
+package margin
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/money"
+)
+
+type marginRepository struct {
+	db *sql.DB
+}
+
+func NewMarginRepository(db *sql.DB) *marginRepository {
+	return &marginRepository{
+		db: db,
+	}
+}
+
+func (r *marginRepository) FindFeeSchedule() (*entities.FeeSchedule, error) {
+	schedule := &entities.FeeSchedule{
+		Referral:    []entities.ReferralFee{},
+		Fulfillment: []entities.FulfillmentFee{},
+	}
+
+	rows, err := r.db.Query(`SELECT category, percent, minimum_fee FROM referral_fees ORDER BY category`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fee entities.ReferralFee
+		if err := rows.Scan(&fee.Category, &fee.Percent, &fee.MinimumFee); err != nil {
+			return nil, err
+		}
+		schedule.Referral = append(schedule.Referral, fee)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tierRows, err := r.db.Query(`SELECT size_tier, fee_per_unit FROM fulfillment_fees ORDER BY size_tier`)
+	if err != nil {
+		return nil, err
+	}
+	defer tierRows.Close()
+
+	for tierRows.Next() {
+		var fee entities.FulfillmentFee
+		if err := tierRows.Scan(&fee.SizeTier, &fee.FeePerUnit); err != nil {
+			return nil, err
+		}
+		schedule.Fulfillment = append(schedule.Fulfillment, fee)
+	}
+	if err = tierRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (r *marginRepository) ReplaceReferralFees(fees []entities.ReferralFee) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM referral_fees`); err != nil {
+		return fmt.Errorf("failed to clear referral fees: %w", err)
+	}
+
+	for _, fee := range fees {
+		_, err := tx.Exec(`INSERT INTO referral_fees (category, percent, minimum_fee) VALUES (?, ?, ?)`, fee.Category, fee.Percent, fee.MinimumFee)
+		if err != nil {
+			return fmt.Errorf("failed to insert referral fee for %q: %w", fee.Category, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *marginRepository) ReplaceFulfillmentFees(fees []entities.FulfillmentFee) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM fulfillment_fees`); err != nil {
+		return fmt.Errorf("failed to clear fulfillment fees: %w", err)
+	}
+
+	for _, fee := range fees {
+		_, err := tx.Exec(`INSERT INTO fulfillment_fees (size_tier, fee_per_unit) VALUES (?, ?)`, fee.SizeTier, fee.FeePerUnit)
+		if err != nil {
+			return fmt.Errorf("failed to insert fulfillment fee for %q: %w", fee.SizeTier, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *marginRepository) UpdateFeeProfile(productID int64, profile entities.FeeProfile) error {
+	_, err := r.db.Exec(`UPDATE products SET category = ?, size_tier = ? WHERE id = ?`,
+		nullString(profile.Category), nullString(profile.SizeTier), productID)
+	return err
+}
+
+// FindMarginItems returns every product with its list price, cost and the
+// sales recorded between from and to (inclusive).
+func (r *marginRepository) FindMarginItems(from, to time.Time) ([]entities.MarginItem, error) {
+	query := `
+		SELECT
+			p.seller_sku,
+			p.product_name,
+			p.category,
+			p.size_tier,
+			COALESCE(d.price, 0),
+			p.product_cost,
+			COALESCE(SUM(s.units), 0),
+			COALESCE(SUM(s.wfs_units), 0),
+			COALESCE(SUM(s.revenue), 0)
+		FROM products p
+		LEFT JOIN wmt_product_details d ON d.product_id = p.id
+		LEFT JOIN daily_sales s ON s.seller_sku = p.seller_sku AND s.sale_date BETWEEN ? AND ?
+		WHERE p.seller_sku IS NOT NULL
+		GROUP BY p.id, p.seller_sku, p.product_name, p.category, p.size_tier, d.price, p.product_cost
+		ORDER BY p.seller_sku
+	`
+
+	rows, err := r.db.Query(query, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []entities.MarginItem
+	for rows.Next() {
+		var item entities.MarginItem
+		var productName, category, sizeTier sql.NullString
+		var productCost money.NullMoney
+		err := rows.Scan(
+			&item.SKU,
+			&productName,
+			&category,
+			&sizeTier,
+			&item.Price,
+			&productCost,
+			&item.UnitsSold,
+			&item.WFSUnits,
+			&item.Revenue,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.ProductName = productName.String
+		item.Category = category.String
+		item.SizeTier = sizeTier.String
+		item.ProductCost = productCost.Ptr()
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,14 @@
+package margin
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type MarginRepository interface {
+	FindFeeSchedule() (*entities.FeeSchedule, error)
+	ReplaceReferralFees(fees []entities.ReferralFee) error
+	ReplaceFulfillmentFees(fees []entities.FulfillmentFee) error
+	UpdateFeeProfile(productID int64, profile entities.FeeProfile) error
+	FindMarginItems(from, to time.Time) ([]entities.MarginItem, error)
+}
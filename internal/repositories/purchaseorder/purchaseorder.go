@@ -0,0 +1,438 @@
+package purchaseorder
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/repositories/stock"
+)
+
+type purchaseOrderRepository struct {
+	db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) *purchaseOrderRepository {
+	return &purchaseOrderRepository{
+		db: db,
+	}
+}
+
+func (r *purchaseOrderRepository) FindAll(filter entities.PurchaseOrderFilter) ([]entities.PurchaseOrder, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Status != "" {
+		conditions = append(conditions, "o.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.SupplierID != 0 {
+		conditions = append(conditions, "o.supplier_id = ?")
+		args = append(args, filter.SupplierID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return r.findOrders(where, args...)
+}
+
+func (r *purchaseOrderRepository) FindByID(id int64) (*entities.PurchaseOrder, error) {
+	orders, err := r.findOrders(" WHERE o.id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return nil, nil
+	}
+
+	return &orders[0], nil
+}
+
+// findOrders loads the matching orders and their lines with one query each.
+func (r *purchaseOrderRepository) findOrders(where string, args ...interface{}) ([]entities.PurchaseOrder, error) {
+	query := `
+		SELECT o.id, o.po_number, o.supplier_id, s.name, o.status, o.expected_date, o.note, o.createdAt, o.updatedAt, o.closedAt
+		FROM purchase_orders o
+		LEFT JOIN suppliers s ON s.id = o.supplier_id
+	` + where + ` ORDER BY o.id DESC`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []entities.PurchaseOrder{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		var order entities.PurchaseOrder
+		var number, supplierName, note sql.NullString
+		var expectedDate, closedAt sql.NullTime
+		err := rows.Scan(
+			&order.ID,
+			&number,
+			&order.SupplierID,
+			&supplierName,
+			&order.Status,
+			&expectedDate,
+			&note,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+			&closedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		order.Number = number.String
+		order.SupplierName = supplierName.String
+		order.Note = note.String
+		if expectedDate.Valid {
+			order.ExpectedDate = &expectedDate.Time
+		}
+		if closedAt.Valid {
+			order.ClosedAt = &closedAt.Time
+		}
+		order.Lines = []entities.PurchaseOrderLine{}
+		index[order.ID] = len(orders)
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	linesQuery := `
+		SELECT l.id, l.purchase_order_id, l.product_id, l.seller_sku, p.product_name, l.quantity_ordered, l.quantity_received, l.unit_cost, l.expected_date
+		FROM purchase_order_lines l
+		INNER JOIN purchase_orders o ON o.id = l.purchase_order_id
+		LEFT JOIN products p ON p.id = l.product_id
+	` + where + ` ORDER BY l.id`
+
+	lineRows, err := r.db.Query(linesQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer lineRows.Close()
+
+	for lineRows.Next() {
+		var line entities.PurchaseOrderLine
+		var orderID int64
+		var productName sql.NullString
+		var expectedDate sql.NullTime
+		err := lineRows.Scan(
+			&line.ID,
+			&orderID,
+			&line.ProductID,
+			&line.SKU,
+			&productName,
+			&line.QuantityOrdered,
+			&line.QuantityReceived,
+			&line.UnitCost,
+			&expectedDate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		line.ProductName = productName.String
+		if expectedDate.Valid {
+			line.ExpectedDate = &expectedDate.Time
+		}
+
+		i, ok := index[orderID]
+		if !ok {
+			continue
+		}
+		orders[i].Lines = append(orders[i].Lines, line)
+		orders[i].TotalUnits += line.QuantityOrdered
+		total, err := orders[i].TotalCost.Add(line.UnitCost.Mul(int64(line.QuantityOrdered)))
+		if err != nil {
+			return nil, fmt.Errorf("purchase order %d: %w", orderID, err)
+		}
+		orders[i].TotalCost = total
+	}
+
+	return orders, lineRows.Err()
+}
+
+func (r *purchaseOrderRepository) Create(order entities.PurchaseOrder) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertQuery := `
+		INSERT INTO purchase_orders (supplier_id, status, expected_date, note, createdAt, updatedAt)
+		VALUES (?, ?, ?, ?, NOW(), NOW())
+	`
+	result, err := tx.Exec(insertQuery,
+		order.SupplierID,
+		entities.PurchaseOrderStatusOpen,
+		order.ExpectedDate,
+		nullString(order.Note),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create purchase order: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(`UPDATE purchase_orders SET po_number = ? WHERE id = ?`, fmt.Sprintf("PO-%06d", id), id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to number purchase order: %w", err)
+	}
+
+	lineQuery := `
+		INSERT INTO purchase_order_lines (purchase_order_id, product_id, seller_sku, quantity_ordered, unit_cost, expected_date)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	for _, line := range order.Lines {
+		_, err := tx.Exec(lineQuery, id, line.ProductID, line.SKU, line.QuantityOrdered, line.UnitCost, line.ExpectedDate)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add %s to purchase order: %w", line.SKU, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit purchase order: %w", err)
+	}
+
+	return id, nil
+}
+
+// Receive books a delivery against an order. The received quantities, the
+// receipt and the ledger entries that raise warehouse stock are written in one
+// transaction.
+func (r *purchaseOrderRepository) Receive(receipt entities.PurchaseOrderReceipt) (*entities.PurchaseOrderReceipt, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var status entities.PurchaseOrderStatus
+	var number sql.NullString
+	err = tx.QueryRow(`SELECT status, po_number FROM purchase_orders WHERE id = ? FOR UPDATE`, receipt.PurchaseOrderID).Scan(&status, &number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewResourceNotFound(fmt.Sprintf("purchase order %d not found", receipt.PurchaseOrderID))
+		}
+		return nil, fmt.Errorf("failed to lock purchase order: %w", err)
+	}
+	if !status.Inbound() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("purchase order %s is %s", number.String, status))
+	}
+
+	lines, err := lockLines(tx, receipt.PurchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	received, err := receiptLines(receipt.Lines, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := stock.ResolveLocation(tx, receipt.Location, entities.StockTypeWarehouse)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Location = location.Code
+	receipt.ReceivedAt = time.Now()
+
+	result, err := tx.Exec(`INSERT INTO purchase_order_receipts (purchase_order_id, location_id, note, receivedAt) VALUES (?, ?, ?, ?)`,
+		receipt.PurchaseOrderID, location.ID, nullString(receipt.Note), receipt.ReceivedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record receipt: %w", err)
+	}
+	receipt.ID, err = result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	movements := make([]entities.StockMovement, 0, len(received))
+	for i, line := range received {
+		result, err := tx.Exec(`INSERT INTO purchase_order_receipt_lines (receipt_id, purchase_order_line_id, quantity) VALUES (?, ?, ?)`,
+			receipt.ID, line.LineID, line.Quantity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record receipt of %s: %w", line.SKU, err)
+		}
+		received[i].ID, err = result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(`UPDATE purchase_order_lines SET quantity_received = quantity_received + ? WHERE id = ?`, line.Quantity, line.LineID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update received quantity of %s: %w", line.SKU, err)
+		}
+		lines[line.LineID].QuantityReceived += line.Quantity
+
+		movements = append(movements, entities.StockMovement{
+			ProductID:    lines[line.LineID].ProductID,
+			SKU:          line.SKU,
+			StockType:    entities.StockTypeWarehouse,
+			LocationID:   location.ID,
+			LocationCode: location.Code,
+			Source:       entities.MovementSourcePurchaseOrder,
+			Reason:       entities.StockReasonReceipt,
+			Quantity:     line.Quantity,
+			ReferenceID:  "po-receipt-" + strconv.FormatInt(received[i].ID, 10),
+			Note:         number.String,
+			CreatedAt:    receipt.ReceivedAt,
+		})
+	}
+
+	if _, err := stock.AppendMovements(tx, movements); err != nil {
+		return nil, err
+	}
+
+	status = entities.PurchaseOrderStatusReceived
+	for _, line := range lines {
+		if line.Outstanding() > 0 {
+			status = entities.PurchaseOrderStatusPartiallyReceived
+			break
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE purchase_orders SET status = ?, updatedAt = NOW() WHERE id = ?`, status, receipt.PurchaseOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update purchase order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit receipt: %w", err)
+	}
+
+	receipt.Lines = received
+	return &receipt, nil
+}
+
+func lockLines(tx *sql.Tx, orderID int64) (map[int64]*entities.PurchaseOrderLine, error) {
+	query := `
+		SELECT id, product_id, seller_sku, quantity_ordered, quantity_received
+		FROM purchase_order_lines
+		WHERE purchase_order_id = ?
+		FOR UPDATE
+	`
+	rows, err := tx.Query(query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock purchase order lines: %w", err)
+	}
+	defer rows.Close()
+
+	lines := make(map[int64]*entities.PurchaseOrderLine)
+	for rows.Next() {
+		var line entities.PurchaseOrderLine
+		if err := rows.Scan(&line.ID, &line.ProductID, &line.SKU, &line.QuantityOrdered, &line.QuantityReceived); err != nil {
+			return nil, err
+		}
+		lines[line.ID] = &line
+	}
+
+	return lines, rows.Err()
+}
+
+// receiptLines matches the requested lines to the order by SKU and checks no
+// more is received than is outstanding. An empty request receives everything
+// still outstanding.
+func receiptLines(requested []entities.PurchaseOrderReceiptLine, lines map[int64]*entities.PurchaseOrderLine) ([]entities.PurchaseOrderReceiptLine, error) {
+	bySKU := make(map[string]*entities.PurchaseOrderLine, len(lines))
+	for _, line := range lines {
+		bySKU[line.SKU] = line
+	}
+
+	if len(requested) == 0 {
+		for _, line := range lines {
+			if line.Outstanding() > 0 {
+				requested = append(requested, entities.PurchaseOrderReceiptLine{LineID: line.ID, SKU: line.SKU, Quantity: line.Outstanding()})
+			}
+		}
+		sort.Slice(requested, func(i, j int) bool { return requested[i].LineID < requested[j].LineID })
+		if len(requested) == 0 {
+			return nil, errors.NewBadRequest("nothing is outstanding on this purchase order")
+		}
+	}
+
+	received := make([]entities.PurchaseOrderReceiptLine, 0, len(requested))
+	pending := make(map[int64]int)
+	for _, req := range requested {
+		line, ok := bySKU[req.SKU]
+		if !ok {
+			return nil, errors.NewBadRequest(fmt.Sprintf("sku %s is not on this purchase order", req.SKU))
+		}
+		pending[line.ID] += req.Quantity
+		if pending[line.ID] > line.Outstanding() {
+			return nil, errors.NewBadRequest(fmt.Sprintf("cannot receive %d of %s, only %d outstanding", pending[line.ID], req.SKU, line.Outstanding()))
+		}
+		received = append(received, entities.PurchaseOrderReceiptLine{LineID: line.ID, SKU: line.SKU, Quantity: req.Quantity})
+	}
+
+	return received, nil
+}
+
+func (r *purchaseOrderRepository) Close(id int64) error {
+	_, err := r.db.Exec(`UPDATE purchase_orders SET status = ?, closedAt = NOW(), updatedAt = NOW() WHERE id = ?`,
+		entities.PurchaseOrderStatusClosed, id)
+	return err
+}
+
+// FindInbound sums the outstanding quantity on open and partially received
+// orders per SKU, optionally for a single SKU.
+func (r *purchaseOrderRepository) FindInbound(sku string) ([]entities.InboundStock, error) {
+	query := `
+		SELECT
+			l.seller_sku,
+			MAX(p.product_name),
+			SUM(l.quantity_ordered - l.quantity_received),
+			COUNT(DISTINCT o.id),
+			MIN(COALESCE(l.expected_date, o.expected_date))
+		FROM purchase_order_lines l
+		INNER JOIN purchase_orders o ON o.id = l.purchase_order_id
+		LEFT JOIN products p ON p.id = l.product_id
+		WHERE o.status IN (?, ?)
+			AND l.quantity_received < l.quantity_ordered
+			AND (? = '' OR l.seller_sku = ?)
+		GROUP BY l.seller_sku
+		ORDER BY l.seller_sku
+	`
+
+	rows, err := r.db.Query(query, entities.PurchaseOrderStatusOpen, entities.PurchaseOrderStatusPartiallyReceived, sku, sku)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inbound := []entities.InboundStock{}
+	for rows.Next() {
+		var item entities.InboundStock
+		var productName sql.NullString
+		var nextExpected sql.NullTime
+		if err := rows.Scan(&item.SKU, &productName, &item.Quantity, &item.PurchaseOrders, &nextExpected); err != nil {
+			return nil, err
+		}
+		item.ProductName = productName.String
+		if nextExpected.Valid {
+			item.NextExpectedDate = &nextExpected.Time
+		}
+		inbound = append(inbound, item)
+	}
+
+	return inbound, rows.Err()
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,12 @@
+package purchaseorder
+
+import "walmart-inventory-manager/internal/entities"
+
+type PurchaseOrderRepository interface {
+	FindAll(filter entities.PurchaseOrderFilter) ([]entities.PurchaseOrder, error)
+	FindByID(id int64) (*entities.PurchaseOrder, error)
+	Create(order entities.PurchaseOrder) (int64, error)
+	Receive(receipt entities.PurchaseOrderReceipt) (*entities.PurchaseOrderReceipt, error)
+	Close(id int64) error
+	FindInbound(sku string) ([]entities.InboundStock, error)
+}
@@ -0,0 +1,138 @@
+package reconciliation
+
+import (
+	"database/sql"
+	"fmt"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type reconciliationRepository struct {
+	db *sql.DB
+}
+
+func NewReconciliationRepository(db *sql.DB) *reconciliationRepository {
+	return &reconciliationRepository{
+		db: db,
+	}
+}
+
+func (r *reconciliationRepository) SaveRun(run entities.ReconciliationRun) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO reconciliation_runs (sync_run_id, tolerance_units, tolerance_percent, total_skus, discrepancy_count, createdAt)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.Exec(query,
+		sql.NullString{String: run.SyncRunID, Valid: run.SyncRunID != ""},
+		run.Tolerance.Units,
+		run.Tolerance.Percent,
+		run.TotalSKUs,
+		run.DiscrepancyCount,
+		run.CreatedAt,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert reconciliation run: %w", err)
+	}
+
+	runID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	itemQuery := `
+		INSERT INTO reconciliation_discrepancies (run_id, product_id, seller_sku, product_name, warehouse_stock, walmart_available, difference, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	for _, item := range run.Items {
+		_, err := tx.Exec(itemQuery,
+			runID,
+			item.ProductID,
+			item.SKU,
+			item.ProductName,
+			item.WarehouseStock,
+			item.WalmartAvailable,
+			item.Difference,
+			item.Status,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert discrepancy for %s: %w", item.SKU, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return runID, nil
+}
+
+func (r *reconciliationRepository) FindLatestRun() (*entities.ReconciliationRun, error) {
+	query := `
+		SELECT id, sync_run_id, tolerance_units, tolerance_percent, total_skus, discrepancy_count, createdAt
+		FROM reconciliation_runs
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	var run entities.ReconciliationRun
+	var syncRunID sql.NullString
+	err := r.db.QueryRow(query).Scan(
+		&run.ID,
+		&syncRunID,
+		&run.Tolerance.Units,
+		&run.Tolerance.Percent,
+		&run.TotalSKUs,
+		&run.DiscrepancyCount,
+		&run.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	run.SyncRunID = syncRunID.String
+
+	itemQuery := `
+		SELECT product_id, seller_sku, product_name, warehouse_stock, walmart_available, difference, status
+		FROM reconciliation_discrepancies
+		WHERE run_id = ?
+		ORDER BY ABS(difference) DESC, seller_sku
+	`
+	rows, err := r.db.Query(itemQuery, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	run.Items = []entities.ReconciliationItem{}
+	for rows.Next() {
+		var item entities.ReconciliationItem
+		var productName sql.NullString
+		err := rows.Scan(
+			&item.ProductID,
+			&item.SKU,
+			&productName,
+			&item.WarehouseStock,
+			&item.WalmartAvailable,
+			&item.Difference,
+			&item.Status,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.ProductName = productName.String
+		run.Items = append(run.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
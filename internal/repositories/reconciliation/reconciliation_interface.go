@@ -0,0 +1,8 @@
+package reconciliation
+
+import "walmart-inventory-manager/internal/entities"
+
+type ReconciliationRepository interface {
+	SaveRun(run entities.ReconciliationRun) (int64, error)
+	FindLatestRun() (*entities.ReconciliationRun, error)
+}
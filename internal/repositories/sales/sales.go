@@ -0,0 +1,88 @@
+package sales
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type salesRepository struct {
+	db *sql.DB
+}
+
+func NewSalesRepository(db *sql.DB) *salesRepository {
+	return &salesRepository{
+		db: db,
+	}
+}
+
+// ReplaceDailySales swaps every stored day between from and to (inclusive) for
+// the given totals, so days whose orders were all cancelled drop back to zero.
+func (r *salesRepository) ReplaceDailySales(from, to time.Time, sales []entities.DailySales) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM daily_sales WHERE sale_date BETWEEN ? AND ?`, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	if err != nil {
+		return fmt.Errorf("failed to clear daily sales: %w", err)
+	}
+
+	query := `
+		INSERT INTO daily_sales (seller_sku, sale_date, units, wfs_units, orders, revenue, updatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, NOW())
+	`
+	for _, s := range sales {
+		_, err := tx.Exec(query, s.SKU, s.Date.Format(time.DateOnly), s.Units, s.WFSUnits, s.Orders, s.Revenue)
+		if err != nil {
+			return fmt.Errorf("failed to insert daily sales for %s: %w", s.SKU, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *salesRepository) LastSaleDate() (*time.Time, error) {
+	var last sql.NullTime
+	err := r.db.QueryRow(`SELECT MAX(sale_date) FROM daily_sales`).Scan(&last)
+	if err != nil {
+		return nil, err
+	}
+	if !last.Valid {
+		return nil, nil
+	}
+	return &last.Time, nil
+}
+
+func (r *salesRepository) FindDailySales(since time.Time) ([]entities.DailySales, error) {
+	query := `
+		SELECT seller_sku, sale_date, units, wfs_units, orders, revenue
+		FROM daily_sales
+		WHERE sale_date >= ?
+		ORDER BY seller_sku, sale_date
+	`
+
+	rows, err := r.db.Query(query, since.Format(time.DateOnly))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sales []entities.DailySales
+	for rows.Next() {
+		var s entities.DailySales
+		if err := rows.Scan(&s.SKU, &s.Date, &s.Units, &s.WFSUnits, &s.Orders, &s.Revenue); err != nil {
+			return nil, err
+		}
+		sales = append(sales, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sales, nil
+}
@@ -0,0 +1,12 @@
+package sales
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type SalesRepository interface {
+	ReplaceDailySales(from, to time.Time, sales []entities.DailySales) error
+	LastSaleDate() (*time.Time, error)
+	FindDailySales(since time.Time) ([]entities.DailySales, error)
+}
@@ -0,0 +1,695 @@
+package stock
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+)
+
+type stockRepository struct {
+	db *sql.DB
+}
+
+func NewStockRepository(db *sql.DB) *stockRepository {
+	return &stockRepository{
+		db: db,
+	}
+}
+
+// ApplyChanges turns manual warehouse stock changes into ledger entries inside a
+// single transaction, so a bulk request either updates all SKUs or none of them.
+func (r *stockRepository) ApplyChanges(changes []entities.StockChange) ([]entities.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	movements := make([]entities.StockMovement, 0, len(changes))
+	for _, change := range changes {
+		movement := entities.StockMovement{
+			SKU:          change.SKU,
+			StockType:    entities.StockTypeWarehouse,
+			LocationCode: change.Location,
+			Source:       entities.MovementSourceManual,
+			Reason:       change.Reason,
+			Quantity:     change.Delta,
+			Note:         change.Note,
+		}
+
+		movement, err = appendMovement(tx, movement, change.SetTo)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, movement)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit stock changes: %w", err)
+	}
+
+	return movements, nil
+}
+
+// RecordMovements appends delta-based movements (orders, returns, receipts). A
+// movement whose reference was already recorded for the same product, stock type
+// and source is skipped, which makes replays of the same event harmless.
+func (r *stockRepository) RecordMovements(movements []entities.StockMovement) ([]entities.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	recorded, err := AppendMovements(tx, movements)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit stock movements: %w", err)
+	}
+
+	return recorded, nil
+}
+
+// AppendMovements writes ledger entries inside a transaction owned by the
+// caller, so other repositories can move stock atomically with their own
+// writes. Movements that were skipped as zero or already recorded are left out
+// of the result.
+func AppendMovements(tx *sql.Tx, movements []entities.StockMovement) ([]entities.StockMovement, error) {
+	recorded := make([]entities.StockMovement, 0, len(movements))
+	for _, m := range movements {
+		m, err := appendMovement(tx, m, nil)
+		if err != nil {
+			return nil, err
+		}
+		if m.ID != 0 {
+			recorded = append(recorded, m)
+		}
+	}
+
+	return recorded, nil
+}
+
+// RecordBalance records an observed absolute balance (e.g. the quantity Walmart
+// reports during a sync) as the delta against the ledger. It returns nil when the
+// balance did not change.
+func (r *stockRepository) RecordBalance(movement entities.StockMovement, balance int) (*entities.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	movement, err = appendMovement(tx, movement, &balance)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit stock balance: %w", err)
+	}
+
+	if movement.ID == 0 {
+		return nil, nil
+	}
+	return &movement, nil
+}
+
+// SyncShipNodes records the quantities Walmart reports per ship node for one
+// product. Nodes that previously held stock but are missing from the report are
+// brought back to zero, and unknown nodes are registered as locations.
+func (r *stockRepository) SyncShipNodes(productID int64, sku string, referenceID string, nodes map[string]int) ([]entities.StockMovement, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	productID, sku, err = lockProduct(tx, productID, sku)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]int, len(nodes))
+	for code, qty := range nodes {
+		if err := ensureShipNode(tx, code); err != nil {
+			return nil, err
+		}
+		balances[code] = qty
+	}
+
+	query := `
+		SELECT l.code
+		FROM location_stock ls
+		INNER JOIN locations l ON l.id = ls.location_id
+		WHERE ls.product_id = ? AND l.type = ? AND ls.quantity <> 0
+	`
+	rows, err := tx.Query(query, productID, entities.LocationTypeWalmartShipNode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ship node stock for %s: %w", sku, err)
+	}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if _, reported := balances[code]; !reported {
+			balances[code] = 0
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(balances))
+	for code := range balances {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var recorded []entities.StockMovement
+	for _, code := range codes {
+		balance := balances[code]
+		movement := entities.StockMovement{
+			ProductID:    productID,
+			SKU:          sku,
+			StockType:    entities.StockTypeWalmart,
+			LocationCode: code,
+			Source:       entities.MovementSourceWalmartSync,
+			ReferenceID:  referenceID,
+		}
+
+		movement, err = appendMovement(tx, movement, &balance)
+		if err != nil {
+			return nil, err
+		}
+		if movement.ID != 0 {
+			recorded = append(recorded, movement)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit ship node stock: %w", err)
+	}
+
+	return recorded, nil
+}
+
+func ensureShipNode(tx *sql.Tx, code string) error {
+	query := `
+		INSERT IGNORE INTO locations (code, name, type, is_default, active, createdAt, updatedAt)
+		VALUES (?, ?, ?, 0, 1, NOW(), NOW())
+	`
+	_, err := tx.Exec(query, code, "Walmart "+code, entities.LocationTypeWalmartShipNode)
+	if err != nil {
+		return fmt.Errorf("failed to register ship node %s: %w", code, err)
+	}
+
+	// Location codes share one namespace, so a warehouse that already uses the
+	// code would silently receive the ship node's stock.
+	var locationType entities.LocationType
+	err = tx.QueryRow(`SELECT type FROM locations WHERE code = ?`, code).Scan(&locationType)
+	if err != nil {
+		return fmt.Errorf("failed to read ship node %s: %w", code, err)
+	}
+	if locationType != entities.LocationTypeWalmartShipNode {
+		return fmt.Errorf("ship node %s clashes with the %s location of the same code", code, locationType)
+	}
+	return nil
+}
+
+// appendMovement locks the product, computes the new running balance and writes
+// the ledger entry together with the cached balance column. When setTo is given
+// the movement quantity is derived from it. A zero-quantity or already recorded
+// movement is not written and is returned with a zero ID.
+func appendMovement(tx *sql.Tx, m entities.StockMovement, setTo *int) (entities.StockMovement, error) {
+	var err error
+	m.ProductID, m.SKU, err = lockProduct(tx, m.ProductID, m.SKU)
+	if err != nil {
+		return m, err
+	}
+
+	location, err := resolveLocation(tx, m.LocationID, m.LocationCode, m.StockType)
+	if err != nil {
+		return m, err
+	}
+	m.LocationID = location.ID
+	m.LocationCode = location.Code
+
+	if m.ReferenceID != "" {
+		var exists int
+		query := `SELECT 1 FROM stock_movements WHERE product_id = ? AND stock_type = ? AND location_id = ? AND source = ? AND reference_id = ? LIMIT 1`
+		err = tx.QueryRow(query, m.ProductID, m.StockType, m.LocationID, m.Source, m.ReferenceID).Scan(&exists)
+		if err == nil {
+			return m, nil
+		}
+		if err != sql.ErrNoRows {
+			return m, fmt.Errorf("failed to check movement reference for %s: %w", m.SKU, err)
+		}
+	}
+
+	previous, err := lastBalance(tx, m.ProductID, m.LocationID)
+	if err != nil {
+		return m, err
+	}
+
+	if setTo != nil {
+		m.Quantity = *setTo - previous
+	}
+	m.BalanceAfter = previous + m.Quantity
+	if m.BalanceAfter < 0 {
+		return m, errors.NewBadRequest(fmt.Sprintf("stock for sku %s at %s cannot go below zero (current %d)", m.SKU, m.LocationCode, previous))
+	}
+	if m.Quantity == 0 {
+		return m, nil
+	}
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = time.Now()
+	}
+
+	insertQuery := `
+		INSERT INTO stock_movements (product_id, seller_sku, stock_type, location_id, source, reason, quantity, balance_after, reference_id, note, createdAt)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	result, err := tx.Exec(insertQuery,
+		m.ProductID,
+		m.SKU,
+		m.StockType,
+		m.LocationID,
+		m.Source,
+		nullString(string(m.Reason)),
+		m.Quantity,
+		m.BalanceAfter,
+		nullString(m.ReferenceID),
+		nullString(m.Note),
+		m.CreatedAt,
+	)
+	if err != nil {
+		return m, fmt.Errorf("failed to record stock movement for %s: %w", m.SKU, err)
+	}
+
+	m.ID, err = result.LastInsertId()
+	if err != nil {
+		return m, err
+	}
+
+	if err := updateCachedBalances(tx, m, location.Type); err != nil {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// updateCachedBalances writes the new per-location balance and recomputes the
+// aggregate column the location contributes to.
+func updateCachedBalances(tx *sql.Tx, m entities.StockMovement, locationType entities.LocationType) error {
+	upsertQuery := `
+		INSERT INTO location_stock (product_id, location_id, quantity, updatedAt)
+		VALUES (?, ?, ?, NOW())
+		ON DUPLICATE KEY UPDATE quantity = VALUES(quantity), updatedAt = VALUES(updatedAt)
+	`
+	_, err := tx.Exec(upsertQuery, m.ProductID, m.LocationID, m.BalanceAfter)
+	if err != nil {
+		return fmt.Errorf("failed to update stock at %s for %s: %w", m.LocationCode, m.SKU, err)
+	}
+
+	total := `
+		SELECT COALESCE(SUM(ls.quantity), 0)
+		FROM location_stock ls
+		INNER JOIN locations l ON l.id = ls.location_id
+		WHERE ls.product_id = ? AND l.type = ?
+	`
+	switch m.StockType {
+	case entities.StockTypeWarehouse:
+		_, err = tx.Exec(`UPDATE products SET warehouse_stock = (`+total+`), updatedAt = NOW() WHERE id = ?`, m.ProductID, locationType, m.ProductID)
+	case entities.StockTypeWalmart:
+		_, err = tx.Exec(`UPDATE wmt_product_details SET available_to_sell_qty = (`+total+`), updatedAt = NOW() WHERE product_id = ?`, m.ProductID, locationType, m.ProductID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update %s balance for %s: %w", m.StockType, m.SKU, err)
+	}
+
+	return nil
+}
+
+// resolveLocation finds the location a movement applies to. Warehouse movements
+// without a location go to the default warehouse; Walmart movements must name
+// their ship node.
+func resolveLocation(tx *sql.Tx, id int64, code string, stockType entities.StockType) (entities.Location, error) {
+	query := `SELECT id, code, type FROM locations `
+	var row *sql.Row
+	switch {
+	case code != "":
+		row = tx.QueryRow(query+`WHERE code = ?`, code)
+	case id != 0:
+		row = tx.QueryRow(query+`WHERE id = ?`, id)
+	case stockType == entities.StockTypeWarehouse:
+		row = tx.QueryRow(query+`WHERE type = ? AND is_default = 1 LIMIT 1`, entities.LocationTypeWarehouse)
+	default:
+		return entities.Location{}, errors.NewBadRequest(fmt.Sprintf("a location is required for %s stock", stockType))
+	}
+
+	var location entities.Location
+	if err := row.Scan(&location.ID, &location.Code, &location.Type); err != nil {
+		if err == sql.ErrNoRows {
+			if code == "" && id == 0 {
+				return location, errors.NewResourceNotFound("no default warehouse location is configured")
+			}
+			return location, errors.NewResourceNotFound(fmt.Sprintf("location %s not found", code))
+		}
+		return location, fmt.Errorf("failed to resolve location: %w", err)
+	}
+
+	if location.Type.StockType() != stockType {
+		return location, errors.NewBadRequest(fmt.Sprintf("location %s does not hold %s stock", location.Code, stockType))
+	}
+
+	return location, nil
+}
+
+// ResolveLocation is resolveLocation for repositories that post ledger entries
+// through AppendMovements and need to know the location up front.
+func ResolveLocation(tx *sql.Tx, code string, stockType entities.StockType) (entities.Location, error) {
+	return resolveLocation(tx, 0, code, stockType)
+}
+
+func lockProduct(tx *sql.Tx, productID int64, sku string) (int64, string, error) {
+	var row *sql.Row
+	if productID != 0 {
+		row = tx.QueryRow(`SELECT id, seller_sku FROM products WHERE id = ? FOR UPDATE`, productID)
+	} else {
+		row = tx.QueryRow(`SELECT id, seller_sku FROM products WHERE seller_sku = ? LIMIT 1 FOR UPDATE`, sku)
+	}
+
+	var id int64
+	var sellerSku sql.NullString
+	if err := row.Scan(&id, &sellerSku); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, "", errors.NewResourceNotFound(fmt.Sprintf("product with sku %s not found", sku))
+		}
+		return 0, "", fmt.Errorf("failed to lock product %s: %w", sku, err)
+	}
+
+	return id, sellerSku.String, nil
+}
+
+func lastBalance(tx *sql.Tx, productID int64, locationID int64) (int, error) {
+	var balance int
+	query := `SELECT balance_after FROM stock_movements WHERE product_id = ? AND location_id = ? ORDER BY id DESC LIMIT 1`
+	err := tx.QueryRow(query, productID, locationID).Scan(&balance)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to read stock balance: %w", err)
+	}
+	return balance, nil
+}
+
+func (r *stockRepository) FindMovements(filter entities.MovementFilter) ([]entities.StockMovement, error) {
+	conditions := []string{"m.seller_sku = ?"}
+	args := []interface{}{filter.SKU}
+	if filter.StockType != "" {
+		conditions = append(conditions, "m.stock_type = ?")
+		args = append(args, filter.StockType)
+	}
+	if filter.Location != "" {
+		conditions = append(conditions, "l.code = ?")
+		args = append(args, filter.Location)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "m.createdAt >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "m.createdAt <= ?")
+		args = append(args, filter.To)
+	}
+	args = append(args, filter.Limit)
+
+	query := `
+		SELECT m.id, m.product_id, m.seller_sku, m.stock_type, m.location_id, l.code, m.source, m.reason,
+			m.quantity, m.balance_after, m.reference_id, m.note, m.createdAt
+		FROM stock_movements m
+		INNER JOIN locations l ON l.id = m.location_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY m.id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []entities.StockMovement{}
+	for rows.Next() {
+		var m entities.StockMovement
+		var reason, referenceID, note sql.NullString
+		err := rows.Scan(
+			&m.ID,
+			&m.ProductID,
+			&m.SKU,
+			&m.StockType,
+			&m.LocationID,
+			&m.LocationCode,
+			&m.Source,
+			&reason,
+			&m.Quantity,
+			&m.BalanceAfter,
+			&referenceID,
+			&note,
+			&m.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		m.Reason = entities.StockReason(reason.String)
+		m.ReferenceID = referenceID.String
+		m.Note = note.String
+		movements = append(movements, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+// FindStockLevels returns the cached warehouse and Walmart balances of every product.
+func (r *stockRepository) FindStockLevels() ([]entities.StockLevel, error) {
+	query := `
+		SELECT
+			p.id,
+			p.seller_sku,
+			p.product_name,
+			p.warehouse_stock,
+			d.available_to_sell_qty
+		FROM products p
+		INNER JOIN wmt_product_details d ON p.id = d.product_id
+		ORDER BY p.seller_sku
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []entities.StockLevel
+	for rows.Next() {
+		var level entities.StockLevel
+		var sku, productName sql.NullString
+		var warehouseStock sql.NullInt32
+		err := rows.Scan(
+			&level.ProductID,
+			&sku,
+			&productName,
+			&warehouseStock,
+			&level.WalmartAvailable,
+		)
+		if err != nil {
+			return nil, err
+		}
+		level.SKU = sku.String
+		level.ProductName = productName.String
+		level.WarehouseStock = int(warehouseStock.Int32)
+		levels = append(levels, level)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return levels, nil
+}
+
+// BalanceAt rebuilds the per-location and aggregate balances of a SKU from the
+// ledger as of the given time.
+func (r *stockRepository) BalanceAt(sku string, at time.Time) (*entities.StockSnapshot, error) {
+	var exists int
+	err := r.db.QueryRow(`SELECT 1 FROM products WHERE seller_sku = ? LIMIT 1`, sku).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	query := `
+		SELECT l.id, l.code, l.name, l.type, SUM(m.quantity)
+		FROM stock_movements m
+		INNER JOIN locations l ON l.id = m.location_id
+		WHERE m.seller_sku = ? AND m.createdAt <= ?
+		GROUP BY l.id, l.code, l.name, l.type
+		ORDER BY l.type, l.code
+	`
+
+	rows, err := r.db.Query(query, sku, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshot := entities.StockSnapshot{SKU: sku, At: at, Locations: []entities.LocationStock{}}
+	for rows.Next() {
+		ls := entities.LocationStock{SKU: sku, UpdatedAt: at}
+		err := rows.Scan(&ls.LocationID, &ls.LocationCode, &ls.LocationName, &ls.LocationType, &ls.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		if ls.LocationType.StockType() == entities.StockTypeWalmart {
+			snapshot.WalmartAvailable += ls.Quantity
+		} else {
+			snapshot.WarehouseStock += ls.Quantity
+		}
+		snapshot.Locations = append(snapshot.Locations, ls)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// FindStockBySKU returns the cached balance of a SKU at every location that has
+// held it, together with the aggregate totals.
+func (r *stockRepository) FindStockBySKU(sku string) (*entities.StockByLocation, error) {
+	var productID int64
+	err := r.db.QueryRow(`SELECT id FROM products WHERE seller_sku = ? LIMIT 1`, sku).Scan(&productID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	locations, err := r.findLocationStock(`ls.product_id = ?`, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := entities.StockByLocation{SKU: sku, Locations: locations}
+	for _, ls := range locations {
+		if ls.LocationType.StockType() == entities.StockTypeWalmart {
+			stock.WalmartAvailable += ls.Quantity
+		} else {
+			stock.WarehouseStock += ls.Quantity
+		}
+	}
+
+	return &stock, nil
+}
+
+func (r *stockRepository) FindStockByLocation(locationID int64) ([]entities.LocationStock, error) {
+	return r.findLocationStock(`ls.location_id = ? AND ls.quantity <> 0`, locationID)
+}
+
+func (r *stockRepository) findLocationStock(condition string, arg interface{}) ([]entities.LocationStock, error) {
+	query := `
+		SELECT l.id, l.code, l.name, l.type, p.seller_sku, ls.quantity, ls.updatedAt
+		FROM location_stock ls
+		INNER JOIN locations l ON l.id = ls.location_id
+		INNER JOIN products p ON p.id = ls.product_id
+		WHERE ` + condition + `
+		ORDER BY l.type, l.code, p.seller_sku
+	`
+
+	rows, err := r.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stock := []entities.LocationStock{}
+	for rows.Next() {
+		var ls entities.LocationStock
+		var sku sql.NullString
+		err := rows.Scan(
+			&ls.LocationID,
+			&ls.LocationCode,
+			&ls.LocationName,
+			&ls.LocationType,
+			&sku,
+			&ls.Quantity,
+			&ls.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		ls.SKU = sku.String
+		stock = append(stock, ls)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// CheckLedger compares the ledger totals with the cached balance columns.
+func (r *stockRepository) CheckLedger(sku string) (*entities.LedgerCheck, error) {
+	query := `
+		SELECT
+			COALESCE((SELECT SUM(m.quantity) FROM stock_movements m WHERE m.product_id = p.id AND m.stock_type = 'warehouse'), 0),
+			COALESCE(p.warehouse_stock, 0),
+			COALESCE((SELECT SUM(m.quantity) FROM stock_movements m WHERE m.product_id = p.id AND m.stock_type = 'walmart'), 0),
+			COALESCE(d.available_to_sell_qty, 0)
+		FROM products p
+		LEFT JOIN wmt_product_details d ON p.id = d.product_id
+		WHERE p.seller_sku = ?
+		LIMIT 1
+	`
+
+	check := entities.LedgerCheck{SKU: sku}
+	err := r.db.QueryRow(query, sku).Scan(
+		&check.LedgerWarehouseStock,
+		&check.RecordedWarehouseStock,
+		&check.LedgerWalmartQty,
+		&check.RecordedWalmartQty,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	check.InSync = check.LedgerWarehouseStock == check.RecordedWarehouseStock &&
+		check.LedgerWalmartQty == check.RecordedWalmartQty
+
+	return &check, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
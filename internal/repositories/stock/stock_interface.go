@@ -0,0 +1,19 @@
+package stock
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type StockRepository interface {
+	ApplyChanges(changes []entities.StockChange) ([]entities.StockMovement, error)
+	RecordMovements(movements []entities.StockMovement) ([]entities.StockMovement, error)
+	RecordBalance(movement entities.StockMovement, balance int) (*entities.StockMovement, error)
+	SyncShipNodes(productID int64, sku string, referenceID string, nodes map[string]int) ([]entities.StockMovement, error)
+	FindMovements(filter entities.MovementFilter) ([]entities.StockMovement, error)
+	FindStockLevels() ([]entities.StockLevel, error)
+	FindStockBySKU(sku string) (*entities.StockByLocation, error)
+	FindStockByLocation(locationID int64) ([]entities.LocationStock, error)
+	BalanceAt(sku string, at time.Time) (*entities.StockSnapshot, error)
+	CheckLedger(sku string) (*entities.LedgerCheck, error)
+}
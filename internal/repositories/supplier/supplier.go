@@ -0,0 +1,186 @@
+package supplier
+
+import (
+	"database/sql"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type supplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) *supplierRepository {
+	return &supplierRepository{
+		db: db,
+	}
+}
+
+const selectSupplier = `
+	SELECT id, name, email, phone, lead_time_days, min_order_qty, active, createdAt, updatedAt
+	FROM suppliers
+`
+
+func (r *supplierRepository) FindAll() ([]entities.Supplier, error) {
+	rows, err := r.db.Query(selectSupplier + ` ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suppliers := []entities.Supplier{}
+	for rows.Next() {
+		supplier, err := scanSupplier(rows)
+		if err != nil {
+			return nil, err
+		}
+		suppliers = append(suppliers, supplier)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suppliers, nil
+}
+
+func (r *supplierRepository) FindByID(id int64) (*entities.Supplier, error) {
+	supplier, err := scanSupplier(r.db.QueryRow(selectSupplier+` WHERE id = ?`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &supplier, nil
+}
+
+func (r *supplierRepository) Create(supplier entities.Supplier) (int64, error) {
+	query := `
+		INSERT INTO suppliers (name, email, phone, lead_time_days, min_order_qty, active, createdAt, updatedAt)
+		VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+	`
+	result, err := r.db.Exec(query,
+		supplier.Name,
+		supplier.Email,
+		supplier.Phone,
+		supplier.LeadTimeDays,
+		supplier.MinOrderQty,
+		supplier.Active,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (r *supplierRepository) Update(supplier entities.Supplier) error {
+	query := `
+		UPDATE suppliers
+		SET
+			name = ?,
+			email = ?,
+			phone = ?,
+			lead_time_days = ?,
+			min_order_qty = ?,
+			active = ?,
+			updatedAt = NOW()
+		WHERE id = ?
+	`
+	_, err := r.db.Exec(query,
+		supplier.Name,
+		supplier.Email,
+		supplier.Phone,
+		supplier.LeadTimeDays,
+		supplier.MinOrderQty,
+		supplier.Active,
+		supplier.ID,
+	)
+
+	return err
+}
+
+// FindSupplyItems returns the SKUs bought from a supplier, or from any active
+// supplier when supplierID is zero, with lead time and MOQ already resolved
+// against the supplier defaults.
+func (r *supplierRepository) FindSupplyItems(supplierID int64) ([]entities.SupplyItem, error) {
+	query := `
+		SELECT
+			p.id,
+			p.seller_sku,
+			p.product_name,
+			s.id,
+			p.supplier_item_number,
+			COALESCE(p.product_cost, 0),
+			COALESCE(p.lead_time_days, s.lead_time_days),
+			COALESCE(p.min_order_qty, s.min_order_qty),
+			COALESCE(p.warehouse_stock, 0),
+			COALESCE(d.available_to_sell_qty, 0)
+		FROM products p
+		INNER JOIN suppliers s ON s.id = p.supplier_id
+		LEFT JOIN wmt_product_details d ON d.product_id = p.id
+		WHERE s.active = 1 AND (? = 0 OR s.id = ?)
+		ORDER BY s.id, p.seller_sku
+	`
+
+	rows, err := r.db.Query(query, supplierID, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []entities.SupplyItem
+	for rows.Next() {
+		var item entities.SupplyItem
+		var sku, productName, supplierItemNumber sql.NullString
+		err := rows.Scan(
+			&item.ProductID,
+			&sku,
+			&productName,
+			&item.SupplierID,
+			&supplierItemNumber,
+			&item.ProductCost,
+			&item.LeadTimeDays,
+			&item.MinOrderQty,
+			&item.WarehouseStock,
+			&item.WalmartAvailable,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.SKU = sku.String
+		item.ProductName = productName.String
+		item.SupplierItemNumber = supplierItemNumber.String
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSupplier(row scanner) (entities.Supplier, error) {
+	var supplier entities.Supplier
+	var email, phone sql.NullString
+	err := row.Scan(
+		&supplier.ID,
+		&supplier.Name,
+		&email,
+		&phone,
+		&supplier.LeadTimeDays,
+		&supplier.MinOrderQty,
+		&supplier.Active,
+		&supplier.CreatedAt,
+		&supplier.UpdatedAt,
+	)
+	supplier.Email = email.String
+	supplier.Phone = phone.String
+	return supplier, err
+}
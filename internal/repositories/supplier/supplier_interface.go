@@ -0,0 +1,11 @@
+package supplier
+
+import "walmart-inventory-manager/internal/entities"
+
+type SupplierRepository interface {
+	FindAll() ([]entities.Supplier, error)
+	FindByID(id int64) (*entities.Supplier, error)
+	Create(supplier entities.Supplier) (int64, error)
+	Update(supplier entities.Supplier) error
+	FindSupplyItems(supplierID int64) ([]entities.SupplyItem, error)
+}
@@ -0,0 +1,313 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/notifier"
+	"walmart-inventory-manager/internal/repositories/alert"
+	"walmart-inventory-manager/internal/service/forecast"
+)
+
+const (
+	notifyTimeout      = 15 * time.Second
+	defaultAlertsLimit = 100
+	maxAlertsLimit     = 1000
+)
+
+type AlertDefault struct {
+	rp        alert.AlertRepository
+	forecast  forecast.ForecastService
+	notifiers map[string]notifier.Notifier
+	logger    *slog.Logger
+}
+
+func NewAlertDefault(rp alert.AlertRepository, forecast forecast.ForecastService, logger *slog.Logger, notifiers ...notifier.Notifier) *AlertDefault {
+	byName := make(map[string]notifier.Notifier, len(notifiers))
+	for _, n := range notifiers {
+		byName[n.Name()] = n
+	}
+	return &AlertDefault{rp: rp, forecast: forecast, notifiers: byName, logger: logger}
+}
+
+type alertKey struct {
+	ruleID    int64
+	productID int64
+}
+
+// Evaluate checks every active rule against the current product state. A rule
+// that starts matching raises one alert and notifies its channels; the alert
+// stays open (and silent) until the condition clears, when it is resolved. An
+// open alert no channel accepted yet is sent again on every run until one does.
+func (s *AlertDefault) Evaluate(runID string) (*entities.AlertEvaluation, error) {
+	rules, err := s.rp.FindRules(true)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects, err := s.rp.FindSubjects()
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts, err := s.forecast.ForecastAll()
+	if err != nil {
+		return nil, err
+	}
+	velocity := make(map[string]float64, len(forecasts))
+	for _, f := range forecasts {
+		velocity[f.SKU] = f.ForecastDailyUnits
+	}
+	for i := range subjects {
+		subjects[i].DailyVelocity = velocity[subjects[i].SKU]
+	}
+
+	openAlerts, err := s.rp.FindOpenAlerts()
+	if err != nil {
+		return nil, err
+	}
+
+	open := make(map[alertKey]entities.Alert, len(openAlerts))
+	for _, a := range openAlerts {
+		open[alertKey{a.RuleID, a.ProductID}] = a
+	}
+
+	// SKU-specific rules take precedence over global rules of the same type.
+	overridden := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.SKU != "" {
+			overridden[rule.SKU+"|"+string(rule.Type)] = true
+		}
+	}
+
+	result := &entities.AlertEvaluation{RunID: runID, Evaluated: len(subjects)}
+	for _, subject := range subjects {
+		for _, rule := range rules {
+			if rule.SKU != "" && rule.SKU != subject.SKU {
+				continue
+			}
+			if rule.SKU == "" && overridden[subject.SKU+"|"+string(rule.Type)] {
+				continue
+			}
+
+			key := alertKey{rule.ID, subject.ProductID}
+			existing, isOpen := open[key]
+			delete(open, key)
+
+			message, triggered := evaluateRule(rule, subject)
+			switch {
+			case triggered && !isOpen:
+				if err := s.raise(rule, subject, message); err != nil {
+					s.logger.Error("error raising alert", slog.String(logging.KeySKU, subject.SKU), logging.Err(err))
+					continue
+				}
+				result.Raised++
+			case triggered && isOpen && existing.NotifiedAt == nil:
+				if s.notify(rule.Channels, existing) {
+					if err := s.rp.MarkNotified(existing.ID); err != nil {
+						s.logger.Error("error marking alert notified", slog.Int64("alert_id", existing.ID), logging.Err(err))
+						continue
+					}
+					result.Notified++
+				}
+			case !triggered && isOpen:
+				if err := s.rp.ResolveAlert(existing.ID); err != nil {
+					s.logger.Error("error resolving alert", slog.Int64("alert_id", existing.ID), logging.Err(err))
+					continue
+				}
+				result.Resolved++
+			}
+		}
+	}
+
+	// Whatever is still open no longer has a matching rule/product pair.
+	for _, a := range open {
+		if err := s.rp.ResolveAlert(a.ID); err != nil {
+			s.logger.Error("error resolving alert", slog.Int64("alert_id", a.ID), logging.Err(err))
+			continue
+		}
+		result.Resolved++
+	}
+
+	return result, nil
+}
+
+func evaluateRule(rule entities.AlertRule, subject entities.AlertSubject) (string, bool) {
+	quantity := subject.Quantity(rule.StockType)
+	name := subject.SKU
+	if subject.ProductName != "" {
+		name = fmt.Sprintf("%s (%s)", subject.SKU, subject.ProductName)
+	}
+
+	switch rule.Type {
+	case entities.AlertRuleThreshold:
+		if quantity <= rule.Threshold {
+			return fmt.Sprintf("%s %s stock is %d, at or below the threshold of %d", name, rule.StockType, quantity, rule.Threshold), true
+		}
+	case entities.AlertRuleDaysOfCover:
+		if subject.DailyVelocity > 0 {
+			cover := float64(quantity) / subject.DailyVelocity
+			if cover < rule.DaysOfCover {
+				return fmt.Sprintf("%s %s stock of %d covers %.1f days of sales, below the target of %.1f days", name, rule.StockType, quantity, cover, rule.DaysOfCover), true
+			}
+		}
+	case entities.AlertRuleStatusChange:
+		if subject.Availability == "Out_of_stock" || subject.ListingStatusID == entities.ListingStatusOutOfStock {
+			return fmt.Sprintf("%s is out of stock on Walmart", name), true
+		}
+	}
+
+	return "", false
+}
+
+func (s *AlertDefault) raise(rule entities.AlertRule, subject entities.AlertSubject, message string) error {
+	a := entities.Alert{
+		RuleID:    rule.ID,
+		ProductID: subject.ProductID,
+		SKU:       subject.SKU,
+		Type:      rule.Type,
+		Message:   message,
+		Quantity:  subject.Quantity(rule.StockType),
+		Status:    entities.AlertStatusOpen,
+		CreatedAt: time.Now(),
+	}
+
+	var err error
+	a.ID, err = s.rp.CreateAlert(a)
+	if err != nil {
+		return err
+	}
+
+	if s.notify(rule.Channels, a) {
+		return s.rp.MarkNotified(a.ID)
+	}
+	return nil
+}
+
+// notify sends the alert to the rule's channels, or to every channel when the
+// rule names none. It reports whether at least one channel accepted it.
+func (s *AlertDefault) notify(channels []string, a entities.Alert) bool {
+	if len(channels) == 0 {
+		channels = s.Channels()
+	}
+
+	delivered := false
+	for _, name := range channels {
+		n, ok := s.notifiers[name]
+		if !ok {
+			s.logger.Warn("unknown notification channel", slog.String("channel", name), slog.Int64("alert_id", a.ID))
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		err := n.Notify(ctx, a)
+		cancel()
+		if err != nil {
+			s.logger.Error("error sending alert notification", slog.String("channel", name), slog.Int64("alert_id", a.ID), logging.Err(err))
+			continue
+		}
+		delivered = true
+	}
+
+	return delivered
+}
+
+func (s *AlertDefault) Rules() ([]entities.AlertRule, error) {
+	return s.rp.FindRules(false)
+}
+
+func (s *AlertDefault) CreateRule(rule entities.AlertRule) (*entities.AlertRule, error) {
+	if !rule.Type.Valid() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid rule type %q: must be threshold, days_of_cover or status_change", rule.Type))
+	}
+	if rule.StockType == "" {
+		rule.StockType = entities.StockTypeWalmart
+	}
+	if !rule.StockType.Valid() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid stock type %q", rule.StockType))
+	}
+	if rule.Threshold < 0 {
+		return nil, errors.NewBadRequest("threshold cannot be negative")
+	}
+	if rule.Type == entities.AlertRuleDaysOfCover && rule.DaysOfCover <= 0 {
+		return nil, errors.NewBadRequest("daysOfCover must be greater than zero")
+	}
+	for _, name := range rule.Channels {
+		if _, ok := s.notifiers[name]; !ok {
+			return nil, errors.NewBadRequest(fmt.Sprintf("unknown channel %q", name))
+		}
+	}
+
+	rule.Active = true
+	id, err := s.rp.CreateRule(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.ID = id
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = rule.CreatedAt
+	if rule.Channels == nil {
+		rule.Channels = []string{}
+	}
+	return &rule, nil
+}
+
+func (s *AlertDefault) DeleteRule(id int64) error {
+	deleted, err := s.rp.DeleteRule(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.NewResourceNotFound(fmt.Sprintf("alert rule %d not found", id))
+	}
+	return nil
+}
+
+func (s *AlertDefault) Alerts(status entities.AlertStatus, limit int) ([]entities.Alert, error) {
+	if status != "" && status != entities.AlertStatusOpen && status != entities.AlertStatusResolved {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid status %q", status))
+	}
+	if limit <= 0 {
+		limit = defaultAlertsLimit
+	}
+	if limit > maxAlertsLimit {
+		limit = maxAlertsLimit
+	}
+
+	return s.rp.FindAlerts(status, limit)
+}
+
+func (s *AlertDefault) Channels() []string {
+	names := make([]string, 0, len(s.notifiers))
+	for name := range s.notifiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TestChannel sends a synthetic alert through one channel so its configuration
+// can be checked without waiting for a real stock event.
+func (s *AlertDefault) TestChannel(name string) error {
+	n, ok := s.notifiers[name]
+	if !ok {
+		return errors.NewResourceNotFound(fmt.Sprintf("channel %s is not configured", name))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	return n.Notify(ctx, entities.Alert{
+		SKU:       "TEST-SKU",
+		Type:      entities.AlertRuleThreshold,
+		Message:   "Test alert from walmart-inventory-manager",
+		Status:    entities.AlertStatusOpen,
+		CreatedAt: time.Now(),
+	})
+}
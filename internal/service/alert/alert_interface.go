@@ -0,0 +1,13 @@
+package alert
+
+import "walmart-inventory-manager/internal/entities"
+
+type AlertService interface {
+	Evaluate(runID string) (*entities.AlertEvaluation, error)
+	Rules() ([]entities.AlertRule, error)
+	CreateRule(rule entities.AlertRule) (*entities.AlertRule, error)
+	DeleteRule(id int64) error
+	Alerts(status entities.AlertStatus, limit int) ([]entities.Alert, error)
+	Channels() []string
+	TestChannel(name string) error
+}
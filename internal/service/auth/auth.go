@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"walmart-inventory-manager/internal/auth"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/repositories/apikey"
+)
+
+type AuthDefault struct {
+	rp     apikey.APIKeyRepository
+	jwt    entities.JWTPolicy
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+func NewAuthDefault(rp apikey.APIKeyRepository, jwt entities.JWTPolicy, logger *slog.Logger) *AuthDefault {
+	return &AuthDefault{rp: rp, jwt: jwt, now: time.Now, logger: logger}
+}
+
+// Authenticate resolves an API key or a JWT to the caller it belongs to. The
+// error returned for bad credentials is deliberately vague; the reason is
+// only logged.
+func (s *AuthDefault) Authenticate(credential string) (*entities.Principal, error) {
+	if credential == "" {
+		return nil, errors.NewUnauthorized("authentication required")
+	}
+
+	if auth.IsAPIKey(credential) {
+		return s.authenticateKey(credential)
+	}
+	return s.authenticateToken(credential)
+}
+
+func (s *AuthDefault) authenticateKey(credential string) (*entities.Principal, error) {
+	key, err := s.rp.FindByHash(auth.HashAPIKey(credential))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || !key.Active() {
+		return nil, errors.NewUnauthorized("invalid credentials")
+	}
+
+	if err := s.rp.Touch(key.ID); err != nil {
+		s.logger.Warn("error recording use of API key", slog.Int64("key_id", key.ID), logging.Err(err))
+	}
+
+	return &entities.Principal{
+		Subject: key.Name,
+		Method:  entities.AuthMethodAPIKey,
+		Role:    key.Role,
+		KeyID:   key.ID,
+	}, nil
+}
+
+func (s *AuthDefault) authenticateToken(credential string) (*entities.Principal, error) {
+	if len(s.jwt.Secret) == 0 {
+		return nil, errors.NewUnauthorized("invalid credentials")
+	}
+
+	claims, err := auth.ValidateJWT(credential, s.jwt, s.now())
+	if err != nil {
+		s.logger.Info("rejected JWT", logging.Err(err))
+		return nil, errors.NewUnauthorized("invalid credentials")
+	}
+
+	// Tokens without a role we know get the least access rather than none.
+	role := entities.Role(claims.Role)
+	if !role.Valid() {
+		role = entities.RoleViewer
+	}
+
+	return &entities.Principal{
+		Subject: claims.Subject,
+		Method:  entities.AuthMethodJWT,
+		Role:    role,
+	}, nil
+}
+
+// Authorize checks that the caller's role grants the permission a route
+// requires.
+func (s *AuthDefault) Authorize(principal *entities.Principal, permission string) error {
+	if principal == nil {
+		return errors.NewUnauthorized("authentication required")
+	}
+	if permission == "" {
+		return errors.NewForbidden("route does not declare a permission")
+	}
+	if !principal.Role.Allows(permission) {
+		return errors.NewForbidden(fmt.Sprintf("role %q does not have the %q permission", principal.Role, permission))
+	}
+	return nil
+}
+
+func (s *AuthDefault) Keys() ([]entities.APIKey, error) {
+	return s.rp.FindAll()
+}
+
+// CreateKey stores a new key and returns it with the plain key, which is not
+// kept anywhere and cannot be shown again.
+func (s *AuthDefault) CreateKey(name string, role entities.Role) (*entities.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", errors.NewBadRequest("name is required")
+	}
+	if role == "" {
+		role = entities.RoleViewer
+	}
+	if !role.Valid() {
+		return nil, "", invalidRole(role)
+	}
+
+	plain, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	id, err := s.rp.Create(entities.APIKey{
+		Name:   name,
+		Prefix: prefix,
+		Role:   role,
+		Hash:   auth.HashAPIKey(plain),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	key, err := s.findKey(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return key, plain, nil
+}
+
+func (s *AuthDefault) SetKeyRole(id int64, role entities.Role) (*entities.APIKey, error) {
+	if !role.Valid() {
+		return nil, invalidRole(role)
+	}
+	if _, err := s.findKey(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.rp.SetRole(id, role); err != nil {
+		return nil, err
+	}
+
+	return s.findKey(id)
+}
+
+func (s *AuthDefault) RevokeKey(id int64) (*entities.APIKey, error) {
+	if _, err := s.findKey(id); err != nil {
+		return nil, err
+	}
+
+	if err := s.rp.Revoke(id); err != nil {
+		return nil, err
+	}
+
+	return s.findKey(id)
+}
+
+func (s *AuthDefault) findKey(id int64) (*entities.APIKey, error) {
+	key, err := s.rp.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("API key %d not found", id))
+	}
+
+	return key, nil
+}
+
+func invalidRole(role entities.Role) error {
+	return errors.NewBadRequest(fmt.Sprintf("invalid role %q: must be %s, %s or %s", role, entities.RoleViewer, entities.RoleOperator, entities.RoleAdmin))
+}
@@ -0,0 +1,12 @@
+package auth
+
+import "walmart-inventory-manager/internal/entities"
+
+type AuthService interface {
+	Authenticate(credential string) (*entities.Principal, error)
+	Authorize(principal *entities.Principal, permission string) error
+	Keys() ([]entities.APIKey, error)
+	CreateKey(name string, role entities.Role) (*entities.APIKey, string, error)
+	SetKeyRole(id int64, role entities.Role) (*entities.APIKey, error)
+	RevokeKey(id int64) (*entities.APIKey, error)
+}
@@ -0,0 +1,172 @@
+package forecast
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/forecast"
+	"walmart-inventory-manager/internal/repositories/sales"
+	"walmart-inventory-manager/internal/repositories/stock"
+)
+
+// historyDays is the longest velocity window and the length of the series fed to the smoother.
+const historyDays = 90
+
+// rates are the sales-derived figures of one SKU. They only change when new
+// sales are stored or the day rolls over, so they are kept between requests.
+type rates struct {
+	velocity7  float64
+	velocity30 float64
+	velocity90 float64
+	daily      float64
+}
+
+type ForecastDefault struct {
+	sales    sales.SalesRepository
+	stock    stock.StockRepository
+	alpha    float64
+	location *time.Location
+
+	mu sync.Mutex
+	// day is the sales day the cached rates were computed for; the zero
+	// time means there is nothing cached.
+	day   time.Time
+	rates map[string]rates
+}
+
+// NewForecastDefault builds the forecast service. Sales days are counted in
+// the location, the same time zone the sales sync stores them in.
+func NewForecastDefault(sales sales.SalesRepository, stock stock.StockRepository, alpha float64, location *time.Location) *ForecastDefault {
+	return &ForecastDefault{sales: sales, stock: stock, alpha: alpha, location: location}
+}
+
+func (s *ForecastDefault) ForecastAll() ([]entities.Forecast, error) {
+	levels, err := s.stock.FindStockLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bySKU, err := s.salesRates(now)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]entities.Forecast, 0, len(levels))
+	for _, level := range levels {
+		forecasts = append(forecasts, build(level, bySKU[level.SKU], now, s.location))
+	}
+
+	sort.Slice(forecasts, func(i, j int) bool {
+		return forecasts[i].SKU < forecasts[j].SKU
+	})
+	return forecasts, nil
+}
+
+// Refresh drops the cached sales rates so the next forecast reads the sales
+// stored since. It runs after every sales sync.
+func (s *ForecastDefault) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.day = time.Time{}
+	s.rates = nil
+}
+
+func (s *ForecastDefault) ForecastSKU(sku string) (*entities.Forecast, error) {
+	forecasts, err := s.ForecastAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range forecasts {
+		if forecasts[i].SKU == sku {
+			return &forecasts[i], nil
+		}
+	}
+
+	return nil, errors.NewResourceNotFound(fmt.Sprintf("product with sku %s not found", sku))
+}
+
+func build(level entities.StockLevel, r rates, now time.Time, loc *time.Location) entities.Forecast {
+	return entities.Forecast{
+		SKU:                level.SKU,
+		Velocity7:          r.velocity7,
+		Velocity30:         r.velocity30,
+		Velocity90:         r.velocity90,
+		ForecastDailyUnits: forecast.Round(r.daily, 2),
+		WalmartAvailable:   level.WalmartAvailable,
+		WarehouseStock:     level.WarehouseStock,
+		DaysOfCover:        forecast.DaysOfCover(level.WalmartAvailable, r.daily),
+		TotalDaysOfCover:   forecast.DaysOfCover(level.WalmartAvailable+level.WarehouseStock, r.daily),
+		ProjectedStockout:  forecast.StockoutDate(now, level.WalmartAvailable, r.daily, loc),
+		GeneratedAt:        now,
+	}
+}
+
+// salesRates returns the rates of every SKU with sales, computing them from
+// the sales history once per sales day.
+func (s *ForecastDefault) salesRates(now time.Time) (map[string]rates, error) {
+	today := s.salesDay(now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rates != nil && s.day.Equal(today) {
+		return s.rates, nil
+	}
+
+	series, err := s.dailySeries(today)
+	if err != nil {
+		return nil, err
+	}
+
+	bySKU := make(map[string]rates, len(series))
+	for sku, days := range series {
+		bySKU[sku] = rates{
+			velocity7:  forecast.Round(forecast.Velocity(days, 7), 2),
+			velocity30: forecast.Round(forecast.Velocity(days, 30), 2),
+			velocity90: forecast.Round(forecast.Velocity(days, 90), 2),
+			daily:      forecast.Smooth(days, s.alpha),
+		}
+	}
+
+	s.day, s.rates = today, bySKU
+	return bySKU, nil
+}
+
+// salesDay is the calendar day of t in the sales time zone. Stored sales days
+// carry only a date, so it is returned at midnight UTC like they are read.
+func (s *ForecastDefault) salesDay(t time.Time) time.Time {
+	t = t.In(s.location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// dailySeries loads the last historyDays days of sales as one zero-filled
+// series per SKU, oldest day first and ending the day before today.
+func (s *ForecastDefault) dailySeries(today time.Time) (map[string][]int, error) {
+	start := today.AddDate(0, 0, -historyDays)
+
+	sales, err := s.sales.FindDailySales(start)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make(map[string][]int)
+	for _, sale := range sales {
+		day := time.Date(sale.Date.Year(), sale.Date.Month(), sale.Date.Day(), 0, 0, 0, 0, time.UTC)
+		index := int(day.Sub(start).Hours() / 24)
+		if index < 0 || index >= historyDays {
+			continue
+		}
+		if series[sale.SKU] == nil {
+			series[sale.SKU] = make([]int, historyDays)
+		}
+		series[sale.SKU][index] += sale.Units
+	}
+
+	return series, nil
+}
@@ -0,0 +1,9 @@
+package forecast
+
+import "walmart-inventory-manager/internal/entities"
+
+type ForecastService interface {
+	ForecastAll() ([]entities.Forecast, error)
+	ForecastSKU(sku string) (*entities.Forecast, error)
+	Refresh()
+}
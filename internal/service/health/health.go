@@ -0,0 +1,237 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/repositories/jobrun"
+)
+
+const defaultCheckTimeout = 2 * time.Second
+
+type HealthDefault struct {
+	rp        jobrun.JobRunRepository
+	db        Pinger
+	tokens    TokenSource
+	policy    entities.ReadinessPolicy
+	logger    *slog.Logger
+	startedAt time.Time
+	now       func() time.Time
+
+	// mu guards nextRuns.
+	mu       sync.Mutex
+	nextRuns map[string]time.Time
+}
+
+func NewHealthDefault(rp jobrun.JobRunRepository, db Pinger, tokens TokenSource, policy entities.ReadinessPolicy, logger *slog.Logger) *HealthDefault {
+	if policy.Timeout <= 0 {
+		policy.Timeout = defaultCheckTimeout
+	}
+
+	return &HealthDefault{
+		rp:        rp,
+		db:        db,
+		tokens:    tokens,
+		policy:    policy,
+		logger:    logger,
+		startedAt: time.Now(),
+		now:       time.Now,
+		nextRuns:  make(map[string]time.Time),
+	}
+}
+
+// check returns what a readiness probe may show about a failure; the
+// underlying error is logged, not returned, since probes are unauthenticated.
+type check func(ctx context.Context) (message string, ok bool)
+
+// Ready runs the readiness checks in parallel. The service is ready when the
+// database answers, a Walmart token can be had and the catalog was synced
+// recently enough.
+func (s *HealthDefault) Ready() entities.Readiness {
+	ctx, cancel := context.WithTimeout(context.Background(), s.policy.Timeout)
+	defer cancel()
+
+	checks := []struct {
+		name string
+		run  check
+	}{
+		{"database", s.checkDatabase},
+		{"walmart_token", s.checkToken},
+		{"catalog_sync", s.checkCatalogSync},
+	}
+
+	readiness := entities.Readiness{
+		Status:    entities.HealthOK,
+		Checks:    make([]entities.HealthCheck, len(checks)),
+		CheckedAt: s.now(),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readiness.Checks[i] = runCheck(ctx, c.name, c.run)
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range readiness.Checks {
+		if c.Status != entities.HealthOK {
+			readiness.Status = entities.HealthFail
+		}
+	}
+
+	return readiness
+}
+
+// runCheck gives up on a check once the context is done; the check keeps
+// running in the background and its result is dropped.
+func runCheck(ctx context.Context, name string, run check) entities.HealthCheck {
+	type outcome struct {
+		message string
+		ok      bool
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		message, ok := run(ctx)
+		done <- outcome{message, ok}
+	}()
+
+	result := entities.HealthCheck{Name: name, Status: entities.HealthFail}
+	select {
+	case o := <-done:
+		result.Message = o.message
+		if o.ok {
+			result.Status = entities.HealthOK
+		}
+	case <-ctx.Done():
+		result.Message = "timed out"
+	}
+	return result
+}
+
+func (s *HealthDefault) checkDatabase(ctx context.Context) (string, bool) {
+	if err := s.db.PingContext(ctx); err != nil {
+		s.logger.Warn("readiness: database ping failed", logging.Err(err))
+		return "database unreachable", false
+	}
+	return "", true
+}
+
+// checkToken looks at the token the Walmart client has cached and fails only
+// when the last attempt to get one failed. An expired token is renewed by the
+// next Walmart call, so it is not a failure on its own.
+func (s *HealthDefault) checkToken(ctx context.Context) (string, bool) {
+	state := s.tokens.TokenState()
+	if state.LastError != nil {
+		s.logger.Warn("readiness: the last Walmart token request failed", logging.Err(state.LastError))
+		return "the last Walmart access token request failed", false
+	}
+	if state.ExpiresAt.IsZero() {
+		return "no token requested yet", true
+	}
+	if remaining := state.ExpiresAt.Sub(s.now()); remaining > 0 {
+		return fmt.Sprintf("token valid for %ds", int64(remaining.Seconds())), true
+	}
+	return "token expired, renewed on the next Walmart call", true
+}
+
+// checkCatalogSync fails once the last successful catalog sync is too old.
+// Until the first sync succeeds the service counts from when it started, so
+// a fresh deployment is not held back by a sync scheduled hours away.
+func (s *HealthDefault) checkCatalogSync(ctx context.Context) (string, bool) {
+	if s.policy.MaxSyncAge <= 0 {
+		return "check disabled", true
+	}
+
+	run, err := s.rp.LastSuccess(entities.JobCatalogSync)
+	if err != nil {
+		s.logger.Warn("readiness: loading the last catalog sync failed", logging.Err(err))
+		return "cannot load the last catalog sync", false
+	}
+
+	now := s.now()
+	if run == nil {
+		age := now.Sub(s.startedAt)
+		if age > s.policy.MaxSyncAge {
+			return fmt.Sprintf("no successful catalog sync since startup %s ago", age.Round(time.Minute)), false
+		}
+		return "no successful catalog sync yet", true
+	}
+
+	age := now.Sub(run.FinishedAt)
+	message := fmt.Sprintf("last successful catalog sync %s ago", age.Round(time.Minute))
+	return message, age <= s.policy.MaxSyncAge
+}
+
+// Status reports every job with its next scheduled run, its last run and
+// when it last succeeded.
+func (s *HealthDefault) Status() ([]entities.JobStatus, error) {
+	latest, err := s.rp.Latest()
+	if err != nil {
+		return nil, err
+	}
+	lastRuns := make(map[string]entities.JobRun, len(latest))
+	for _, run := range latest {
+		lastRuns[run.Job] = run
+	}
+
+	s.mu.Lock()
+	nextRuns := make(map[string]time.Time, len(s.nextRuns))
+	for job, next := range s.nextRuns {
+		nextRuns[job] = next
+	}
+	s.mu.Unlock()
+
+	statuses := make([]entities.JobStatus, 0, len(entities.Jobs))
+	for _, job := range entities.Jobs {
+		status := entities.JobStatus{Job: job}
+
+		if next, ok := nextRuns[job]; ok {
+			status.NextRunAt = &next
+		}
+
+		if run, ok := lastRuns[job]; ok {
+			status.LastRun = &run
+			if run.Result == entities.JobResultSuccess {
+				status.LastSuccessAt = &run.FinishedAt
+			} else {
+				success, err := s.rp.LastSuccess(job)
+				if err != nil {
+					return nil, err
+				}
+				if success != nil {
+					status.LastSuccessAt = &success.FinishedAt
+				}
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Scheduled records when a job runs next.
+func (s *HealthDefault) Scheduled(job string, next time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextRuns[job] = next
+}
+
+// Finished stores the outcome of a job run. A failure to store it is only
+// logged; it must not stop the job.
+func (s *HealthDefault) Finished(run entities.JobRun) {
+	if _, err := s.rp.Create(run); err != nil {
+		s.logger.Error("error recording job run",
+			slog.String(logging.KeyJob, run.Job),
+			slog.String(logging.KeyRunID, run.RunID),
+			logging.Err(err),
+		)
+	}
+}
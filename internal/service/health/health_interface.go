@@ -0,0 +1,26 @@
+package health
+
+import (
+	"context"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type HealthService interface {
+	Ready() entities.Readiness
+	Status() ([]entities.JobStatus, error)
+	// Scheduled and Finished are called by the background jobs.
+	Scheduled(job string, next time.Time)
+	Finished(run entities.JobRun)
+}
+
+// Pinger checks that the database answers.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// TokenSource reports the state of the cached Walmart access token. It must
+// not request a token: the readiness probe is public.
+type TokenSource interface {
+	TokenState() entities.TokenState
+}
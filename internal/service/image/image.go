@@ -0,0 +1,618 @@
+package image
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/imagematch"
+	"walmart-inventory-manager/internal/imagestore"
+	"walmart-inventory-manager/internal/repositories/image"
+	"walmart-inventory-manager/internal/repositories/inventory"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+type ImageDefault struct {
+	rp        image.ImageRepository
+	inventory inventory.InventoryRepository
+	store     *imagestore.Store
+	searcher  ImageSearcher
+	client    *http.Client
+	policy    entities.ImagePolicy
+	backfill  entities.ImageBackfillPolicy
+
+	// mu guards the backfill state; a run holds it for its whole duration.
+	mu      sync.Mutex
+	running bool
+	lastRun *entities.ImageBackfillRun
+}
+
+func NewImageDefault(rp image.ImageRepository, inventory inventory.InventoryRepository, store *imagestore.Store, searcher ImageSearcher, policy entities.ImagePolicy, backfill entities.ImageBackfillPolicy) *ImageDefault {
+	return &ImageDefault{
+		rp:        rp,
+		inventory: inventory,
+		store:     store,
+		searcher:  searcher,
+		client:    imagestore.NewClient(30 * time.Second),
+		policy:    policy,
+		backfill:  backfill,
+	}
+}
+
+func (s *ImageDefault) List(sku string) ([]entities.ProductImage, error) {
+	product, err := s.findProduct(sku)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.rp.FindByProduct(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		withURLs(&images[i])
+	}
+
+	return images, nil
+}
+
+// Upload stores an image sent by a user. Uploading an image the product
+// already has returns the existing one.
+func (s *ImageDefault) Upload(sku string, data []byte, primary bool) (*entities.ProductImage, error) {
+	product, err := s.findProduct(sku)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.policy.MaxBytes {
+		return nil, errors.NewBadRequest(fmt.Sprintf("image exceeds the %d byte limit", s.policy.MaxBytes))
+	}
+
+	img, _, err := s.save(product.ID, entities.ImageSourceUpload, "", data, primary)
+	return img, err
+}
+
+// AddFromURL downloads an image chosen by a user, e.g. to replace a wrong match.
+func (s *ImageDefault) AddFromURL(sku, imageURL string, primary bool) (*entities.ProductImage, error) {
+	product, err := s.findProduct(sku)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(strings.TrimSpace(imageURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.NewBadRequest("url must be an absolute http or https URL")
+	}
+
+	data, err := imagestore.Download(s.client, parsed.String(), s.policy.MaxBytes)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("could not download image: %v", err))
+	}
+
+	img, _, err := s.save(product.ID, entities.ImageSourceUpload, parsed.String(), data, primary)
+	return img, err
+}
+
+func (s *ImageDefault) SetPrimary(sku string, id int64) ([]entities.ProductImage, error) {
+	product, err := s.findProduct(sku)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.findImage(product, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.rp.SetPrimary(product.ID, id); err != nil {
+		return nil, err
+	}
+	if err := s.syncProductImage(product.ID); err != nil {
+		return nil, err
+	}
+
+	return s.List(sku)
+}
+
+func (s *ImageDefault) Delete(sku string, id int64) error {
+	product, err := s.findProduct(sku)
+	if err != nil {
+		return err
+	}
+	img, err := s.findImage(product, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rp.Delete(product.ID, id); err != nil {
+		return err
+	}
+	if err := s.store.Remove(img.FileName, img.ThumbnailName); err != nil {
+		return err
+	}
+
+	return s.syncProductImage(product.ID)
+}
+
+func (s *ImageDefault) Open(sku string, id int64, thumbnail bool) (*ImageFile, error) {
+	product, err := s.findProduct(sku)
+	if err != nil {
+		return nil, err
+	}
+	img, err := s.findImage(product, id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, contentType := img.FileName, img.ContentType
+	if thumbnail {
+		name, contentType = img.ThumbnailName, "image/jpeg"
+	}
+
+	file, err := s.store.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cached image %d: %w", id, err)
+	}
+
+	return &ImageFile{File: file, ContentType: contentType, Image: *img}, nil
+}
+
+// NeedsWalmartImages reports whether the product's images should be looked up
+// on Walmart: it has none, or the Walmart ones are older than the refresh
+// period. Products whose images were all uploaded, or that have a match
+// waiting for review, are left alone.
+func (s *ImageDefault) NeedsWalmartImages(productID int64) (bool, error) {
+	reviews, err := s.rp.FindReviewsByProduct(productID)
+	if err != nil {
+		return false, err
+	}
+	for _, review := range reviews {
+		if review.Status == entities.ImageReviewPending {
+			return false, nil
+		}
+	}
+
+	images, err := s.rp.FindByProduct(productID)
+	if err != nil {
+		return false, err
+	}
+	if len(images) == 0 {
+		return true, nil
+	}
+
+	last, err := s.rp.LastFetched(productID, entities.ImageSourceWalmart)
+	if err != nil || last == nil {
+		return false, err
+	}
+
+	return time.Since(*last) > s.policy.RefreshAfter, nil
+}
+
+// SyncWalmartImages downloads the images Walmart lists for a product and
+// returns how many were new. Images already cached are only marked as seen.
+func (s *ImageDefault) SyncWalmartImages(productID int64, urls []string) (int, error) {
+	var added int
+	var errs []error
+	for _, imageURL := range urls {
+		data, err := imagestore.Download(s.client, imageURL, s.policy.MaxBytes)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		_, created, err := s.save(productID, entities.ImageSourceWalmart, imageURL, data, false)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", imageURL, err))
+			continue
+		}
+		if created {
+			added++
+		}
+	}
+
+	return added, stdErrors.Join(errs...)
+}
+
+// ApplyMatch acts on the catalog item found for a product. Confident matches
+// have their images downloaded, and weaker ones are queued for review unless
+// the same item was reviewed before: an approved item is used again and a
+// rejected one is dropped. Matches below the review threshold are dropped.
+func (s *ImageDefault) ApplyMatch(productID int64, match entities.ImageMatch) (entities.ImageMatchOutcome, int, error) {
+	switch imagematch.Decide(match.Score, s.policy) {
+	case entities.ImageMatchAccepted:
+		added, err := s.SyncWalmartImages(productID, match.ImageURLs)
+		return entities.ImageMatchAccepted, added, err
+	case entities.ImageMatchDiscarded:
+		return entities.ImageMatchDiscarded, 0, nil
+	}
+
+	reviews, err := s.rp.FindReviewsByProduct(productID)
+	if err != nil {
+		return "", 0, err
+	}
+	for _, review := range reviews {
+		if !sameItem(review.Match, match) {
+			continue
+		}
+		switch review.Status {
+		case entities.ImageReviewApproved:
+			added, err := s.SyncWalmartImages(productID, match.ImageURLs)
+			return entities.ImageMatchAccepted, added, err
+		case entities.ImageReviewPending:
+			return entities.ImageMatchQueued, 0, nil
+		default:
+			return entities.ImageMatchDiscarded, 0, nil
+		}
+	}
+
+	_, err = s.rp.CreateReview(entities.ImageReview{
+		ProductID: productID,
+		Match:     match,
+		Status:    entities.ImageReviewPending,
+	})
+	if err != nil {
+		return "", 0, err
+	}
+
+	return entities.ImageMatchQueued, 0, nil
+}
+
+func (s *ImageDefault) Reviews(status entities.ImageReviewStatus, limit int) ([]entities.ImageReview, error) {
+	if status != "" && !status.Valid() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid review status %q", status))
+	}
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	return s.rp.FindReviews(status, limit)
+}
+
+// ApproveReview downloads the images of a queued match. The review stays
+// pending when none of them could be stored.
+func (s *ImageDefault) ApproveReview(id int64) (*entities.ImageReview, error) {
+	review, err := s.findPendingReview(id)
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := s.SyncWalmartImages(review.ProductID, review.Match.ImageURLs)
+	if err != nil && added == 0 {
+		return nil, fmt.Errorf("failed to download the images of review %d: %w", id, err)
+	}
+
+	if err := s.rp.SetReviewStatus(id, entities.ImageReviewApproved); err != nil {
+		return nil, err
+	}
+	if err := s.rp.Requeue(review.ProductID); err != nil {
+		return nil, err
+	}
+
+	return s.rp.FindReview(id)
+}
+
+func (s *ImageDefault) RejectReview(id int64) (*entities.ImageReview, error) {
+	review, err := s.findPendingReview(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.rp.SetReviewStatus(id, entities.ImageReviewRejected); err != nil {
+		return nil, err
+	}
+	// The product goes back to the backfill queue, where the rejected item is
+	// skipped and the lookup retried until a better match turns up.
+	if err := s.rp.Requeue(review.ProductID); err != nil {
+		return nil, err
+	}
+
+	return s.rp.FindReview(id)
+}
+
+func (s *ImageDefault) findPendingReview(id int64) (*entities.ImageReview, error) {
+	review, err := s.rp.FindReview(id)
+	if err != nil {
+		return nil, err
+	}
+	if review == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("image review %d not found", id))
+	}
+	if review.Status != entities.ImageReviewPending {
+		return nil, errors.NewBadRequest(fmt.Sprintf("image review %d is already %s", id, review.Status))
+	}
+	return review, nil
+}
+
+// RunBackfill queues the products that need images and looks up a batch of
+// those that are due. Only one run happens at a time.
+func (s *ImageDefault) RunBackfill() (*entities.ImageBackfillRun, error) {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, errors.NewConflict("the image backfill is already running")
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	run := &entities.ImageBackfillRun{StartedAt: time.Now()}
+	defer func() {
+		run.FinishedAt = time.Now()
+		s.mu.Lock()
+		s.running = false
+		s.lastRun = run
+		s.mu.Unlock()
+	}()
+
+	missing, err := s.rp.EnqueueMissing()
+	if err != nil {
+		return nil, err
+	}
+	refreshed, err := s.rp.EnqueueRefresh(time.Now().Add(-s.policy.RefreshAfter))
+	if err != nil {
+		return nil, err
+	}
+	run.Enqueued = missing + refreshed
+
+	items, err := s.rp.FindDueBackfills(s.backfill.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		outcome, added, err := s.backfillOne(item)
+		run.Processed++
+		run.ImagesAdded += added
+
+		item.Attempts++
+		item.LastOutcome = outcome
+		item.LastError = ""
+		if err != nil {
+			item.LastError = err.Error()
+		}
+
+		switch {
+		case outcome == string(entities.ImageMatchQueued):
+			item.Status = entities.ImageBackfillReview
+			run.Review++
+		case err == nil && outcome != string(entities.ImageMatchDiscarded) && outcome != backfillNoMatch:
+			item.Status = entities.ImageBackfillDone
+			run.Matched++
+		case item.Attempts >= s.backfill.MaxAttempts:
+			item.Status = entities.ImageBackfillExhausted
+			run.Exhausted++
+		default:
+			item.NextAttemptAt = time.Now().Add(retryDelay(item.Attempts, s.backfill.RetryBase, s.backfill.RetryMax))
+			run.Retried++
+		}
+
+		if err := s.rp.UpdateBackfill(item); err != nil {
+			return run, err
+		}
+	}
+
+	return run, nil
+}
+
+// Outcomes of a backfill attempt besides the ImageMatchOutcome values.
+const (
+	backfillNotNeeded = "not_needed"
+	backfillStored    = "stored_url"
+	backfillNoMatch   = "no_match"
+	backfillError     = "error"
+)
+
+// backfillOne looks up the images of one queued product and reports what
+// happened. An image URL stored before images were cached locally is
+// downloaded first, so those products cost no catalog search.
+func (s *ImageDefault) backfillOne(item entities.ImageBackfillItem) (string, int, error) {
+	needed, err := s.NeedsWalmartImages(item.ProductID)
+	if err != nil {
+		return backfillError, 0, err
+	}
+	if !needed {
+		return backfillNotNeeded, 0, nil
+	}
+
+	if strings.HasPrefix(item.ProductImage, "http") {
+		added, err := s.SyncWalmartImages(item.ProductID, []string{item.ProductImage})
+		if err == nil || added > 0 {
+			return backfillStored, added, nil
+		}
+	}
+
+	match, err := s.searcher.ItemSearch(item.ProductName, item.UPC, item.GTIN)
+	if err != nil {
+		return backfillError, 0, err
+	}
+	if match == nil {
+		return backfillNoMatch, 0, nil
+	}
+
+	outcome, added, err := s.ApplyMatch(item.ProductID, *match)
+	if err != nil && added > 0 {
+		// Some images were stored; the rest are picked up on the next refresh.
+		err = nil
+	}
+	return string(outcome), added, err
+}
+
+// retryDelay doubles base with every failed attempt, up to limit.
+func retryDelay(attempts int, base, limit time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+func (s *ImageDefault) BackfillProgress() (*entities.ImageBackfillProgress, error) {
+	progress, err := s.rp.CountBackfills()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	progress.Running = s.running
+	progress.LastRun = s.lastRun
+	s.mu.Unlock()
+
+	return progress, nil
+}
+
+func (s *ImageDefault) Backfills(status entities.ImageBackfillStatus, limit int) ([]entities.ImageBackfillItem, error) {
+	if status != "" && !status.Valid() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid backfill status %q", status))
+	}
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	return s.rp.FindBackfills(status, limit)
+}
+
+// Requeue puts the given SKUs at the front of the backfill queue with their
+// attempts reset, whatever state they were in.
+func (s *ImageDefault) Requeue(skus []string) (*entities.ImageBackfillProgress, error) {
+	if len(skus) == 0 {
+		return nil, errors.NewBadRequest("at least one sku is required")
+	}
+
+	productIDs := make([]int64, 0, len(skus))
+	for _, sku := range skus {
+		product, err := s.findProduct(strings.TrimSpace(sku))
+		if err != nil {
+			return nil, err
+		}
+		productIDs = append(productIDs, product.ID)
+	}
+
+	for _, productID := range productIDs {
+		if err := s.rp.Requeue(productID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.BackfillProgress()
+}
+
+// sameItem compares catalog items by ID, or by title when either has none.
+func sameItem(a, b entities.ImageMatch) bool {
+	if a.ItemID != "" && b.ItemID != "" {
+		return a.ItemID == b.ItemID
+	}
+	return strings.EqualFold(a.Title, b.Title)
+}
+
+// save caches data and records it, or refreshes the existing record when the
+// product already has the same image. It reports whether a new image was added.
+func (s *ImageDefault) save(productID int64, source entities.ImageSource, sourceURL string, data []byte, primary bool) (*entities.ProductImage, bool, error) {
+	stored, err := s.store.Save(productID, data)
+	if err != nil {
+		if err == imagestore.ErrUnsupportedImage || stdErrors.Is(err, imagestore.ErrTooManyPixels) {
+			return nil, false, errors.NewBadRequest(err.Error())
+		}
+		return nil, false, err
+	}
+
+	existing, err := s.rp.FindByChecksum(productID, stored.Checksum)
+	if err != nil {
+		return nil, false, err
+	}
+
+	id := int64(0)
+	if existing != nil {
+		id = existing.ID
+		if err := s.rp.Touch(id, sourceURL); err != nil {
+			return nil, false, err
+		}
+		if primary {
+			if err := s.rp.SetPrimary(productID, id); err != nil {
+				return nil, false, err
+			}
+		}
+	} else {
+		id, err = s.rp.Insert(entities.ProductImage{
+			ProductID:     productID,
+			Source:        source,
+			SourceURL:     sourceURL,
+			FileName:      stored.FileName,
+			ThumbnailName: stored.ThumbnailName,
+			ContentType:   stored.ContentType,
+			Width:         stored.Width,
+			Height:        stored.Height,
+			SizeBytes:     stored.SizeBytes,
+			Checksum:      stored.Checksum,
+		}, primary)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := s.syncProductImage(productID); err != nil {
+		return nil, false, err
+	}
+
+	img, err := s.rp.FindByID(productID, id)
+	if err != nil {
+		return nil, false, err
+	}
+	withURLs(img)
+
+	return img, existing == nil, nil
+}
+
+// syncProductImage points products.product_image at the primary image.
+func (s *ImageDefault) syncProductImage(productID int64) error {
+	images, err := s.rp.FindByProduct(productID)
+	if err != nil {
+		return err
+	}
+
+	primaryURL := ""
+	if len(images) > 0 && images[0].Primary {
+		withURLs(&images[0])
+		primaryURL = images[0].URL
+	}
+
+	return s.inventory.UpdateProductImage(productID, primaryURL)
+}
+
+func (s *ImageDefault) findProduct(sku string) (*entities.Product, error) {
+	product, err := s.inventory.GetProductBySKU(sku)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with SKU %s not found", sku))
+	}
+	return product, nil
+}
+
+func (s *ImageDefault) findImage(product *entities.Product, id int64) (*entities.ProductImage, error) {
+	img, err := s.rp.FindByID(product.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("image %d of SKU %s not found", id, product.SKU))
+	}
+	return img, nil
+}
+
+// withURLs fills in where the API serves the image and its thumbnail.
+func withURLs(img *entities.ProductImage) {
+	img.URL = fmt.Sprintf("/api/v1/inventory/%s/images/%d", url.PathEscape(img.SKU), img.ID)
+	img.ThumbnailURL = img.URL + "/thumbnail"
+}
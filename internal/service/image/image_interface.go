@@ -0,0 +1,35 @@
+package image
+
+import (
+	"os"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type ImageService interface {
+	List(sku string) ([]entities.ProductImage, error)
+	Upload(sku string, data []byte, primary bool) (*entities.ProductImage, error)
+	AddFromURL(sku, url string, primary bool) (*entities.ProductImage, error)
+	SetPrimary(sku string, id int64) ([]entities.ProductImage, error)
+	Delete(sku string, id int64) error
+	Open(sku string, id int64, thumbnail bool) (*ImageFile, error)
+	ApplyMatch(productID int64, match entities.ImageMatch) (entities.ImageMatchOutcome, int, error)
+	Reviews(status entities.ImageReviewStatus, limit int) ([]entities.ImageReview, error)
+	ApproveReview(id int64) (*entities.ImageReview, error)
+	RejectReview(id int64) (*entities.ImageReview, error)
+	RunBackfill() (*entities.ImageBackfillRun, error)
+	BackfillProgress() (*entities.ImageBackfillProgress, error)
+	Backfills(status entities.ImageBackfillStatus, limit int) ([]entities.ImageBackfillItem, error)
+	Requeue(skus []string) (*entities.ImageBackfillProgress, error)
+}
+
+// ImageSearcher finds the catalog item a product's images come from.
+type ImageSearcher interface {
+	ItemSearch(productName, upc, gtin string) (*entities.ImageMatch, error)
+}
+
+// ImageFile is an open cached image. The caller closes it.
+type ImageFile struct {
+	*os.File
+	ContentType string
+	Image       entities.ProductImage
+}
@@ -1,18 +1,87 @@
 package inventory
 
 import (
+	"fmt"
+	"walmart-inventory-manager/internal/barcode"
 	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
 	"walmart-inventory-manager/internal/repositories/inventory"
+	"walmart-inventory-manager/internal/service/forecast"
 )
 
 type InventoryDefault struct {
-	rp inventory.InventoryRepository
+	rp       inventory.InventoryRepository
+	forecast forecast.ForecastService
 }
 
-func NewInventoryDefault(rp inventory.InventoryRepository) *InventoryDefault {
-	return &InventoryDefault{rp: rp}
+func NewInventoryDefault(rp inventory.InventoryRepository, forecast forecast.ForecastService) *InventoryDefault {
+	return &InventoryDefault{rp: rp, forecast: forecast}
 }
 
+// FindAll lists every product together with its sales forecast.
 func (s *InventoryDefault) FindAll() ([]entities.Product, error) {
-	return s.rp.FindAll()
+	products, err := s.rp.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts, err := s.forecast.ForecastAll()
+	if err != nil {
+		return nil, err
+	}
+
+	bySKU := make(map[string]*entities.Forecast, len(forecasts))
+	for i := range forecasts {
+		bySKU[forecasts[i].SKU] = &forecasts[i]
+	}
+	for i := range products {
+		products[i].Forecast = bySKU[products[i].SKU]
+	}
+
+	return products, nil
+}
+
+func (s *InventoryDefault) FindBySKU(sku string) (*entities.Product, error) {
+	return findProduct("sku", sku, s.rp.GetProductBySKU)
+}
+
+func (s *InventoryDefault) FindByGTIN(gtin string) (*entities.Product, error) {
+	return findBarcode("gtin", gtin, s.rp.GetProductByGTIN)
+}
+
+func (s *InventoryDefault) FindByUPC(upc string) (*entities.Product, error) {
+	return findBarcode("upc", upc, s.rp.GetProductByUPC)
+}
+
+func (s *InventoryDefault) FindByWPID(wpid string) (*entities.Product, error) {
+	return findProduct("wpid", wpid, s.rp.GetProductByWPID)
+}
+
+// findProduct runs a repository lookup and turns a missing row into a ResourceNotFound error.
+func findProduct(field, value string, lookup func(string) (*entities.Product, error)) (*entities.Product, error) {
+	if value == "" {
+		return nil, errors.NewBadRequest(fmt.Sprintf("%s is required", field))
+	}
+
+	product, err := lookup(value)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with %s %s not found", field, value))
+	}
+
+	return product, nil
+}
+
+// findBarcode looks a product up by UPC or GTIN. When nothing matches and the
+// value is not a valid barcode, the caller is told why instead of getting a 404.
+func findBarcode(field, value string, lookup func(string) (*entities.Product, error)) (*entities.Product, error) {
+	product, err := findProduct(field, value, lookup)
+	if _, notFound := err.(errors.ResourceNotFound); notFound {
+		if _, parseErr := barcode.Parse(value); parseErr != nil {
+			return nil, errors.NewBadRequest(parseErr.Error())
+		}
+	}
+	return product, err
 }
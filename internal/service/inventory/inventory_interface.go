@@ -4,4 +4,8 @@ import "walmart-inventory-manager/internal/entities"
 
 type InventoryService interface {
 	FindAll() ([]entities.Product, error)
+	FindBySKU(sku string) (*entities.Product, error)
+	FindByGTIN(gtin string) (*entities.Product, error)
+	FindByUPC(upc string) (*entities.Product, error)
+	FindByWPID(wpid string) (*entities.Product, error)
 }
@@ -0,0 +1,92 @@
+package listing
+
+import (
+	"fmt"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/repositories/inventory"
+	"walmart-inventory-manager/internal/repositories/listing"
+)
+
+const (
+	defaultHistoryLimit = 100
+	maxHistoryLimit     = 1000
+)
+
+type ListingDefault struct {
+	rp        listing.ListingRepository
+	inventory inventory.InventoryRepository
+}
+
+func NewListingDefault(rp listing.ListingRepository, inventory inventory.InventoryRepository) *ListingDefault {
+	return &ListingDefault{rp: rp, inventory: inventory}
+}
+
+func (s *ListingDefault) Statuses() []entities.ListingStatusInfo {
+	statuses := make([]entities.ListingStatusInfo, 0, len(entities.ListingStatuses))
+	for _, status := range entities.ListingStatuses {
+		statuses = append(statuses, entities.ListingStatusInfo{ID: status, Name: status.String()})
+	}
+	return statuses
+}
+
+func (s *ListingDefault) Rules() ([]entities.ListingStatusRule, error) {
+	return s.rp.FindRules()
+}
+
+// ReplaceRules swaps the whole rule set. A rule may name its status or give its
+// ID; rules without a position keep the order they were sent in.
+func (s *ListingDefault) ReplaceRules(rules []entities.ListingStatusRule) ([]entities.ListingStatusRule, error) {
+	if len(rules) == 0 {
+		return nil, errors.NewBadRequest("at least one rule is required")
+	}
+
+	for i, rule := range rules {
+		if rule.Status != "" {
+			status, ok := entities.ParseListingStatus(rule.Status)
+			if !ok {
+				return nil, errors.NewBadRequest(fmt.Sprintf("rule %d: unknown listing status %q", i, rule.Status))
+			}
+			rule.StatusID = status
+		}
+		if !rule.StatusID.Valid() {
+			return nil, errors.NewBadRequest(fmt.Sprintf("rule %d: invalid listing status id %d", i, rule.StatusID))
+		}
+		if rule.StatusID == entities.ListingStatusDelisted {
+			return nil, errors.NewBadRequest(fmt.Sprintf("rule %d: delisted is only set for SKUs missing from Walmart", i))
+		}
+		rule.LifecycleStatus = strings.TrimSpace(rule.LifecycleStatus)
+		rule.PublishedStatus = strings.TrimSpace(rule.PublishedStatus)
+		rule.Availability = strings.TrimSpace(rule.Availability)
+		if rule.Position == 0 {
+			rule.Position = (i + 1) * 10
+		}
+		rules[i] = rule
+	}
+
+	if err := s.rp.ReplaceRules(rules); err != nil {
+		return nil, err
+	}
+
+	return s.rp.FindRules()
+}
+
+func (s *ListingDefault) History(sku string, limit int) ([]entities.ListingTransition, error) {
+	product, err := s.inventory.GetProductBySKU(sku)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with SKU %s not found", sku))
+	}
+
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+
+	return s.rp.FindHistory(product.ID, limit)
+}
@@ -0,0 +1,10 @@
+package listing
+
+import "walmart-inventory-manager/internal/entities"
+
+type ListingService interface {
+	Statuses() []entities.ListingStatusInfo
+	Rules() ([]entities.ListingStatusRule, error)
+	ReplaceRules(rules []entities.ListingStatusRule) ([]entities.ListingStatusRule, error)
+	History(sku string, limit int) ([]entities.ListingTransition, error)
+}
@@ -0,0 +1,116 @@
+package location
+
+import (
+	"fmt"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/repositories/location"
+	"walmart-inventory-manager/internal/repositories/stock"
+)
+
+type LocationDefault struct {
+	rp    location.LocationRepository
+	stock stock.StockRepository
+}
+
+func NewLocationDefault(rp location.LocationRepository, stock stock.StockRepository) *LocationDefault {
+	return &LocationDefault{rp: rp, stock: stock}
+}
+
+func (s *LocationDefault) FindAll() ([]entities.Location, error) {
+	return s.rp.FindAll()
+}
+
+func (s *LocationDefault) FindByCode(code string) (*entities.Location, error) {
+	location, err := s.rp.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if location == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("location %s not found", code))
+	}
+
+	return location, nil
+}
+
+func (s *LocationDefault) Create(location entities.Location) (*entities.Location, error) {
+	location.Code = strings.TrimSpace(location.Code)
+	location.Name = strings.TrimSpace(location.Name)
+	if location.Code == "" || location.Name == "" {
+		return nil, errors.NewBadRequest("code and name are required")
+	}
+	if location.Type == "" {
+		location.Type = entities.LocationTypeWarehouse
+	}
+	if !location.Type.Valid() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid location type %q", location.Type))
+	}
+	if location.IsDefault && location.Type != entities.LocationTypeWarehouse {
+		return nil, errors.NewBadRequest("only warehouse locations can be the default")
+	}
+
+	existing, err := s.rp.FindByCode(location.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("location %s already exists", location.Code))
+	}
+
+	location.Active = true
+	if _, err := s.rp.Create(location); err != nil {
+		return nil, err
+	}
+
+	return s.FindByCode(location.Code)
+}
+
+// Update changes the descriptive fields of a location. Code and type are fixed
+// once created because ledger entries refer to them.
+func (s *LocationDefault) Update(code string, changes entities.Location) (*entities.Location, error) {
+	location, err := s.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(changes.Name); name != "" {
+		location.Name = name
+	}
+	location.Address = changes.Address
+	location.Active = changes.Active
+	if changes.IsDefault && location.Type != entities.LocationTypeWarehouse {
+		return nil, errors.NewBadRequest("only warehouse locations can be the default")
+	}
+	if location.IsDefault && !changes.IsDefault {
+		return nil, errors.NewBadRequest("mark another warehouse as default instead of unsetting it")
+	}
+	location.IsDefault = changes.IsDefault || location.IsDefault
+
+	if err := s.rp.Update(*location); err != nil {
+		return nil, err
+	}
+
+	return s.FindByCode(code)
+}
+
+func (s *LocationDefault) Stock(code string) ([]entities.LocationStock, error) {
+	location, err := s.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stock.FindStockByLocation(location.ID)
+}
+
+func (s *LocationDefault) StockBySKU(sku string) (*entities.StockByLocation, error) {
+	stock, err := s.stock.FindStockBySKU(sku)
+	if err != nil {
+		return nil, err
+	}
+	if stock == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with sku %s not found", sku))
+	}
+
+	return stock, nil
+}
@@ -0,0 +1,12 @@
+package location
+
+import "walmart-inventory-manager/internal/entities"
+
+type LocationService interface {
+	FindAll() ([]entities.Location, error)
+	FindByCode(code string) (*entities.Location, error)
+	Create(location entities.Location) (*entities.Location, error)
+	Update(code string, location entities.Location) (*entities.Location, error)
+	Stock(code string) ([]entities.LocationStock, error)
+	StockBySKU(sku string) (*entities.StockByLocation, error)
+}
@@ -0,0 +1,148 @@
+package margin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/margin"
+	"walmart-inventory-manager/internal/money"
+	"walmart-inventory-manager/internal/repositories/inventory"
+	marginRepository "walmart-inventory-manager/internal/repositories/margin"
+)
+
+// defaultReportDays is the period covered when no dates are given.
+const defaultReportDays = 30
+
+type MarginDefault struct {
+	rp        marginRepository.MarginRepository
+	inventory inventory.InventoryRepository
+}
+
+func NewMarginDefault(rp marginRepository.MarginRepository, inventory inventory.InventoryRepository) *MarginDefault {
+	return &MarginDefault{rp: rp, inventory: inventory}
+}
+
+// Report computes the margin of every SKU over the period from..to. A zero to
+// means today and a zero from the defaultReportDays before it.
+func (s *MarginDefault) Report(from, to time.Time) (*entities.MarginReport, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultReportDays+1)
+	}
+	if from.After(to) {
+		return nil, errors.NewBadRequest("from must not be after to")
+	}
+
+	schedule, err := s.rp.FindFeeSchedule()
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.rp.FindMarginItems(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entities.MarginReport{
+		From:         from,
+		To:           to,
+		Lines:        make([]entities.MarginLine, 0, len(items)),
+		TotalRevenue: money.Totals{},
+		TotalProfit:  money.Totals{},
+		GeneratedAt:  time.Now(),
+	}
+	for _, item := range items {
+		line, err := margin.Calculate(item, *schedule)
+		if err != nil {
+			return nil, err
+		}
+		report.Lines = append(report.Lines, line)
+		report.TotalRevenue.Add(line.Revenue)
+		if line.PeriodProfit != nil {
+			report.TotalProfit.Add(*line.PeriodProfit)
+		} else {
+			report.MissingCost++
+		}
+	}
+
+	return report, nil
+}
+
+func (s *MarginDefault) FeeSchedule() (*entities.FeeSchedule, error) {
+	return s.rp.FindFeeSchedule()
+}
+
+func (s *MarginDefault) ReplaceReferralFees(fees []entities.ReferralFee) (*entities.FeeSchedule, error) {
+	seen := make(map[string]bool, len(fees))
+	for i, fee := range fees {
+		fee.Category = strings.TrimSpace(fee.Category)
+		if seen[fee.Category] {
+			return nil, errors.NewBadRequest(fmt.Sprintf("fee %d: category %q is listed twice", i, fee.Category))
+		}
+		seen[fee.Category] = true
+		if fee.Percent < 0 || fee.Percent > 100 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("fee %d: percent must be between 0 and 100", i))
+		}
+		if fee.MinimumFee.IsNegative() {
+			return nil, errors.NewBadRequest(fmt.Sprintf("fee %d: minimumFee cannot be negative", i))
+		}
+		fees[i] = fee
+	}
+	if !seen[""] {
+		return nil, errors.NewBadRequest("a default referral fee with an empty category is required")
+	}
+
+	if err := s.rp.ReplaceReferralFees(fees); err != nil {
+		return nil, err
+	}
+
+	return s.rp.FindFeeSchedule()
+}
+
+func (s *MarginDefault) ReplaceFulfillmentFees(fees []entities.FulfillmentFee) (*entities.FeeSchedule, error) {
+	seen := make(map[string]bool, len(fees))
+	for i, fee := range fees {
+		fee.SizeTier = strings.TrimSpace(fee.SizeTier)
+		if seen[fee.SizeTier] {
+			return nil, errors.NewBadRequest(fmt.Sprintf("fee %d: size tier %q is listed twice", i, fee.SizeTier))
+		}
+		seen[fee.SizeTier] = true
+		if fee.FeePerUnit.IsNegative() {
+			return nil, errors.NewBadRequest(fmt.Sprintf("fee %d: feePerUnit cannot be negative", i))
+		}
+		fees[i] = fee
+	}
+	if !seen[""] {
+		return nil, errors.NewBadRequest("a default fulfillment fee with an empty size tier is required")
+	}
+
+	if err := s.rp.ReplaceFulfillmentFees(fees); err != nil {
+		return nil, err
+	}
+
+	return s.rp.FindFeeSchedule()
+}
+
+// SetFeeProfile sets the category and size tier a SKU's fees are looked up by.
+// Empty values fall back to the default rows.
+func (s *MarginDefault) SetFeeProfile(sku string, profile entities.FeeProfile) (*entities.FeeProfile, error) {
+	product, err := s.inventory.GetProductBySKU(sku)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with SKU %s not found", sku))
+	}
+
+	profile.Category = strings.TrimSpace(profile.Category)
+	profile.SizeTier = strings.TrimSpace(profile.SizeTier)
+	if err := s.rp.UpdateFeeProfile(product.ID, profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
@@ -0,0 +1,14 @@
+package margin
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type MarginService interface {
+	Report(from, to time.Time) (*entities.MarginReport, error)
+	FeeSchedule() (*entities.FeeSchedule, error)
+	ReplaceReferralFees(fees []entities.ReferralFee) (*entities.FeeSchedule, error)
+	ReplaceFulfillmentFees(fees []entities.FulfillmentFee) (*entities.FeeSchedule, error)
+	SetFeeProfile(sku string, profile entities.FeeProfile) (*entities.FeeProfile, error)
+}
@@ -0,0 +1,158 @@
+package purchaseorder
+
+import (
+	"fmt"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/repositories/inventory"
+	"walmart-inventory-manager/internal/repositories/purchaseorder"
+	"walmart-inventory-manager/internal/repositories/supplier"
+	"walmart-inventory-manager/internal/service/replenishment"
+)
+
+type PurchaseOrderDefault struct {
+	rp            purchaseorder.PurchaseOrderRepository
+	suppliers     supplier.SupplierRepository
+	inventory     inventory.InventoryRepository
+	replenishment replenishment.ReplenishmentService
+}
+
+func NewPurchaseOrderDefault(rp purchaseorder.PurchaseOrderRepository, suppliers supplier.SupplierRepository, inventory inventory.InventoryRepository, replenishment replenishment.ReplenishmentService) *PurchaseOrderDefault {
+	return &PurchaseOrderDefault{rp: rp, suppliers: suppliers, inventory: inventory, replenishment: replenishment}
+}
+
+func (s *PurchaseOrderDefault) FindAll(filter entities.PurchaseOrderFilter) ([]entities.PurchaseOrder, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid purchase order status %q", filter.Status))
+	}
+	return s.rp.FindAll(filter)
+}
+
+func (s *PurchaseOrderDefault) FindByID(id int64) (*entities.PurchaseOrder, error) {
+	order, err := s.rp.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("purchase order %d not found", id))
+	}
+
+	return order, nil
+}
+
+// Create opens a purchase order. Lines without a unit cost take the product
+// cost recorded on the SKU.
+func (s *PurchaseOrderDefault) Create(order entities.PurchaseOrder) (*entities.PurchaseOrder, error) {
+	supplier, err := s.suppliers.FindByID(order.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("supplier %d not found", order.SupplierID))
+	}
+	if !supplier.Active {
+		return nil, errors.NewBadRequest(fmt.Sprintf("supplier %s is inactive", supplier.Name))
+	}
+	if len(order.Lines) == 0 {
+		return nil, errors.NewBadRequest("a purchase order needs at least one line")
+	}
+
+	seen := make(map[string]bool, len(order.Lines))
+	for i, line := range order.Lines {
+		line.SKU = strings.TrimSpace(line.SKU)
+		if line.SKU == "" {
+			return nil, errors.NewBadRequest(fmt.Sprintf("line %d: sku is required", i))
+		}
+		if seen[line.SKU] {
+			return nil, errors.NewBadRequest(fmt.Sprintf("line %d: sku %s is already on the order", i, line.SKU))
+		}
+		seen[line.SKU] = true
+		if line.QuantityOrdered <= 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("line %d: quantityOrdered must be positive", i))
+		}
+		if line.UnitCost.IsNegative() {
+			return nil, errors.NewBadRequest(fmt.Sprintf("line %d: unitCost cannot be negative", i))
+		}
+
+		product, err := s.inventory.GetProductBySKU(line.SKU)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, errors.NewResourceNotFound(fmt.Sprintf("product with SKU %s not found", line.SKU))
+		}
+		line.ProductID = product.ID
+		if line.UnitCost.IsZero() && product.Supply != nil && product.Supply.ProductCost != nil {
+			line.UnitCost = *product.Supply.ProductCost
+		}
+		line.QuantityReceived = 0
+		order.Lines[i] = line
+	}
+
+	id, err := s.rp.Create(order)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(id)
+}
+
+// CreateFromDraft turns the current reorder suggestion for a supplier into an
+// open purchase order.
+func (s *PurchaseOrderDefault) CreateFromDraft(supplierID int64) (*entities.PurchaseOrder, error) {
+	draft, err := s.replenishment.DraftPurchaseOrder(supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if len(draft.Lines) == 0 {
+		return nil, errors.NewBadRequest(fmt.Sprintf("nothing needs reordering from %s", draft.Supplier.Name))
+	}
+
+	order := entities.PurchaseOrder{SupplierID: supplierID, Note: "Created from reorder suggestion"}
+	for _, line := range draft.Lines {
+		order.Lines = append(order.Lines, entities.PurchaseOrderLine{
+			SKU:             line.SKU,
+			QuantityOrdered: line.SuggestedQty,
+			UnitCost:        line.UnitCost,
+		})
+	}
+
+	return s.Create(order)
+}
+
+func (s *PurchaseOrderDefault) Receive(id int64, receipt entities.PurchaseOrderReceipt) (*entities.PurchaseOrderReceipt, error) {
+	for i, line := range receipt.Lines {
+		if strings.TrimSpace(line.SKU) == "" {
+			return nil, errors.NewBadRequest(fmt.Sprintf("line %d: sku is required", i))
+		}
+		if line.Quantity <= 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("line %d: quantity must be positive", i))
+		}
+		receipt.Lines[i].SKU = strings.TrimSpace(line.SKU)
+	}
+	receipt.PurchaseOrderID = id
+
+	return s.rp.Receive(receipt)
+}
+
+// Close stops waiting for whatever is still outstanding on an order.
+func (s *PurchaseOrderDefault) Close(id int64) (*entities.PurchaseOrder, error) {
+	order, err := s.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.Inbound() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("purchase order %s is already %s", order.Number, order.Status))
+	}
+
+	if err := s.rp.Close(id); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(id)
+}
+
+func (s *PurchaseOrderDefault) Inbound(sku string) ([]entities.InboundStock, error) {
+	return s.rp.FindInbound(strings.TrimSpace(sku))
+}
@@ -0,0 +1,13 @@
+package purchaseorder
+
+import "walmart-inventory-manager/internal/entities"
+
+type PurchaseOrderService interface {
+	FindAll(filter entities.PurchaseOrderFilter) ([]entities.PurchaseOrder, error)
+	FindByID(id int64) (*entities.PurchaseOrder, error)
+	Create(order entities.PurchaseOrder) (*entities.PurchaseOrder, error)
+	CreateFromDraft(supplierID int64) (*entities.PurchaseOrder, error)
+	Receive(id int64, receipt entities.PurchaseOrderReceipt) (*entities.PurchaseOrderReceipt, error)
+	Close(id int64) (*entities.PurchaseOrder, error)
+	Inbound(sku string) ([]entities.InboundStock, error)
+}
@@ -0,0 +1,92 @@
+package reconciliation
+
+import (
+	"math"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/repositories/reconciliation"
+	"walmart-inventory-manager/internal/repositories/stock"
+)
+
+type ReconciliationDefault struct {
+	rp        reconciliation.ReconciliationRepository
+	stock     stock.StockRepository
+	tolerance entities.ReconciliationTolerance
+}
+
+func NewReconciliationDefault(rp reconciliation.ReconciliationRepository, stock stock.StockRepository, tolerance entities.ReconciliationTolerance) *ReconciliationDefault {
+	return &ReconciliationDefault{rp: rp, stock: stock, tolerance: tolerance}
+}
+
+// Reconcile compares warehouse stock with Walmart availability for every SKU and
+// stores the discrepancies that fall outside the configured tolerance.
+func (s *ReconciliationDefault) Reconcile(syncRunID string) (*entities.ReconciliationRun, error) {
+	levels, err := s.stock.FindStockLevels()
+	if err != nil {
+		return nil, err
+	}
+
+	run := entities.ReconciliationRun{
+		SyncRunID: syncRunID,
+		Tolerance: s.tolerance,
+		TotalSKUs: len(levels),
+		CreatedAt: time.Now(),
+		Items:     Compare(levels, s.tolerance),
+	}
+	run.DiscrepancyCount = len(run.Items)
+
+	run.ID, err = s.rp.SaveRun(run)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func (s *ReconciliationDefault) Latest() (*entities.ReconciliationRun, error) {
+	run, err := s.rp.FindLatestRun()
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, errors.NewResourceNotFound("no reconciliation has been run yet")
+	}
+
+	return run, nil
+}
+
+// Compare returns the SKUs whose balances disagree by more than both the unit
+// and the percentage tolerance. The percentage is taken of the larger balance.
+func Compare(levels []entities.StockLevel, tolerance entities.ReconciliationTolerance) []entities.ReconciliationItem {
+	items := []entities.ReconciliationItem{}
+	for _, level := range levels {
+		difference := level.WalmartAvailable - level.WarehouseStock
+		if withinTolerance(level, difference, tolerance) {
+			continue
+		}
+
+		status := entities.DiscrepancyWalmartLower
+		if difference > 0 {
+			status = entities.DiscrepancyWalmartHigher
+		}
+
+		items = append(items, entities.ReconciliationItem{
+			StockLevel: level,
+			Difference: difference,
+			Status:     status,
+		})
+	}
+
+	return items
+}
+
+func withinTolerance(level entities.StockLevel, difference int, tolerance entities.ReconciliationTolerance) bool {
+	absolute := int(math.Abs(float64(difference)))
+	if absolute == 0 || absolute <= tolerance.Units {
+		return true
+	}
+
+	base := math.Max(float64(level.WarehouseStock), float64(level.WalmartAvailable))
+	return base > 0 && float64(absolute)/base*100 <= tolerance.Percent
+}
@@ -0,0 +1,8 @@
+package reconciliation
+
+import "walmart-inventory-manager/internal/entities"
+
+type ReconciliationService interface {
+	Reconcile(syncRunID string) (*entities.ReconciliationRun, error)
+	Latest() (*entities.ReconciliationRun, error)
+}
@@ -0,0 +1,122 @@
+package replenishment
+
+import (
+	"fmt"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/replenishment"
+	"walmart-inventory-manager/internal/repositories/purchaseorder"
+	"walmart-inventory-manager/internal/repositories/supplier"
+	forecastService "walmart-inventory-manager/internal/service/forecast"
+)
+
+type ReplenishmentDefault struct {
+	suppliers      supplier.SupplierRepository
+	purchaseOrders purchaseorder.PurchaseOrderRepository
+	forecasts      forecastService.ForecastService
+	policy         entities.ReorderPolicy
+}
+
+func NewReplenishmentDefault(suppliers supplier.SupplierRepository, purchaseOrders purchaseorder.PurchaseOrderRepository, forecasts forecastService.ForecastService, policy entities.ReorderPolicy) *ReplenishmentDefault {
+	return &ReplenishmentDefault{suppliers: suppliers, purchaseOrders: purchaseOrders, forecasts: forecasts, policy: policy}
+}
+
+// Suggestions returns one suggestion per supplier that has at least one SKU to reorder.
+func (s *ReplenishmentDefault) Suggestions() ([]entities.ReorderSuggestion, error) {
+	suppliers, err := s.suppliers.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.suggest(0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []entities.ReorderSuggestion{}
+	for _, supplier := range suppliers {
+		if suggestion, ok := suggestions[supplier.ID]; ok {
+			suggestion.Supplier = supplier
+			result = append(result, *suggestion)
+		}
+	}
+
+	return result, nil
+}
+
+// DraftPurchaseOrder returns the lines to order from one supplier. The draft is
+// empty, not an error, when nothing needs reordering.
+func (s *ReplenishmentDefault) DraftPurchaseOrder(supplierID int64) (*entities.ReorderSuggestion, error) {
+	supplier, err := s.suppliers.FindByID(supplierID)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("supplier %d not found", supplierID))
+	}
+
+	suggestions, err := s.suggest(supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestion, ok := suggestions[supplierID]
+	if !ok {
+		suggestion = &entities.ReorderSuggestion{Lines: []entities.ReorderLine{}, GeneratedAt: time.Now()}
+	}
+	suggestion.Supplier = *supplier
+
+	return suggestion, nil
+}
+
+func (s *ReplenishmentDefault) suggest(supplierID int64) (map[int64]*entities.ReorderSuggestion, error) {
+	items, err := s.suppliers.FindSupplyItems(supplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	forecasts, err := s.forecasts.ForecastAll()
+	if err != nil {
+		return nil, err
+	}
+
+	velocity := make(map[string]float64, len(forecasts))
+	for _, f := range forecasts {
+		velocity[f.SKU] = f.ForecastDailyUnits
+	}
+
+	inbound, err := s.purchaseOrders.FindInbound("")
+	if err != nil {
+		return nil, err
+	}
+
+	onOrder := make(map[string]int, len(inbound))
+	for _, item := range inbound {
+		onOrder[item.SKU] = item.Quantity
+	}
+
+	now := time.Now()
+	suggestions := make(map[int64]*entities.ReorderSuggestion)
+	for _, item := range items {
+		line := replenishment.Suggest(item, velocity[item.SKU], onOrder[item.SKU], s.policy)
+		if line.SuggestedQty == 0 {
+			continue
+		}
+
+		suggestion, ok := suggestions[item.SupplierID]
+		if !ok {
+			suggestion = &entities.ReorderSuggestion{GeneratedAt: now}
+			suggestions[item.SupplierID] = suggestion
+		}
+		suggestion.Lines = append(suggestion.Lines, line)
+		suggestion.TotalUnits += line.SuggestedQty
+		total, err := suggestion.TotalCost.Add(line.LineTotal)
+		if err != nil {
+			return nil, fmt.Errorf("sku %s: %w", item.SKU, err)
+		}
+		suggestion.TotalCost = total
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,8 @@
+package replenishment
+
+import "walmart-inventory-manager/internal/entities"
+
+type ReplenishmentService interface {
+	Suggestions() ([]entities.ReorderSuggestion, error)
+	DraftPurchaseOrder(supplierID int64) (*entities.ReorderSuggestion, error)
+}
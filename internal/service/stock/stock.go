@@ -0,0 +1,163 @@
+package stock
+
+import (
+	"fmt"
+	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/repositories/stock"
+)
+
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 500
+	maxBulkChanges      = 500
+)
+
+type StockDefault struct {
+	rp stock.StockRepository
+}
+
+func NewStockDefault(rp stock.StockRepository) *StockDefault {
+	return &StockDefault{rp: rp}
+}
+
+func (s *StockDefault) SetWarehouseStock(sku string, location string, quantity int, reason entities.StockReason, note string) (*entities.StockMovement, error) {
+	if reason == "" {
+		reason = entities.StockReasonCount
+	}
+	return s.applyOne(entities.StockChange{SKU: sku, Location: location, SetTo: &quantity, Reason: reason, Note: note})
+}
+
+func (s *StockDefault) AdjustWarehouseStock(sku string, location string, delta int, reason entities.StockReason, note string) (*entities.StockMovement, error) {
+	return s.applyOne(entities.StockChange{SKU: sku, Location: location, Delta: delta, Reason: reason, Note: note})
+}
+
+func (s *StockDefault) BulkAdjust(changes []entities.StockChange) ([]entities.StockMovement, error) {
+	if len(changes) == 0 {
+		return nil, errors.NewBadRequest("at least one stock change is required")
+	}
+	if len(changes) > maxBulkChanges {
+		return nil, errors.NewBadRequest(fmt.Sprintf("a bulk request accepts at most %d changes", maxBulkChanges))
+	}
+	for i, change := range changes {
+		if err := validateChange(change); err != nil {
+			return nil, errors.NewBadRequest(fmt.Sprintf("change %d: %s", i, err.Error()))
+		}
+	}
+
+	return s.rp.ApplyChanges(changes)
+}
+
+// RecordMovements appends order and return movements reported by other systems.
+// Each one needs a reference ID so that the same event is only counted once.
+func (s *StockDefault) RecordMovements(movements []entities.StockMovement) ([]entities.StockMovement, error) {
+	if len(movements) == 0 {
+		return nil, errors.NewBadRequest("at least one stock movement is required")
+	}
+	if len(movements) > maxBulkChanges {
+		return nil, errors.NewBadRequest(fmt.Sprintf("a bulk request accepts at most %d movements", maxBulkChanges))
+	}
+	for i, m := range movements {
+		if m.SKU == "" {
+			return nil, errors.NewBadRequest(fmt.Sprintf("movement %d: sku is required", i))
+		}
+		if m.Source != entities.MovementSourceOrder && m.Source != entities.MovementSourceReturn {
+			return nil, errors.NewBadRequest(fmt.Sprintf("movement %d: source must be order or return", i))
+		}
+		if m.ReferenceID == "" {
+			return nil, errors.NewBadRequest(fmt.Sprintf("movement %d: referenceId is required", i))
+		}
+		if m.Quantity == 0 {
+			return nil, errors.NewBadRequest(fmt.Sprintf("movement %d: quantity must not be zero", i))
+		}
+		if m.StockType == "" {
+			movements[i].StockType = entities.StockTypeWarehouse
+		} else if !m.StockType.Valid() {
+			return nil, errors.NewBadRequest(fmt.Sprintf("movement %d: invalid stock type %q", i, m.StockType))
+		}
+		movements[i].ID = 0
+		movements[i].ProductID = 0
+		movements[i].Reason = ""
+		movements[i].CreatedAt = time.Time{}
+	}
+
+	return s.rp.RecordMovements(movements)
+}
+
+func (s *StockDefault) Movements(filter entities.MovementFilter) ([]entities.StockMovement, error) {
+	if filter.SKU == "" {
+		return nil, errors.NewBadRequest("sku is required")
+	}
+	if filter.StockType != "" && !filter.StockType.Valid() {
+		return nil, errors.NewBadRequest(fmt.Sprintf("invalid stock type %q", filter.StockType))
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultHistoryLimit
+	}
+	if filter.Limit > maxHistoryLimit {
+		filter.Limit = maxHistoryLimit
+	}
+
+	return s.rp.FindMovements(filter)
+}
+
+func (s *StockDefault) BalanceAt(sku string, at time.Time) (*entities.StockSnapshot, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	snapshot, err := s.rp.BalanceAt(sku, at)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with sku %s not found", sku))
+	}
+
+	return snapshot, nil
+}
+
+func (s *StockDefault) CheckLedger(sku string) (*entities.LedgerCheck, error) {
+	check, err := s.rp.CheckLedger(sku)
+	if err != nil {
+		return nil, err
+	}
+	if check == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with sku %s not found", sku))
+	}
+
+	return check, nil
+}
+
+func (s *StockDefault) applyOne(change entities.StockChange) (*entities.StockMovement, error) {
+	if err := validateChange(change); err != nil {
+		return nil, err
+	}
+
+	movements, err := s.rp.ApplyChanges([]entities.StockChange{change})
+	if err != nil {
+		return nil, err
+	}
+
+	return &movements[0], nil
+}
+
+func validateChange(change entities.StockChange) error {
+	if change.SKU == "" {
+		return errors.NewBadRequest("sku is required")
+	}
+	if !change.Reason.Valid() {
+		return errors.NewBadRequest(fmt.Sprintf("invalid reason %q: must be one of receipt, adjustment, damage, count", change.Reason))
+	}
+	if change.SetTo != nil {
+		if *change.SetTo < 0 {
+			return errors.NewBadRequest("quantity cannot be negative")
+		}
+		return nil
+	}
+	if change.Delta == 0 {
+		return errors.NewBadRequest("delta must not be zero")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package stock
+
+import (
+	"time"
+	"walmart-inventory-manager/internal/entities"
+)
+
+type StockService interface {
+	SetWarehouseStock(sku string, location string, quantity int, reason entities.StockReason, note string) (*entities.StockMovement, error)
+	AdjustWarehouseStock(sku string, location string, delta int, reason entities.StockReason, note string) (*entities.StockMovement, error)
+	BulkAdjust(changes []entities.StockChange) ([]entities.StockMovement, error)
+	RecordMovements(movements []entities.StockMovement) ([]entities.StockMovement, error)
+	Movements(filter entities.MovementFilter) ([]entities.StockMovement, error)
+	BalanceAt(sku string, at time.Time) (*entities.StockSnapshot, error)
+	CheckLedger(sku string) (*entities.LedgerCheck, error)
+}
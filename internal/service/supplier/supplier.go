@@ -0,0 +1,117 @@
+package supplier
+
+import (
+	"fmt"
+	"strings"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/repositories/inventory"
+	"walmart-inventory-manager/internal/repositories/supplier"
+)
+
+type SupplierDefault struct {
+	rp        supplier.SupplierRepository
+	inventory inventory.InventoryRepository
+}
+
+func NewSupplierDefault(rp supplier.SupplierRepository, inventory inventory.InventoryRepository) *SupplierDefault {
+	return &SupplierDefault{rp: rp, inventory: inventory}
+}
+
+func (s *SupplierDefault) FindAll() ([]entities.Supplier, error) {
+	return s.rp.FindAll()
+}
+
+func (s *SupplierDefault) FindByID(id int64) (*entities.Supplier, error) {
+	supplier, err := s.rp.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("supplier %d not found", id))
+	}
+
+	return supplier, nil
+}
+
+func (s *SupplierDefault) Create(supplier entities.Supplier) (*entities.Supplier, error) {
+	if err := validateSupplier(&supplier); err != nil {
+		return nil, err
+	}
+
+	supplier.Active = true
+	id, err := s.rp.Create(supplier)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(id)
+}
+
+func (s *SupplierDefault) Update(id int64, changes entities.Supplier) (*entities.Supplier, error) {
+	if _, err := s.FindByID(id); err != nil {
+		return nil, err
+	}
+
+	changes.ID = id
+	if err := validateSupplier(&changes); err != nil {
+		return nil, err
+	}
+
+	if err := s.rp.Update(changes); err != nil {
+		return nil, err
+	}
+
+	return s.FindByID(id)
+}
+
+// AssignProduct sets who a SKU is bought from and at what terms. A nil supplier
+// unlinks the SKU, which removes it from reorder suggestions.
+func (s *SupplierDefault) AssignProduct(sku string, supply entities.SupplySettings) (*entities.Product, error) {
+	product, err := s.inventory.GetProductBySKU(sku)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.NewResourceNotFound(fmt.Sprintf("product with SKU %s not found", sku))
+	}
+
+	if supply.SupplierID != nil {
+		if _, err := s.FindByID(*supply.SupplierID); err != nil {
+			return nil, err
+		}
+	}
+	if supply.ProductCost != nil && supply.ProductCost.IsNegative() {
+		return nil, errors.NewBadRequest("productCost cannot be negative")
+	}
+	if supply.LeadTimeDays != nil && *supply.LeadTimeDays < 0 {
+		return nil, errors.NewBadRequest("leadTimeDays cannot be negative")
+	}
+	if supply.MinOrderQty != nil && *supply.MinOrderQty < 1 {
+		return nil, errors.NewBadRequest("minOrderQty must be at least 1")
+	}
+	supply.SupplierItemNumber = strings.TrimSpace(supply.SupplierItemNumber)
+
+	if err := s.inventory.UpdateSupplySettings(product.ID, supply); err != nil {
+		return nil, err
+	}
+
+	return s.inventory.GetProductBySKU(sku)
+}
+
+func validateSupplier(supplier *entities.Supplier) error {
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	if supplier.Name == "" {
+		return errors.NewBadRequest("name is required")
+	}
+	if supplier.LeadTimeDays < 0 {
+		return errors.NewBadRequest("leadTimeDays cannot be negative")
+	}
+	if supplier.MinOrderQty == 0 {
+		supplier.MinOrderQty = 1
+	}
+	if supplier.MinOrderQty < 1 {
+		return errors.NewBadRequest("minOrderQty must be at least 1")
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package supplier
+
+import "walmart-inventory-manager/internal/entities"
+
+type SupplierService interface {
+	FindAll() ([]entities.Supplier, error)
+	FindByID(id int64) (*entities.Supplier, error)
+	Create(supplier entities.Supplier) (*entities.Supplier, error)
+	Update(id int64, supplier entities.Supplier) (*entities.Supplier, error)
+	AssignProduct(sku string, supply entities.SupplySettings) (*entities.Product, error)
+}
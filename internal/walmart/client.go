@@ -6,13 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
-	"os"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"walmart-inventory-manager/internal/entities"
+	"walmart-inventory-manager/internal/imagematch"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/metrics"
+	"walmart-inventory-manager/internal/money"
 
 	"github.com/google/uuid"
 )
@@ -26,6 +30,12 @@ type Client struct {
 	accessToken   string
 	expiresAt     time.Time
 	mutex         sync.Mutex
+	logger        *slog.Logger
+
+	// token mirrors the cached token for TokenState under a lock of its own,
+	// so reading it never waits on a token request in flight.
+	tokenMutex sync.Mutex
+	token      entities.TokenState
 }
 
 var (
@@ -33,24 +43,28 @@ var (
 	once     sync.Once
 )
 
-func GetInstance() (*Client, error) {
+// Credentials identify the seller to the Walmart Marketplace API.
+type Credentials struct {
+	PartnerID    string
+	ClientID     string
+	ClientSecret string
+}
+
+func GetInstance(credentials Credentials, logger *slog.Logger) (*Client, error) {
 	var initErr error
 	once.Do(func() {
-		partnerID := os.Getenv("WM_PARTNER_ID")
-		clientID := os.Getenv("WALMART_CLIENT_ID")
-		clientSecret := os.Getenv("WALMART_CLIENT_SECRET")
-
-		if partnerID == "" || clientID == "" || clientSecret == "" {
-			initErr = errors.New("missing required environment variables")
+		if credentials.PartnerID == "" || credentials.ClientID == "" || credentials.ClientSecret == "" {
+			initErr = errors.New("missing Walmart credentials")
 			return
 		}
 
 		instance = &Client{
-			partnerID:     partnerID,
+			partnerID:     credentials.PartnerID,
 			correlationID: generateCorrelationID(),
 			serviceName:   "Walmart Marketplace",
-			clientID:      clientID,
-			clientSecret:  clientSecret,
+			clientID:      credentials.ClientID,
+			clientSecret:  credentials.ClientSecret,
+			logger:        logger.With(slog.String("component", "walmart_client")),
 		}
 	})
 
@@ -61,8 +75,8 @@ func GetInstance() (*Client, error) {
 	return instance, nil
 }
 
-func NewClient() (*Client, error) {
-	return GetInstance()
+func NewClient(credentials Credentials, logger *slog.Logger) (*Client, error) {
+	return GetInstance(credentials, logger)
 }
 
 type tokenResponse struct {
@@ -74,10 +88,45 @@ type apiResponse struct {
 	ItemResponse []interface{} `json:"ItemResponse"`
 }
 
+// Endpoint names that label the Walmart API metrics.
+const (
+	endpointToken      = "token"
+	endpointItems      = "items"
+	endpointInventory  = "inventory"
+	endpointItemSearch = "item_search"
+	endpointOrders     = "orders"
+)
+
 func generateCorrelationID() string {
 	return uuid.New().String()
 }
 
+// correlate gives a Walmart API call its correlation ID and logs it, so the
+// call can be traced with Walmart support.
+func (c *Client) correlate(req *http.Request) {
+	correlationID := generateCorrelationID()
+	req.Header.Set("WM_QOS.CORRELATION_ID", correlationID)
+	c.logger.Debug("calling Walmart API",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.String(logging.KeyCorrelationID, correlationID),
+	)
+}
+
+// do sends a request to a Walmart endpoint and records its status and latency.
+func (c *Client) do(httpClient *http.Client, endpoint string, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	metrics.ObserveWalmartRequest(endpoint, status, time.Since(start))
+
+	return resp, err
+}
+
 func (c *Client) GetAccessToken() (string, int64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -89,15 +138,31 @@ func (c *Client) GetAccessToken() (string, int64, error) {
 
 	token, err := c.requestToken()
 	if err != nil {
+		c.setTokenState(entities.TokenState{ExpiresAt: c.expiresAt, LastError: err})
 		return "", 0, err
 	}
 
 	c.accessToken = token.AccessToken
 	c.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	c.setTokenState(entities.TokenState{ExpiresAt: c.expiresAt})
 
 	return c.accessToken, token.ExpiresIn, nil
 }
 
+// TokenState reports when the cached token expires and whether the last
+// attempt to get one failed. It never calls Walmart.
+func (c *Client) TokenState() entities.TokenState {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	return c.token
+}
+
+func (c *Client) setTokenState(state entities.TokenState) {
+	c.tokenMutex.Lock()
+	c.token = state
+	c.tokenMutex.Unlock()
+}
+
 func (c *Client) requestToken() (*tokenResponse, error) {
 	urlEndpoint := "https://marketplace.walmartapis.com/v3/token"
 
@@ -115,11 +180,11 @@ func (c *Client) requestToken() (*tokenResponse, error) {
 	req.Header.Set("Authorization", "Basic "+auth)
 	req.Header.Set("WM_PARTNER.ID", c.partnerID)
 	req.Header.Set("WM_SVC.NAME", "Walmart Marketplace")
-	req.Header.Set("WM_QOS.CORRELATION_ID", generateCorrelationID())
+	c.correlate(req)
 	req.Header.Set("Accept", "application/json")
 
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := c.do(client, endpointToken, req)
 	if err != nil {
 		return nil, err
 	}
@@ -161,11 +226,11 @@ func (c *Client) FetchWalmartItems() (map[string]map[string]interface{}, error)
 
 		req.Header.Set("Accept", "application/json")
 		req.Header.Set("WM_SEC.ACCESS_TOKEN", accessToken)
-		req.Header.Set("WM_QOS.CORRELATION_ID", generateCorrelationID())
+		c.correlate(req)
 		req.Header.Set("WM_SVC.NAME", "Walmart Marketplace")
 
 		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
+		resp, err := c.do(client, endpointItems, req)
 		if err != nil {
 			if strings.Contains(err.Error(), "context deadline exceeded") {
 				return nil, fmt.Errorf("request timed out after 30 seconds: %w", err)
@@ -218,7 +283,8 @@ func (c *Client) FetchWalmartItems() (map[string]map[string]interface{}, error)
 
 			if price, exists := item["price"].(map[string]interface{}); exists {
 				if amount, ok := price["amount"].(float64); ok {
-					product["price"] = amount
+					currency, _ := price["currency"].(string)
+					product["price"] = money.FromFloat(amount, currency)
 				}
 			}
 
@@ -235,110 +301,9 @@ func (c *Client) FetchWalmartItems() (map[string]map[string]interface{}, error)
 	return productMap, nil
 }
 
-func FetchWalmartOrderStats(client *Client) ([]OrderStats, error) {
-	accessToken, _, err := client.GetAccessToken()
-	if err != nil {
-		return nil, err
-	}
-
-	baseURL := "https://marketplace.walmartapis.com/v3/orders?sku=00037000949619&createdStartDate=2025-05-01&createdEndDate=2025-05-06&productInfo=true&shipNodeType=WFSFulfilled"
-
-	type skuData struct {
-		ProductName string
-		OrderCount  int
-		Units       int
-	}
-	skuMap := make(map[string]skuData)
-
-	var nextCursor string
-
-	for {
-		urlEndpoint := baseURL
-		if nextCursor != "" {
-			urlEndpoint += "&nextCursor=" + url.QueryEscape(nextCursor)
-		}
-
-		req, _ := http.NewRequest("GET", urlEndpoint, nil)
-		req.Header.Set("Accept", "application/json")
-		req.Header.Set("WM_SEC.ACCESS_TOKEN", accessToken)
-		req.Header.Set("WM_QOS.CORRELATION_ID", generateCorrelationID())
-		req.Header.Set("WM_SVC.NAME", "Walmart Marketplace")
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return nil, err
-		}
-		defer resp.Body.Close()
-
-		body, _ := io.ReadAll(resp.Body)
-		if resp.StatusCode != http.StatusOK {
-			return nil, errors.New("failed to fetch orders: " + string(body))
-		}
-
-		var data struct {
-			List struct {
-				Meta struct {
-					NextCursor string `json:"nextCursor"`
-				} `json:"meta"`
-				Elements struct {
-					Order []struct {
-						OrderLines struct {
-							OrderLine []struct {
-								Item struct {
-									Sku         string `json:"sku"`
-									ProductName string `json:"productName"`
-								} `json:"item"`
-								OrderLineQuantity struct {
-									Amount string `json:"amount"`
-								} `json:"orderLineQuantity"`
-							} `json:"orderLine"`
-						} `json:"orderLines"`
-					} `json:"order"`
-				} `json:"elements"`
-			} `json:"list"`
-		}
-
-		if err := json.Unmarshal(body, &data); err != nil {
-			return nil, err
-		}
-
-		for _, order := range data.List.Elements.Order {
-			for _, line := range order.OrderLines.OrderLine {
-				sku := line.Item.Sku
-				name := line.Item.ProductName
-
-				amount, err := strconv.Atoi(line.OrderLineQuantity.Amount)
-				if err != nil {
-					amount = 0
-				}
-
-				entry := skuMap[sku]
-				entry.ProductName = name
-				entry.OrderCount++
-				entry.Units += amount
-				skuMap[sku] = entry
-			}
-		}
-
-		if data.List.Meta.NextCursor == "" {
-			break
-		}
-		nextCursor = data.List.Meta.NextCursor
-	}
-
-	var result []OrderStats
-	for sku, data := range skuMap {
-		result = append(result, OrderStats{
-			SKU:         sku,
-			ProductName: data.ProductName,
-			OrderCount:  data.OrderCount,
-			UnitsSold:   data.Units,
-		})
-	}
-	return result, nil
-}
-
-func (c *Client) FetchWalmartInventory() (map[string]int, error) {
+// FetchWalmartInventory returns the available-to-sell quantity of every SKU
+// broken down by ship node.
+func (c *Client) FetchWalmartInventory() (map[string]map[string]int, error) {
 	accessToken, _, err := c.GetAccessToken()
 	if err != nil {
 		return nil, errors.New("failed to get access token: " + err.Error())
@@ -353,11 +318,11 @@ func (c *Client) FetchWalmartInventory() (map[string]int, error) {
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("WM_SEC.ACCESS_TOKEN", accessToken)
-	req.Header.Set("WM_QOS.CORRELATION_ID", generateCorrelationID())
+	c.correlate(req)
 	req.Header.Set("WM_SVC.NAME", "Walmart Marketplace")
 
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := c.do(client, endpointInventory, req)
 	if err != nil {
 		if strings.Contains(err.Error(), "context deadline exceeded") {
 			return nil, fmt.Errorf("request timed out after 30 seconds: %w", err)
@@ -371,8 +336,6 @@ func (c *Client) FetchWalmartInventory() (map[string]int, error) {
 		return nil, errors.New("failed to fetch inventory: " + string(body))
 	}
 
-	fmt.Println("Raw Inventory API Response:", string(body))
-
 	var apiResp map[string]interface{}
 	if err := json.Unmarshal(body, &apiResp); err != nil {
 		return nil, err
@@ -388,7 +351,7 @@ func (c *Client) FetchWalmartInventory() (map[string]int, error) {
 		return nil, errors.New("missing 'inventory' in API response")
 	}
 
-	inventoryMap := make(map[string]int)
+	inventoryMap := make(map[string]map[string]int)
 
 	for _, rawInv := range inventoryList {
 		inv, ok := rawInv.(map[string]interface{})
@@ -401,81 +364,137 @@ func (c *Client) FetchWalmartInventory() (map[string]int, error) {
 			continue
 		}
 
-		totalQty := 0
+		nodes := make(map[string]int)
 		if shipNodes, exists := inv["shipNodes"].([]interface{}); exists {
 			for _, node := range shipNodes {
 				if shipNode, ok := node.(map[string]interface{}); ok {
 					if qty, ok := shipNode["availToSellQty"].(float64); ok {
-						totalQty += int(qty)
+						nodes[shipNodeCode(shipNode)] += int(qty)
 					}
 				}
 			}
 		}
 
-		inventoryMap[sku] = totalQty
+		inventoryMap[sku] = nodes
 	}
 
-	fmt.Println("Processed Inventory Data:", inventoryMap)
+	c.logger.Debug("fetched Walmart inventory", slog.Int("skus", len(inventoryMap)))
 	return inventoryMap, nil
 }
 
-func (c *Client) ItemSearch(productName, upc, gtin string) (string, error) {
-	accessToken, _, err := c.GetAccessToken()
-	if err != nil {
-		return "", errors.New("failed to get access token: " + err.Error())
+// shipNodeCode identifies a ship node entry. The fulfillment inventory API
+// reports the node type; a node ID is used when one is present.
+func shipNodeCode(shipNode map[string]interface{}) string {
+	for _, key := range []string{"shipNode", "shipNodeId", "shipNodeType"} {
+		if code, ok := shipNode[key].(string); ok && code != "" {
+			return code
+		}
 	}
+	return "UNKNOWN"
+}
 
-	queryParams := url.Values{}
+// TotalQuantity sums the quantities of all ship nodes.
+func TotalQuantity(nodes map[string]int) int {
+	total := 0
+	for _, qty := range nodes {
+		total += qty
+	}
+	return total
+}
+
+// ItemSearch looks the product up in the Walmart catalog and returns the best
+// matching item with its images and how confident the match is, or nil when
+// no item with images came back. Products with a barcode are searched by it
+// first; when that finds no item with the same code, the catalog is searched
+// by product name as well and the titles are scored.
+func (c *Client) ItemSearch(productName, upc, gtin string) (*entities.ImageMatch, error) {
+	query := imagematch.Query{ProductName: productName, UPC: upc, GTIN: gtin}
+
+	codeParams := url.Values{}
 	if gtin != "" {
-		queryParams.Set("gtin", gtin)
+		codeParams.Set("gtin", gtin)
 	} else if upc != "" {
-		queryParams.Set("upc", upc)
-	} else if productName != "" {
-		queryParams.Set("query", productName)
-	} else {
-		return "", errors.New("no query parameter provided")
+		codeParams.Set("upc", upc)
+	}
+	if len(codeParams) == 0 && productName == "" {
+		return nil, errors.New("no query parameter provided")
 	}
 
-	urlEndpoint := "https://marketplace.walmartapis.com/v3/items/walmart/search?" + queryParams.Encode()
+	var match *entities.ImageMatch
+	if len(codeParams) > 0 {
+		candidates, err := c.searchItems(codeParams)
+		if err != nil {
+			return nil, err
+		}
+		match = imagematch.Best(query, candidates)
+	}
 
-	// 📌 LOG DE LA URL Y PARAMETROS
-	fmt.Println("🟡 ItemSearch - Querying Walmart API with:")
-	fmt.Println("🔹 productName:", productName)
-	fmt.Println("🔹 upc:", upc)
-	fmt.Println("🔹 gtin:", gtin)
-	fmt.Println("🔹 URL:", urlEndpoint)
+	if productName != "" && (match == nil || match.Method == entities.ImageMatchTitle) {
+		candidates, err := c.searchItems(url.Values{"query": {productName}})
+		if err != nil {
+			return nil, err
+		}
+		if byTitle := imagematch.Best(query, candidates); byTitle != nil && (match == nil || byTitle.Score > match.Score) {
+			match = byTitle
+		}
+	}
+
+	if match != nil {
+		c.logger.Debug("matched Walmart catalog item",
+			slog.String("product_name", productName),
+			slog.String("item_id", match.ItemID),
+			slog.String("method", string(match.Method)),
+			slog.Float64("score", match.Score),
+		)
+	}
+
+	return match, nil
+}
+
+// searchItems runs one catalog search and returns the items it found.
+func (c *Client) searchItems(queryParams url.Values) ([]imagematch.Candidate, error) {
+	accessToken, _, err := c.GetAccessToken()
+	if err != nil {
+		return nil, errors.New("failed to get access token: " + err.Error())
+	}
+
+	urlEndpoint := "https://marketplace.walmartapis.com/v3/items/walmart/search?" + queryParams.Encode()
 
 	req, err := http.NewRequest("GET", urlEndpoint, nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("WM_SEC.ACCESS_TOKEN", accessToken)
-	req.Header.Set("WM_QOS.CORRELATION_ID", generateCorrelationID())
+	c.correlate(req)
 	req.Header.Set("WM_SVC.NAME", "Walmart Marketplace")
 
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := c.do(client, endpointItemSearch, req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	// 🔍 Leer el cuerpo para logging
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	// A search that finds nothing may answer 404 rather than an empty list.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("item search failed with status %d: %s", resp.StatusCode, bodyBytes)
 	}
 
-	// LOG DEL CUERPO COMPLETO DE LA RESPUESTA
-	fmt.Println("🔵 Walmart API Response:")
-	fmt.Println(string(bodyBytes))
-
-	// Volver a leer desde buffer para decodificar
 	var result struct {
 		Items []struct {
+			ItemID string `json:"itemId"`
 			Title  string `json:"title"`
+			UPC    string `json:"upc"`
+			GTIN   string `json:"gtin"`
 			Images []struct {
 				URL string `json:"url"`
 			} `json:"images"`
@@ -483,18 +502,19 @@ func (c *Client) ItemSearch(productName, upc, gtin string) (string, error) {
 	}
 
 	if err := json.Unmarshal(bodyBytes, &result); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	// 🧠 Coincidencia de título
+	candidates := make([]imagematch.Candidate, 0, len(result.Items))
 	for _, item := range result.Items {
-		if strings.EqualFold(item.Title, productName) && len(item.Images) > 0 {
-			fmt.Println("✅ Imagen encontrada para:", item.Title)
-			fmt.Println("🖼️ URL de la imagen:", item.Images[0].URL) // 🔍 Aquí se imprime la URL
-
-			return item.Images[0].URL, nil
+		candidate := imagematch.Candidate{ItemID: item.ItemID, Title: item.Title, UPC: item.UPC, GTIN: item.GTIN}
+		for _, image := range item.Images {
+			if image.URL != "" {
+				candidate.ImageURLs = append(candidate.ImageURLs, image.URL)
+			}
 		}
+		candidates = append(candidates, candidate)
 	}
 
-	return "", errors.New("no matching image found")
+	return candidates, nil
 }
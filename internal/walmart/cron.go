@@ -2,127 +2,292 @@ package walmart
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"time"
 
+	"walmart-inventory-manager/internal/barcode"
 	"walmart-inventory-manager/internal/entities"
+	appErrors "walmart-inventory-manager/internal/errors"
+	"walmart-inventory-manager/internal/listing"
+	"walmart-inventory-manager/internal/logging"
+	"walmart-inventory-manager/internal/metrics"
+	"walmart-inventory-manager/internal/money"
 	"walmart-inventory-manager/internal/repositories/inventory"
+	listingRepository "walmart-inventory-manager/internal/repositories/listing"
+	"walmart-inventory-manager/internal/repositories/sales"
+	"walmart-inventory-manager/internal/repositories/stock"
+	imageService "walmart-inventory-manager/internal/service/image"
+
+	"github.com/google/uuid"
 )
 
-type OrderStats struct {
-	SKU         string `json:"sku"`
-	ProductName string `json:"productName"`
-	OrderCount  int    `json:"orderCount"`
-	UnitsSold   int    `json:"unitsSold"`
+// salesHistoryDays is how far back the first sales sync reaches.
+const salesHistoryDays = 90
+
+// JobRecorder keeps track of the background jobs: when each runs next and
+// how its runs ended.
+type JobRecorder interface {
+	Scheduled(job string, next time.Time)
+	Finished(run entities.JobRun)
 }
 
-func OrdersCronjob(client *Client, repo inventory.InventoryRepository) {
-	go func() {
-		for {
-			location, err := time.LoadLocation("America/Argentina/Buenos_Aires")
-			if err != nil {
-				log.Println("[OrdersCronjob] Error loading timezone:", err)
-				location = time.UTC
-			}
+// finishRun reports a finished job run to the metrics and the recorder and
+// returns how long it took. A nil error means the run succeeded.
+func finishRun(jobs JobRecorder, job, runID string, startedAt time.Time, err error) time.Duration {
+	run := entities.JobRun{
+		Job:        job,
+		RunID:      runID,
+		Result:     entities.JobResultSuccess,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+	}
+	if err != nil {
+		run.Result = entities.JobResultFailure
+		run.Error = err.Error()
+	}
 
-			now := time.Now().In(location)
-			nextRun := time.Date(now.Year(), now.Month(), now.Day(), 23, 40, 0, 0, location)
-			if now.After(nextRun) {
-				nextRun = nextRun.Add(24 * time.Hour)
-			}
+	duration := run.FinishedAt.Sub(run.StartedAt)
+	metrics.ObserveSync(job, string(run.Result), duration)
+	jobs.Finished(run)
+
+	return duration
+}
+
+// OrdersCronjob syncs the daily sales on the schedule. Sales days are counted
+// in the schedule's time zone.
+func OrdersCronjob(client *Client, salesRepo sales.SalesRepository, schedule entities.DailySchedule, logger *slog.Logger, jobs JobRecorder, hooks ...SyncHook) {
+	logger = logger.With(slog.String(logging.KeyJob, entities.JobOrdersSync))
 
+	go func() {
+		for {
+			nextRun := schedule.Next(time.Now())
 			sleepDuration := time.Until(nextRun)
-			log.Printf("[OrdersCronjob] Now: %s | Sleeping until: %s (duration: %s)\n", now.Format(time.RFC1123), nextRun.Format(time.RFC1123), sleepDuration)
+			logger.Info("next orders sync scheduled", slog.Time("next_run", nextRun), slog.Duration("sleep", sleepDuration))
+			jobs.Scheduled(entities.JobOrdersSync, nextRun)
 
 			time.Sleep(sleepDuration)
 
 			start := time.Now()
-			log.Println("[OrdersCronjob] Starting Walmart orders fetch...")
+			runID := uuid.New().String()
+			runLogger := logger.With(slog.String(logging.KeyRunID, runID))
+			runLogger.Info("orders sync started")
 
-			stats, err := FetchWalmartOrderStats(client)
+			days, err := syncDailySales(client, salesRepo, schedule.Location, runLogger)
 			if err != nil {
-				log.Printf("[OrdersCronjob] Error fetching Walmart orders: %v\n", err)
+				runLogger.Error("error syncing Walmart daily sales", logging.Err(err))
+				finishRun(jobs, entities.JobOrdersSync, runID, start, err)
 				continue
 			}
 
-			log.Printf("[OrdersCronjob] Successfully fetched %d SKUs\n", len(stats))
+			duration := finishRun(jobs, entities.JobOrdersSync, runID, start, nil)
+			runLogger.Info("orders sync finished", slog.Int("sku_days", days), slog.Duration("duration", duration))
 
-			jsonBytes, err := json.MarshalIndent(stats, "", "  ")
-			if err != nil {
-				log.Printf("[OrdersCronjob] Error marshaling stats to JSON: %v\n", err)
-				continue
+			for _, hook := range hooks {
+				hook(runID)
 			}
+		}
+	}()
+}
 
-			log.Println("[OrdersCronjob] JSON output:")
-			fmt.Println(string(jsonBytes))
+// syncDailySales fetches the orders placed since shortly before the last stored
+// sales day (or the full history on the first run) and stores them as daily
+// units per SKU. The overlap picks up late cancellations.
+func syncDailySales(client *Client, salesRepo sales.SalesRepository, location *time.Location, logger *slog.Logger) (int, error) {
+	now := time.Now().In(location)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	from := to.AddDate(0, 0, -salesHistoryDays)
+
+	last, err := salesRepo.LastSaleDate()
+	if err != nil {
+		return 0, err
+	}
+	if last != nil {
+		overlap := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, location).AddDate(0, 0, -2)
+		if overlap.After(from) {
+			from = overlap
+		}
+	}
 
-			log.Printf("[OrdersCronjob] Finished inserting Walmart Orders. Duration: %s\n", time.Since(start))
+	type saleKey struct {
+		sku  string
+		date string
+	}
+	totals := make(map[saleKey]*entities.DailySales)
+
+	for _, shipNodeType := range []string{ShipNodeTypeSellerFulfilled, ShipNodeTypeWFSFulfilled} {
+		orders, err := FetchWalmartOrders(client, from, to, shipNodeType)
+		if err != nil {
+			return 0, fmt.Errorf("fetching %s orders: %w", shipNodeType, err)
 		}
-	}()
+
+		for _, order := range orders {
+			orderDate := order.Date().In(location)
+			day := time.Date(orderDate.Year(), orderDate.Month(), orderDate.Day(), 0, 0, 0, 0, time.UTC)
+			for _, line := range order.OrderLines.OrderLine {
+				key := saleKey{line.Item.SKU, day.Format(time.DateOnly)}
+				entry, ok := totals[key]
+				if !ok {
+					entry = &entities.DailySales{SKU: line.Item.SKU, Date: day}
+					totals[key] = entry
+				}
+				entry.Units += line.Units()
+				if shipNodeType == ShipNodeTypeWFSFulfilled {
+					entry.WFSUnits += line.Units()
+				}
+				entry.Orders++
+				revenue, err := line.Revenue()
+				if err == nil {
+					revenue, err = entry.Revenue.Add(revenue)
+				}
+				if err != nil {
+					logger.Warn("skipping order line revenue",
+						slog.String("purchase_order_id", order.PurchaseOrderID),
+						slog.String(logging.KeySKU, line.Item.SKU),
+						logging.Err(err),
+					)
+					continue
+				}
+				entry.Revenue = revenue
+			}
+		}
+	}
+
+	dailySales := make([]entities.DailySales, 0, len(totals))
+	for _, entry := range totals {
+		dailySales = append(dailySales, *entry)
+	}
+
+	if err := salesRepo.ReplaceDailySales(from, to, dailySales); err != nil {
+		return 0, err
+	}
+
+	return len(dailySales), nil
 }
 
-func StartCronJob(client *Client, repo inventory.InventoryRepository) {
+// ImageBackfillJob looks up missing product images in the background, one
+// batch per interval, so the catalog sync never waits on image searches. A
+// zero interval disables the job; the backfill can still be run through the API.
+func ImageBackfillJob(images imageService.ImageService, interval time.Duration, logger *slog.Logger, jobs JobRecorder) {
+	logger = logger.With(slog.String(logging.KeyJob, entities.JobImageBackfill))
+	if interval <= 0 {
+		logger.Info("image backfill job disabled")
+		return
+	}
+
 	go func() {
-		for {
-			now := time.Now()
-			location, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		jobs.Scheduled(entities.JobImageBackfill, time.Now().Add(interval))
+
+		for range ticker.C {
+			start := time.Now()
+			runID := uuid.New().String()
+			run, err := images.RunBackfill()
+			jobs.Scheduled(entities.JobImageBackfill, time.Now().Add(interval))
+			// A run started by hand is still going; this tick did not run at all.
+			var conflict appErrors.Conflict
+			if errors.As(err, &conflict) {
+				logger.Info("image backfill skipped, a run is already in progress", slog.String(logging.KeyRunID, runID))
+				continue
+			}
+			finishRun(jobs, entities.JobImageBackfill, runID, start, err)
 			if err != nil {
-				log.Println("Error loading timezone:", err)
-				location = time.UTC
+				logger.Error("error running image backfill", slog.String(logging.KeyRunID, runID), logging.Err(err))
+				continue
 			}
 
-			nextRun := time.Date(now.Year(), now.Month(), now.Day(), 15, 25, 0, 0, location)
-
-			if now.After(nextRun) {
-				nextRun = nextRun.Add(24 * time.Hour)
+			metrics.CountSKUs(entities.JobImageBackfill, "matched", run.Matched)
+			metrics.CountSKUs(entities.JobImageBackfill, "review", run.Review)
+			metrics.CountSKUs(entities.JobImageBackfill, "retried", run.Retried)
+			metrics.CountSKUs(entities.JobImageBackfill, "exhausted", run.Exhausted)
+			if run.Processed > 0 || run.Enqueued > 0 {
+				logger.Info("image backfill finished",
+					slog.String(logging.KeyRunID, runID),
+					slog.Int64("enqueued", run.Enqueued),
+					slog.Int("processed", run.Processed),
+					slog.Int("matched", run.Matched),
+					slog.Int("review", run.Review),
+					slog.Int("retried", run.Retried),
+					slog.Int("exhausted", run.Exhausted),
+					slog.Int("images_added", run.ImagesAdded),
+					slog.Duration("duration", run.FinishedAt.Sub(run.StartedAt)),
+				)
 			}
+		}
+	}()
+}
+
+// SyncHook runs after every completed sync with the ID of the sync run.
+type SyncHook func(runID string)
 
+func StartCronJob(client *Client, repo inventory.InventoryRepository, ledger stock.StockRepository, listings listingRepository.ListingRepository, delistPolicy entities.DelistPolicy, schedule entities.DailySchedule, logger *slog.Logger, jobs JobRecorder, hooks ...SyncHook) {
+	logger = logger.With(slog.String(logging.KeyJob, entities.JobCatalogSync))
+
+	go func() {
+		for {
+			nextRun := schedule.Next(time.Now())
 			durationUntilNextRun := time.Until(nextRun)
-			log.Printf("Next Walmart Items Fetch Job at: %s\n", nextRun)
+			logger.Info("next catalog sync scheduled", slog.Time("next_run", nextRun))
+			jobs.Scheduled(entities.JobCatalogSync, nextRun)
 
 			time.Sleep(durationUntilNextRun)
 
-			log.Println("Running Walmart Items Fetch Job...")
+			start := time.Now()
+			runID := uuid.New().String()
+			runLogger := logger.With(slog.String(logging.KeyRunID, runID))
+			runLogger.Info("catalog sync started")
+
+			fail := func(msg string, err error) {
+				runLogger.Error(msg, logging.Err(err))
+				finishRun(jobs, entities.JobCatalogSync, runID, start, fmt.Errorf("%s: %w", msg, err))
+			}
 
-			productsMap, err := fetchWalmartItemsWithRetry(client, 3)
+			productsMap, err := fetchWalmartItemsWithRetry(client, 3, runLogger)
 			if err != nil {
-				log.Printf("Error fetching Walmart items after retries: %v\n", err)
+				fail("error fetching Walmart items after retries", err)
 				continue
 			}
 
-			inventoryMap, err := fetchWalmartInventoryWithRetry(client, 3)
+			inventoryMap, err := fetchWalmartInventoryWithRetry(client, 3, runLogger)
 			if err != nil {
-				log.Printf("Error fetching Walmart inventory after retries: %v\n", err)
+				fail("error fetching Walmart inventory after retries", err)
 				continue
 			}
 
 			// Create inventory stats JSON
 			inventoryStats := make(map[string]int)
-			for sku, qty := range inventoryMap {
-				inventoryStats[sku] = qty
+			for sku, nodes := range inventoryMap {
+				inventoryStats[sku] = TotalQuantity(nodes)
 			}
 
 			// Marshal inventory stats
 			inventoryJSON, err := json.MarshalIndent(inventoryStats, "", "  ")
 			if err != nil {
-				log.Printf("Error marshaling inventory stats to JSON: %v\n", err)
+				fail("error marshaling inventory stats to JSON", err)
 				continue
 			}
 
 			// Save to file in root directory
 			err = os.WriteFile("inventory_stats.json", inventoryJSON, 0644)
 			if err != nil {
-				log.Printf("Error writing inventory stats to file: %v\n", err)
+				fail("error writing inventory stats to file", err)
 				continue
 			}
 
-			log.Println("Inventory stats saved to inventory_stats.json")
+			runLogger.Debug("inventory stats saved", slog.String("file", "inventory_stats.json"))
+
+			listingRules, err := listings.FindRules()
+			if err != nil {
+				runLogger.Warn("error loading listing status rules, using defaults", logging.Err(err))
+				listingRules = nil
+			}
 
 			dbProducts, err := repo.FindAll()
 			if err != nil {
-				log.Printf("Error fetching products from DB: %v\n", err)
+				fail("error fetching products from DB", err)
 				continue
 			}
 
@@ -135,46 +300,55 @@ func StartCronJob(client *Client, repo inventory.InventoryRepository) {
 			errorCount := 0
 			updateCount := 0
 			insertCount := 0
+			missingCount := 0
+			listedOutOfStock := 0
+			noQuantity := 0
 
 			for sku, productData := range productsMap {
+				skuLogger := runLogger.With(slog.String(logging.KeySKU, sku))
+
 				// Get available quantity from inventory data
-				availableQty := 0
-				if availToSellQty, exists := inventoryMap[sku]; exists {
-					availableQty = availToSellQty
-				}
+				shipNodes := inventoryMap[sku]
+				availableQty := TotalQuantity(shipNodes)
 
 				lifecycleStatus := getStringValue(productData, "lifecycleStatus")
 				availability := getStringValue(productData, "availability")
 				publishedStatus := getStringValue(productData, "publishedStatus")
 
-				var listingStatusID int
-				if lifecycleStatus == "ACTIVE" && availability == "In_stock" && publishedStatus == "PUBLISHED" {
-					listingStatusID = 1
-				} else if lifecycleStatus == "ACTIVE" && availability == "Out_of_stock" && publishedStatus == "PUBLISHED" {
-					listingStatusID = 2
-				} else if lifecycleStatus == "ARCHIVED" || (publishedStatus == "UNPUBLISHED" && lifecycleStatus == "ACTIVE") || (publishedStatus == "SYSTEM_PROBLEM" && lifecycleStatus == "ACTIVE") {
-					listingStatusID = 3
+				listingStatus, listingReason := listing.Resolve(listingRules, listing.Observation{
+					LifecycleStatus: lifecycleStatus,
+					PublishedStatus: publishedStatus,
+					Availability:    availability,
+				})
+				if listingStatus == entities.ListingStatusUnknown {
+					skuLogger.Warn("listing status is unknown", slog.String("reason", listingReason))
+				}
+				if listingStatus == entities.ListingStatusOutOfStock {
+					listedOutOfStock++
+				}
+				if availableQty <= 0 {
+					noQuantity++
 				}
 
 				// Create product structure with combined data
 				product := entities.Product{
 					SKU:                sku,
-					UPC:                getStringValue(productData, "upc"),
+					UPC:                barcode.NormalizeUPC(getStringValue(productData, "upc")),
 					ProductName:        getStringValue(productData, "productName"),
-					Price:              getFloatValue(productData, "price"),
+					Price:              getMoneyValue(productData, "price"),
 					AvailableToSellQTY: availableQty,
-					GTIN:               getStringValue(productData, "gtin"),
+					GTIN:               barcode.NormalizeGTIN(getStringValue(productData, "gtin")),
 					WPID:               getStringValue(productData, "wpid"),
 					Availability:       availability,
-					PublishedStatus:    getStringValue(productData, "publishedStatus"),
+					PublishedStatus:    publishedStatus,
 					LifecycleStatus:    lifecycleStatus,
-					ListingStatusID:    listingStatusID,
+					ListingStatusID:    listingStatus,
 				}
 
 				// Check if product exists by SKU (seller_sku in products table)
 				existingProduct, err := repo.GetProductBySKU(sku)
 				if err != nil {
-					log.Printf("Error checking existing product for SKU %s: %v\n", sku, err)
+					skuLogger.Error("error checking existing product", logging.Err(err))
 					errorCount++
 					continue
 				}
@@ -186,7 +360,7 @@ func StartCronJob(client *Client, repo inventory.InventoryRepository) {
 					// Update product details (product_name, upc, seller_sku)
 					err = repo.UpdateProduct(product)
 					if err != nil {
-						log.Printf("Error updating product for SKU %s: %v\n", sku, err)
+						skuLogger.Error("error updating product", logging.Err(err))
 						errorCount++
 						continue
 					}
@@ -194,97 +368,156 @@ func StartCronJob(client *Client, repo inventory.InventoryRepository) {
 					// Update Walmart product details (gtin, available_to_sell_qty, price)
 					err = repo.UpdateWmtProductDetail(product.ID, product)
 					if err != nil {
-						log.Printf("Error updating wmt_product_detail for SKU %s: %v\n", sku, err)
+						skuLogger.Error("error updating wmt_product_detail", logging.Err(err))
 						errorCount++
 						continue
 					}
 
-					// Update listing status if it has changed
-					if existingProduct.ListingStatusID != product.ListingStatusID {
-						err = repo.UpdateListingStatus(product.ID, product.ListingStatusID)
-						if err != nil {
-							log.Printf("Error updating listing status for SKU %s: %v\n", sku, err)
-						}
-					}
+					recordWalmartQuantity(ledger, product, shipNodes, runID, skuLogger)
 
-					// Search and update product image if it doesn't exist
-					if existingProduct.ProductImage == "" {
-						imageURL, err := client.ItemSearch(product.ProductName, product.UPC, product.GTIN)
-						if err != nil {
-							log.Printf("No image found for %s: %v\n", product.ProductName, err)
-						} else {
-							err = repo.InsertProductImage(product.GTIN, imageURL)
-							if err != nil {
-								log.Printf("Error updating product image for WPID %s: %v\n", product.WPID, err)
-							} else {
-								log.Printf("Updated product image for WPID %s\n", product.WPID)
-							}
-						}
+					if existingProduct.ListingStatusID == entities.ListingStatusDelisted {
+						listingReason = "restored after reappearing in the Walmart items response; " + listingReason
 					}
+					applyListingStatus(listings, product, listingReason, runID, skuLogger)
+					markSeen(listings, product, skuLogger)
 
 					updateCount++
 					successCount++
-					log.Printf("Updated product SKU %s - Available Qty: %d, Price: %.2f\n", sku, availableQty, product.Price)
+					skuLogger.Debug("updated product", slog.Int("available_qty", availableQty), slog.String("price", product.Price.String()))
 				} else {
 					// Product doesn't exist, insert new one
 					productID, err := repo.InsertProduct(product)
 					if err != nil {
-						log.Printf("Error inserting product for SKU %s: %v\n", sku, err)
+						skuLogger.Error("error inserting product", logging.Err(err))
 						errorCount++
 						continue
 					}
 
 					err = repo.InsertWmtProductDetail(productID, product)
 					if err != nil {
-						log.Printf("Error inserting wmt_product_detail for SKU %s: %v\n", sku, err)
+						skuLogger.Error("error inserting wmt_product_detail", logging.Err(err))
 						errorCount++
 						continue
 					}
 
-					// Set listing status for new product
-					err = repo.UpdateListingStatus(productID, product.ListingStatusID)
-					if err != nil {
-						log.Printf("Error setting listing status for new SKU %s: %v\n", sku, err)
-					}
+					product.ID = productID
+					recordWalmartQuantity(ledger, product, shipNodes, runID, skuLogger)
+
+					applyListingStatus(listings, product, listingReason, runID, skuLogger)
+					markSeen(listings, product, skuLogger)
 
-					// Search and insert product image
-					imageURL, err := client.ItemSearch(product.ProductName, product.UPC, product.GTIN)
-					if err != nil {
-						log.Printf("No image found for %s: %v\n", product.ProductName, err)
-					} else {
-						err = repo.InsertProductImage(product.GTIN, imageURL)
-						if err != nil {
-							log.Printf("Error inserting product image for SKU %s: %v\n", sku, err)
-						} else {
-							log.Printf("Inserted product image for SKU %s\n", sku)
-						}
-					}
 					insertCount++
 					successCount++
-					log.Printf("Inserted new product SKU %s - Available Qty: %d, Price: %.2f\n", sku, availableQty, product.Price)
+					skuLogger.Info("inserted new product", slog.Int("available_qty", availableQty), slog.String("price", product.Price.String()))
 				}
 				// Remove SKU from the map of DB products; remaining ones are not in the API response
 				delete(dbProductSKUs, sku)
 			}
 
-			// For all the products retrieved from the DB that are not in the response of the walmart API we should set the listing_status_id of 5.
-			for _, p := range dbProductSKUs {
-				log.Printf("Product %s not in Walmart response, setting listing_status_id to 5", p.SKU)
-				err := repo.UpdateListingStatus(p.ID, 5)
-				if err != nil {
-					log.Printf("Error updating listing status to 5 for SKU %s: %v\n", p.SKU, err)
-					errorCount++
+			// Products in the DB that are missing from the Walmart API response are delisted once
+			// their grace period runs out, unless so many are missing that the response is suspect.
+			// Products already delisted are expected to be missing, so they count on neither side.
+			listed := len(dbProducts)
+			for sku, p := range dbProductSKUs {
+				if p.ListingStatusID == entities.ListingStatusDelisted {
+					delete(dbProductSKUs, sku)
+					listed--
+				}
+			}
+			if listing.CircuitOpen(len(dbProductSKUs), listed, delistPolicy) {
+				runLogger.Warn("delisting skipped, too many products missing from the Walmart response",
+					slog.Int("missing", len(dbProductSKUs)),
+					slog.Int("total", listed),
+					slog.Float64("max_missing_percent", delistPolicy.MaxMissingPercent),
+				)
+			} else {
+				for _, p := range dbProductSKUs {
+					if markMissing(listings, p, delistPolicy, runID, runLogger.With(slog.String(logging.KeySKU, p.SKU))) {
+						missingCount++
+					} else {
+						errorCount++
+					}
 				}
 			}
 
-			log.Printf("Finished processing Walmart Inventory. Success: %d, Updates: %d, Inserts: %d, Errors: %d\n",
-				successCount, updateCount, insertCount, errorCount)
+			duration := finishRun(jobs, entities.JobCatalogSync, runID, start, nil)
+			metrics.CountSKUs(entities.JobCatalogSync, "inserted", insertCount)
+			metrics.CountSKUs(entities.JobCatalogSync, "updated", updateCount)
+			metrics.CountSKUs(entities.JobCatalogSync, "missing", missingCount)
+			metrics.CountSKUs(entities.JobCatalogSync, "failed", errorCount)
+			metrics.SetOutOfStock(metrics.OutOfStockListing, listedOutOfStock)
+			metrics.SetOutOfStock(metrics.OutOfStockNoQuantity, noQuantity)
+
+			runLogger.Info("catalog sync finished",
+				slog.Int("success", successCount),
+				slog.Int("updates", updateCount),
+				slog.Int("inserts", insertCount),
+				slog.Int("missing", missingCount),
+				slog.Int("errors", errorCount),
+				slog.Duration("duration", duration),
+			)
+
+			for _, hook := range hooks {
+				hook(runID)
+			}
 		}
 	}()
 }
 
+// markSeen resets the missed-sync counter of a product Walmart reported.
+func markSeen(listings listingRepository.ListingRepository, product entities.Product, logger *slog.Logger) {
+	if err := listings.MarkSeen(product.ID); err != nil {
+		logger.Error("error marking product as seen", logging.Err(err))
+	}
+}
+
+// markMissing counts a missed sync for the product and delists it once the
+// grace period is used up.
+func markMissing(listings listingRepository.ListingRepository, product entities.Product, policy entities.DelistPolicy, runID string, logger *slog.Logger) bool {
+	misses, err := listings.MarkMissing(product.ID)
+	if err != nil {
+		logger.Error("error counting missing sync", logging.Err(err))
+		return false
+	}
+
+	status, reason, delist := listing.Missing(misses, policy)
+	if !delist {
+		logger.Info("product missing from the Walmart response", slog.String("reason", reason), slog.Int("grace_runs", policy.GraceRuns))
+		return true
+	}
+
+	product.ListingStatusID = status
+	return applyListingStatus(listings, product, reason, runID, logger)
+}
+
+// applyListingStatus moves the product to its resolved listing status; the
+// repository records the transition when the status changes.
+func applyListingStatus(listings listingRepository.ListingRepository, product entities.Product, reason string, runID string, logger *slog.Logger) bool {
+	transition, err := listings.ApplyTransition(product.ID, product.ListingStatusID, reason, runID)
+	if err != nil {
+		logger.Error("error updating listing status", logging.Err(err))
+		return false
+	}
+	if transition != nil {
+		logger.Info("listing status changed",
+			slog.String("from", string(transition.From)),
+			slog.String("to", string(transition.To)),
+			slog.String("reason", reason),
+		)
+	}
+	return true
+}
+
+// recordWalmartQuantity stores the per-ship-node quantities reported by Walmart as ledger entries for the sync run.
+func recordWalmartQuantity(ledger stock.StockRepository, product entities.Product, shipNodes map[string]int, runID string, logger *slog.Logger) {
+	_, err := ledger.SyncShipNodes(product.ID, product.SKU, runID, shipNodes)
+	if err != nil {
+		logger.Error("error recording Walmart quantity", logging.Err(err))
+	}
+}
+
 // fetchWalmartItemsWithRetry attempts to fetch Walmart items with retry logic
-func fetchWalmartItemsWithRetry(client *Client, maxRetries int) (map[string]map[string]interface{}, error) {
+func fetchWalmartItemsWithRetry(client *Client, maxRetries int, logger *slog.Logger) (map[string]map[string]interface{}, error) {
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		productsMap, err := client.FetchWalmartItems()
@@ -292,10 +525,10 @@ func fetchWalmartItemsWithRetry(client *Client, maxRetries int) (map[string]map[
 			return productsMap, nil
 		}
 		lastErr = err
-		log.Printf("Attempt %d/%d failed to fetch Walmart items: %v\n", attempt, maxRetries, err)
 		if attempt < maxRetries {
 			backoff := time.Duration(1<<uint(attempt)) * time.Second
-			log.Printf("Retrying in %v...\n", backoff)
+			logger.Warn("failed to fetch Walmart items, retrying",
+				slog.Int("attempt", attempt), slog.Int("max_attempts", maxRetries), slog.Duration("backoff", backoff), logging.Err(err))
 			time.Sleep(backoff)
 		}
 	}
@@ -303,7 +536,7 @@ func fetchWalmartItemsWithRetry(client *Client, maxRetries int) (map[string]map[
 }
 
 // fetchWalmartInventoryWithRetry attempts to fetch Walmart inventory with retry logic
-func fetchWalmartInventoryWithRetry(client *Client, maxRetries int) (map[string]int, error) {
+func fetchWalmartInventoryWithRetry(client *Client, maxRetries int, logger *slog.Logger) (map[string]map[string]int, error) {
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		inventoryMap, err := client.FetchWalmartInventory()
@@ -311,11 +544,11 @@ func fetchWalmartInventoryWithRetry(client *Client, maxRetries int) (map[string]
 			return inventoryMap, nil
 		}
 		lastErr = err
-		log.Printf("Attempt %d/%d failed to fetch Walmart inventory: %v\n", attempt, maxRetries, err)
 		if attempt < maxRetries {
 			// Exponential backoff: 2^attempt seconds
 			backoff := time.Duration(1<<uint(attempt)) * time.Second
-			log.Printf("Retrying in %v...\n", backoff)
+			logger.Warn("failed to fetch Walmart inventory, retrying",
+				slog.Int("attempt", attempt), slog.Int("max_attempts", maxRetries), slog.Duration("backoff", backoff), logging.Err(err))
 			time.Sleep(backoff)
 		}
 	}
@@ -329,11 +562,11 @@ func getStringValue(m map[string]interface{}, key string) string {
 	return ""
 }
 
-func getFloatValue(m map[string]interface{}, key string) float64 {
-	if v, ok := m[key].(float64); ok {
+func getMoneyValue(m map[string]interface{}, key string) money.Money {
+	if v, ok := m[key].(money.Money); ok {
 		return v
 	}
-	return 0
+	return money.Money{}
 }
 
 func getIntValue(m map[string]interface{}, key string) int {
@@ -0,0 +1,223 @@
+package walmart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"walmart-inventory-manager/internal/money"
+)
+
+const (
+	ShipNodeTypeSellerFulfilled = "SellerFulfilled"
+	ShipNodeTypeWFSFulfilled    = "WFSFulfilled"
+)
+
+type OrderStats struct {
+	SKU         string `json:"sku"`
+	ProductName string `json:"productName"`
+	OrderCount  int    `json:"orderCount"`
+	UnitsSold   int    `json:"unitsSold"`
+}
+
+type Tax struct {
+	TaxName   string      `json:"taxName"`
+	TaxAmount money.Money `json:"taxAmount"`
+}
+
+type Charge struct {
+	ChargeType   string      `json:"chargeType"`
+	ChargeName   string      `json:"chargeName"`
+	ChargeAmount money.Money `json:"chargeAmount"`
+	Tax          *Tax        `json:"tax"`
+}
+
+type Quantity struct {
+	UnitOfMeasurement string `json:"unitOfMeasurement"`
+	Amount            string `json:"amount"`
+}
+
+type OrderItem struct {
+	SKU         string `json:"sku"`
+	ProductName string `json:"productName"`
+}
+
+type OrderLineStatus struct {
+	Status         string   `json:"status"`
+	StatusQuantity Quantity `json:"statusQuantity"`
+}
+
+type OrderLine struct {
+	LineNumber string    `json:"lineNumber"`
+	Item       OrderItem `json:"item"`
+	Charges    struct {
+		Charge []Charge `json:"charge"`
+	} `json:"charges"`
+	OrderLineQuantity Quantity `json:"orderLineQuantity"`
+	OrderLineStatuses struct {
+		OrderLineStatus []OrderLineStatus `json:"orderLineStatus"`
+	} `json:"orderLineStatuses"`
+}
+
+// Units returns the ordered quantity minus any cancelled units.
+func (l OrderLine) Units() int {
+	units, err := strconv.Atoi(l.OrderLineQuantity.Amount)
+	if err != nil {
+		return 0
+	}
+
+	for _, status := range l.OrderLineStatuses.OrderLineStatus {
+		if status.Status == "Cancelled" {
+			cancelled, _ := strconv.Atoi(status.StatusQuantity.Amount)
+			units -= cancelled
+		}
+	}
+	if units < 0 {
+		return 0
+	}
+	return units
+}
+
+// Revenue returns the item price charged for the line, prorated to the units
+// that were not cancelled. Item price charges in more than one currency are an
+// error.
+func (l OrderLine) Revenue() (money.Money, error) {
+	ordered, err := strconv.Atoi(l.OrderLineQuantity.Amount)
+	if err != nil || ordered == 0 {
+		return money.Money{}, nil
+	}
+
+	var total money.Money
+	for _, charge := range l.Charges.Charge {
+		if charge.ChargeName != "ItemPrice" {
+			continue
+		}
+		if total, err = total.Add(charge.ChargeAmount); err != nil {
+			return money.Money{}, fmt.Errorf("order line %s: %w", l.LineNumber, err)
+		}
+	}
+
+	return total.MulFrac(int64(l.Units()), int64(ordered)), nil
+}
+
+type Order struct {
+	PurchaseOrderID string `json:"purchaseOrderId"`
+	CustomerOrderID string `json:"customerOrderId"`
+	OrderDate       int64  `json:"orderDate"`
+	OrderLines      struct {
+		OrderLine []OrderLine `json:"orderLine"`
+	} `json:"orderLines"`
+	ShipNode struct {
+		Type string `json:"type"`
+	} `json:"shipNode"`
+}
+
+// Date returns the order date; Walmart reports it in epoch milliseconds.
+func (o Order) Date() time.Time {
+	return time.UnixMilli(o.OrderDate)
+}
+
+// FetchWalmartOrders returns the orders created between start and end for the
+// given ship node type.
+func FetchWalmartOrders(client *Client, start, end time.Time, shipNodeType string) ([]Order, error) {
+	accessToken, _, err := client.GetAccessToken()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("createdStartDate", start.Format(time.DateOnly))
+	params.Set("createdEndDate", end.Format(time.DateOnly))
+	params.Set("productInfo", "true")
+	params.Set("limit", "200")
+	if shipNodeType != "" {
+		params.Set("shipNodeType", shipNodeType)
+	}
+	baseURL := "https://marketplace.walmartapis.com/v3/orders?" + params.Encode()
+
+	var orders []Order
+	var nextCursor string
+
+	for {
+		urlEndpoint := baseURL
+		if nextCursor != "" {
+			urlEndpoint += "&nextCursor=" + url.QueryEscape(nextCursor)
+		}
+
+		req, _ := http.NewRequest("GET", urlEndpoint, nil)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("WM_SEC.ACCESS_TOKEN", accessToken)
+		client.correlate(req)
+		req.Header.Set("WM_SVC.NAME", "Walmart Marketplace")
+
+		resp, err := client.do(http.DefaultClient, endpointOrders, req)
+		if err != nil {
+			return nil, err
+		}
+
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			// Walmart answers 404 when no order matches the filters.
+			break
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.New("failed to fetch orders: " + string(body))
+		}
+
+		var data struct {
+			List struct {
+				Meta struct {
+					NextCursor string `json:"nextCursor"`
+				} `json:"meta"`
+				Elements struct {
+					Order []Order `json:"order"`
+				} `json:"elements"`
+			} `json:"list"`
+		}
+
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, data.List.Elements.Order...)
+
+		if data.List.Meta.NextCursor == "" {
+			break
+		}
+		nextCursor = data.List.Meta.NextCursor
+	}
+
+	return orders, nil
+}
+
+func FetchWalmartOrderStats(client *Client, start, end time.Time) ([]OrderStats, error) {
+	orders, err := FetchWalmartOrders(client, start, end, ShipNodeTypeWFSFulfilled)
+	if err != nil {
+		return nil, err
+	}
+
+	skuMap := make(map[string]*OrderStats)
+	var result []OrderStats
+	for _, order := range orders {
+		for _, line := range order.OrderLines.OrderLine {
+			entry, ok := skuMap[line.Item.SKU]
+			if !ok {
+				entry = &OrderStats{SKU: line.Item.SKU}
+				skuMap[line.Item.SKU] = entry
+			}
+			entry.ProductName = line.Item.ProductName
+			entry.OrderCount++
+			entry.UnitsSold += line.Units()
+		}
+	}
+
+	for _, stats := range skuMap {
+		result = append(result, *stats)
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package web
+
+import (
+	stdErrors "errors"
+	"log/slog"
+	"net/http"
+	"walmart-inventory-manager/platform/web/response"
+)
+
+// Error is implemented by errors that know which HTTP response they stand
+// for. Handlers return them and the router writes the response; any other
+// error becomes a 500 whose cause is logged but not shown to the client.
+type Error interface {
+	error
+	StatusCode() int
+	Code() string
+}
+
+// detailer is implemented by errors that carry data to help the client, such
+// as the field that failed validation.
+type detailer interface {
+	Details() interface{}
+}
+
+const internalErrorMessage = "internal server error"
+
+func writeError(w *responseWriter, r *http.Request, err error) {
+	requestID := RequestID(r)
+
+	if w.written {
+		logError(r, "error after the response was written", err)
+		return
+	}
+
+	body := response.ErrorBody{
+		Code:      "internal_error",
+		Message:   internalErrorMessage,
+		RequestID: requestID,
+	}
+	statusCode := http.StatusInternalServerError
+
+	var typed Error
+	if stdErrors.As(err, &typed) {
+		statusCode = typed.StatusCode()
+		body.Code = typed.Code()
+		body.Message = typed.Error()
+	}
+
+	var withDetails detailer
+	if stdErrors.As(err, &withDetails) {
+		body.Details = withDetails.Details()
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		logError(r, "error handling request", err)
+	}
+
+	response.ErrorJSON(w, statusCode, body)
+}
+
+func logError(r *http.Request, msg string, err error) {
+	slog.Default().ErrorContext(r.Context(), msg,
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("request_id", RequestID(r)),
+		slog.String("error", err.Error()),
+	)
+}
+
+// routeError answers requests that match no route.
+type routeError struct {
+	statusCode int
+	code       string
+	message    string
+}
+
+func (e routeError) Error() string   { return e.message }
+func (e routeError) StatusCode() int { return e.statusCode }
+func (e routeError) Code() string    { return e.code }
+
+func notFound(w http.ResponseWriter, r *http.Request) error {
+	return routeError{http.StatusNotFound, "not_found", "no route for " + r.Method + " " + r.URL.Path}
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) error {
+	return routeError{http.StatusMethodNotAllowed, "method_not_allowed", "method " + r.Method + " is not allowed on " + r.URL.Path}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+	"walmart-inventory-manager/platform/web"
+)
+
+type AccessLogConfig struct {
+	Logger *slog.Logger
+	// SkipPaths are not logged, for endpoints polled by probes.
+	SkipPaths []string
+}
+
+// AccessLog logs one structured line per request. Server errors are logged
+// at error level and client errors as warnings.
+func AccessLog(cfg AccessLogConfig) func(http.Handler) http.Handler {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	skip := make(map[string]bool, len(cfg.SkipPaths))
+	for _, path := range cfg.SkipPaths {
+		skip[path] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			rec := newRecorder(w)
+			next.ServeHTTP(rec, r)
+
+			level := slog.LevelInfo
+			switch status := rec.Status(); {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+
+			cfg.Logger.LogAttrs(r.Context(), level, "request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", rec.Status()),
+				slog.Int64("bytes", rec.bytes),
+				slog.Duration("duration", time.Since(start)),
+				slog.String("request_id", web.RequestID(r)),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+type BodyLimitConfig struct {
+	MaxBytes int64
+	// MaxMultipartBytes applies to multipart uploads, which carry files and
+	// need more room than JSON bodies.
+	MaxMultipartBytes int64
+}
+
+// BodyLimit caps request bodies. Bodies declared too large are refused with a
+// 413 right away; others are cut off at the limit, which the handler sees as
+// an *http.MaxBytesError when it reads past it.
+func BodyLimit(cfg BodyLimitConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := cfg.MaxBytes
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+				limit = cfg.MaxMultipartBytes
+			}
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.ContentLength > limit {
+				writeError(w, r, http.StatusRequestEntityTooLarge, "payload_too_large", "request body is larger than "+strconv.FormatInt(limit, 10)+" bytes")
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
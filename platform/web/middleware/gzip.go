@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type GzipConfig struct {
+	// Level is a compress/gzip level; 0 means gzip.DefaultCompression.
+	Level int
+	// ContentTypes are the compressible media types, matched by prefix.
+	// Defaults to JSON, CSV and text.
+	ContentTypes []string
+}
+
+var defaultCompressibleTypes = []string{"application/json", "text/"}
+
+// Gzip compresses responses for clients that accept it. Only compressible
+// content types are touched; images are already compressed and are served
+// with range support, which compression would break.
+func Gzip(cfg GzipConfig) func(http.Handler) http.Handler {
+	if cfg.Level == 0 {
+		cfg.Level = gzip.DefaultCompression
+	}
+	if len(cfg.ContentTypes) == 0 {
+		cfg.ContentTypes = defaultCompressibleTypes
+	}
+
+	pool := sync.Pool{New: func() interface{} {
+		zw, _ := gzip.NewWriterLevel(nil, cfg.Level)
+		return zw
+	}}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r) || r.Header.Get("Range") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipWriter{ResponseWriter: w, cfg: cfg, pool: &pool}
+			defer gw.close()
+			next.ServeHTTP(gw, r)
+		})
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		encoding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if strings.EqualFold(strings.TrimSpace(encoding), "gzip") {
+			return strings.ReplaceAll(params, " ", "") != "q=0"
+		}
+	}
+	return false
+}
+
+// gzipWriter decides whether to compress when the status is written, once
+// the handler has set the content type.
+type gzipWriter struct {
+	http.ResponseWriter
+	cfg         GzipConfig
+	pool        *sync.Pool
+	zw          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	if w.compressible(status) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Del("Content-Length")
+		w.zw = w.pool.Get().(*gzip.Writer)
+		w.zw.Reset(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gzipWriter) compressible(status int) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := w.Header().Get("Content-Type")
+	for _, prefix := range w.cfg.ContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.zw != nil {
+		return w.zw.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *gzipWriter) Flush() {
+	if w.zw != nil {
+		w.zw.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *gzipWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *gzipWriter) close() {
+	if w.zw == nil {
+		return
+	}
+	w.zw.Close()
+	w.pool.Put(w.zw)
+	w.zw = nil
+}
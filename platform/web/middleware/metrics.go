@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+	"walmart-inventory-manager/platform/web"
+)
+
+type MetricsConfig struct {
+	// Observe is called once per request with the pattern of the route that
+	// matched, or "" when none did.
+	Observe func(method, route string, status int, duration time.Duration)
+}
+
+// Metrics reports every request to the configured observer, keeping the
+// platform free of any particular metrics library.
+func Metrics(cfg MetricsConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.Observe == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := newRecorder(w)
+			next.ServeHTTP(rec, r)
+
+			cfg.Observe(r.Method, web.RoutePattern(r), rec.Status(), time.Since(start))
+		})
+	}
+}
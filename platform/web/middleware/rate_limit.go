@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained rate per client; 0 turns limiting off.
+	RequestsPerSecond float64
+	// Burst is how many requests a client may make at once.
+	Burst int
+	// KeyFunc names the client a request belongs to. Defaults to the client IP.
+	KeyFunc func(*http.Request) string
+}
+
+// sweepInterval is how often idle clients are forgotten.
+const sweepInterval = time.Minute
+
+// RateLimit gives every client a token bucket and answers 429 with a
+// Retry-After header once it is empty.
+func RateLimit(cfg RateLimitConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if cfg.RequestsPerSecond <= 0 {
+			return next
+		}
+		if cfg.KeyFunc == nil {
+			cfg.KeyFunc = func(r *http.Request) string { return ClientIP(r, false) }
+		}
+		limiter := newRateLimiter(cfg.RequestsPerSecond, cfg.Burst)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, wait := limiter.allow(cfg.KeyFunc(r), time.Now())
+			if !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				writeError(w, r, http.StatusTooManyRequests, "rate_limited", "too many requests")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientIP returns the address the request came from. Behind a trusted proxy
+// that is the first address in X-Forwarded-For; otherwise the header is
+// ignored, since anyone can send it.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{
+		rate:    rate,
+		burst:   math.Max(float64(burst), 1),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token from the client's bucket, or says how long until one
+// is available.
+func (l *rateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops buckets that have refilled completely; a new bucket for the
+// same client would be identical.
+func (l *rateLimiter) sweep(now time.Time) {
+	full := time.Duration(l.burst / l.rate * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= full {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"walmart-inventory-manager/platform/web"
+	"walmart-inventory-manager/platform/web/response"
+)
+
+// recorder keeps the status and size of a response for the middleware that
+// inspects it after the handler ran.
+type recorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func newRecorder(w http.ResponseWriter) *recorder {
+	if rec, ok := w.(*recorder); ok {
+		return rec
+	}
+	return &recorder{ResponseWriter: w}
+}
+
+func (w *recorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *recorder) written() bool {
+	return w.status != 0
+}
+
+// Status is the status sent, or 200 when the handler wrote nothing.
+func (w *recorder) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *recorder) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *recorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// writeError answers with the same envelope the router uses for handler errors.
+func writeError(w http.ResponseWriter, r *http.Request, statusCode int, code, message string) {
+	response.ErrorJSON(w, statusCode, response.ErrorBody{
+		Code:      code,
+		Message:   message,
+		RequestID: web.RequestID(r),
+	})
+}
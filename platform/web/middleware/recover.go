@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"walmart-inventory-manager/platform/web"
+)
+
+// Recover turns a panicking handler into a 500 instead of a dropped
+// connection, and logs the panic with its stack trace.
+func Recover() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := newRecorder(w)
+
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				// ErrAbortHandler is how handlers deliberately abort a response.
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				slog.Default().ErrorContext(r.Context(), "panic serving request",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.String("request_id", web.RequestID(r)),
+					slog.String("panic", fmt.Sprint(p)),
+					slog.String("stack", string(debug.Stack())),
+				)
+				if !rec.written() {
+					writeError(rec, r, http.StatusInternalServerError, "internal_error", "internal server error")
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
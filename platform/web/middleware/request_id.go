@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"walmart-inventory-manager/platform/web"
+
+	"github.com/google/uuid"
+)
+
+const maxRequestIDLength = 128
+
+type RequestIDConfig struct {
+	// Header carries the ID in both directions. Defaults to X-Request-ID.
+	Header string
+	// TrustIncoming keeps a well-formed ID sent by a client or proxy, so one
+	// request can be followed across services.
+	TrustIncoming bool
+}
+
+// RequestID gives every request an ID, available through web.RequestID and
+// echoed in the response header and in error bodies.
+func RequestID(cfg RequestIDConfig) func(http.Handler) http.Handler {
+	if cfg.Header == "" {
+		cfg.Header = "X-Request-ID"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(cfg.Header)
+			if !cfg.TrustIncoming || !validRequestID(id) {
+				id = uuid.NewString()
+			}
+
+			w.Header().Set(cfg.Header, id)
+			next.ServeHTTP(w, web.WithRequestID(r, id))
+		})
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,20 @@
+package web
+
+import (
+	"context"
+	"net/http"
+)
+
+type requestIDKey struct{}
+
+// RequestID returns the ID the request ID middleware gave the request, or ""
+// when that middleware is not in use.
+func RequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID returns a copy of r carrying the request ID.
+func WithRequestID(r *http.Request, id string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+}
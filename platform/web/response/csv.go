@@ -0,0 +1,115 @@
+package response
+
+import (
+	"encoding/csv"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CSV writes the rows as a CSV attachment. Cells are escaped with csvCell.
+// The status is already sent when a write fails, so the error is only logged.
+func CSV(w http.ResponseWriter, filename string, header []string, rows [][]string) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write(csvRow(header))
+	for _, row := range rows {
+		if err := writer.Write(csvRow(row)); err != nil {
+			break
+		}
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		slog.Error("error writing csv response", slog.String("file", filename), slog.String("error", err.Error()))
+	}
+}
+
+// csvCell escapes a cell that a spreadsheet would read as a formula, such as a
+// product name starting with "=", by prefixing it with a single quote. Numbers
+// like "-5.00" are left alone so they stay numbers.
+func csvCell(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+func csvRow(row []string) []string {
+	escaped := make([]string, len(row))
+	for i, value := range row {
+		escaped[i] = csvCell(value)
+	}
+	return escaped
+}
+
+// WantsCSV reports whether the client asked for CSV rather than JSON, through
+// ?format=csv or the Accept header. CSV wins when the client prefers text/csv
+// over application/json, or names it more specifically at the same quality,
+// so "text/csv, */*" gets CSV and "*/*" keeps getting JSON.
+func WantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "csv"
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return false
+	}
+
+	csvQuality, csvSpecificity := acceptQuality(accept, "text", "csv")
+	jsonQuality, jsonSpecificity := acceptQuality(accept, "application", "json")
+	if csvQuality == 0 {
+		return false
+	}
+	return csvQuality > jsonQuality || (csvQuality == jsonQuality && csvSpecificity > jsonSpecificity)
+}
+
+// acceptQuality finds the media range of the Accept header that best matches
+// the type and returns its quality, together with how specific the range is:
+// 2 for type/subtype, 1 for type/* and 0 for */*. A type no range matches has
+// a quality of 0.
+func acceptQuality(accept, mediaType, subtype string) (float64, int) {
+	quality, specificity := 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		rangeType, rangeSubtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+		if !ok {
+			continue
+		}
+
+		var s int
+		switch {
+		case rangeType == mediaType && rangeSubtype == subtype:
+			s = 2
+		case rangeType == mediaType && rangeSubtype == "*":
+			s = 1
+		case rangeType == "*" && rangeSubtype == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s < specificity {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		quality, specificity = q, s
+	}
+	return quality, specificity
+}
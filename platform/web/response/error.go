@@ -4,23 +4,40 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
-type errorResponse struct {
-	Status  string `json:"status"`
-	Message string `json:"message"`
+// ErrorBody is the envelope of every error response. Code is a stable,
+// machine-readable name for the error; Message is meant for people.
+type ErrorBody struct {
+	Status    string      `json:"status"`
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	RequestID string      `json:"requestId,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
 }
 
 func Error(w http.ResponseWriter, statusCode int, message string) {
+	ErrorJSON(w, statusCode, ErrorBody{Message: message})
+}
+
+func Errorf(w http.ResponseWriter, statusCode int, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	Error(w, statusCode, message)
+}
+
+// ErrorJSON writes the error envelope. Status is filled in from the status
+// code, and Code too when it is empty.
+func ErrorJSON(w http.ResponseWriter, statusCode int, body ErrorBody) {
 	defaultStatusCode := http.StatusInternalServerError
 
 	if statusCode > 299 && statusCode < 600 {
 		defaultStatusCode = statusCode
 	}
 
-	body := errorResponse{
-		Status:  http.StatusText(defaultStatusCode),
-		Message: message,
+	body.Status = http.StatusText(defaultStatusCode)
+	if body.Code == "" {
+		body.Code = strings.ReplaceAll(strings.ToLower(body.Status), " ", "_")
 	}
 
 	bytes, err := json.Marshal(body)
@@ -33,8 +50,3 @@ func Error(w http.ResponseWriter, statusCode int, message string) {
 	w.WriteHeader(defaultStatusCode)
 	w.Write(bytes)
 }
-
-func Errorf(w http.ResponseWriter, statusCode int, format string, args ...interface{}) {
-	message := fmt.Sprintf(format, args...)
-	Error(w, statusCode, message)
-}
@@ -0,0 +1,31 @@
+package web
+
+import "net/http"
+
+// responseWriter records whether a response has been started, so an error
+// returned afterwards is not written on top of it.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	written bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.written {
+		w.status = status
+		w.written = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
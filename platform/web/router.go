@@ -7,9 +7,13 @@ import (
 )
 
 func NewRouter() *Router {
+	rt := chi.NewRouter()
+	rt.NotFound(handlerAdapter(notFound, ""))
+	rt.MethodNotAllowed(handlerAdapter(methodNotAllowed, ""))
+
 	return &Router{
 		routerGroup: RouterGroup{
-			rt: chi.NewRouter(),
+			rt: rt,
 		},
 	}
 }
@@ -22,8 +26,15 @@ func (r *Router) Use(md ...func(HandleFunc) HandleFunc) {
 	r.routerGroup.Use(md...)
 }
 
-func (r *Router) Handle(method string, path string, hd HandleFunc, md ...func(HandleFunc) HandleFunc) {
-	r.routerGroup.Handle(method, path, hd, md...)
+// UseHTTP adds middleware that runs before routing, for every request
+// including those that match no route. It must be called before any route is
+// registered.
+func (r *Router) UseHTTP(md ...func(http.Handler) http.Handler) {
+	r.routerGroup.rt.Use(md...)
+}
+
+func (r *Router) Handle(method string, path string, permission string, hd HandleFunc, md ...func(HandleFunc) HandleFunc) {
+	r.routerGroup.Handle(method, path, permission, hd, md...)
 }
 
 func (r *Router) Route(path string, fn func(rg *RouterGroup)) {
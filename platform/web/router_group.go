@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -8,6 +9,35 @@ import (
 
 type HandleFunc func(w http.ResponseWriter, r *http.Request) (err error)
 
+// WrapHandler adapts a plain http.Handler to a route handler. The handler
+// writes its own response, errors included.
+func WrapHandler(h http.Handler) HandleFunc {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		h.ServeHTTP(w, r)
+		return nil
+	}
+}
+
+type permissionKey struct{}
+
+// RoutePermission returns the permission the matched route was registered
+// with. The router does not interpret it; middleware does.
+func RoutePermission(r *http.Request) string {
+	permission, _ := r.Context().Value(permissionKey{}).(string)
+	return permission
+}
+
+// RoutePattern returns the pattern of the route that matched, such as
+// "/api/v1/inventory/{sku}", or "" when none did. Middleware running before
+// routing can read it once the handler has returned.
+func RoutePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
+
 type RouterGroup struct {
 	rt *chi.Mux
 	md []func(HandleFunc) HandleFunc
@@ -18,11 +48,13 @@ func (rg *RouterGroup) Use(md ...func(HandleFunc) HandleFunc) {
 	(*rg).md = append((*rg).md, md...)
 }
 
-func (rg *RouterGroup) Handle(method string, path string, hd HandleFunc, md ...func(HandleFunc) HandleFunc) {
+// Handle registers a route. Every route declares the permission needed to call
+// it, which is available to middleware through RoutePermission.
+func (rg *RouterGroup) Handle(method string, path string, permission string, hd HandleFunc, md ...func(HandleFunc) HandleFunc) {
 	hd = handlerChain(hd, md...)
 	hd = handlerChain(hd, (*rg).md...)
 
-	handler := handlerAdapter(hd)
+	handler := handlerAdapter(hd, permission)
 	path = (*rg).basePath + path
 
 	(*rg).rt.Method(method, path, handler)
@@ -38,19 +70,24 @@ func (rg *RouterGroup) Route(path string, fn func(rg *RouterGroup)) {
 	fn(subRouter)
 }
 
+// handlerChain wraps hd so that the first middleware runs first.
 func handlerChain(hd HandleFunc, md ...func(HandleFunc) HandleFunc) HandleFunc {
-	for _, m := range md {
-		hd = m(hd)
+	for i := len(md) - 1; i >= 0; i-- {
+		hd = md[i](hd)
 	}
 
 	return hd
 }
 
-func handlerAdapter(hd HandleFunc) http.HandlerFunc {
+// handlerAdapter runs a handler with the route permission in its context and
+// turns the error it returns into the response.
+func handlerAdapter(hd HandleFunc, permission string) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		err := hd(w, req)
-		if err != nil {
-			println(err.Error())
+		req = req.WithContext(context.WithValue(req.Context(), permissionKey{}, permission))
+
+		rw := &responseWriter{ResponseWriter: w}
+		if err := hd(rw, req); err != nil {
+			writeError(rw, req, err)
 		}
 	}
-}
\ No newline at end of file
+}